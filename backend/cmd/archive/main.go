@@ -0,0 +1,141 @@
+// backend/cmd/archive/main.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/archive"
+	"github.com/dimasrizkyfebrian/coursify/internal/database"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+)
+
+const usage = `Usage:
+  archive export -course <course-id> -out <file.zip>
+  archive import -file <file.zip> -instructor <email> [-format archive|cartridge] [-dry-run]`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	// Load env file
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using environment variables from runtime")
+	}
+
+	switch os.Args[1] {
+	case "export":
+		runExport(os.Args[2:])
+	case "import":
+		runImport(os.Args[2:])
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	courseID := fs.String("course", "", "ID of the course to export")
+	out := fs.String("out", "", "Path of the zip file to write")
+	fs.Parse(args)
+
+	if *courseID == "" || *out == "" {
+		log.Fatal("-course and -out are required")
+	}
+
+	db := database.ConnectDB()
+	courseRepo := repository.NewCourseRepository(db)
+
+	course, err := courseRepo.GetCourseByID(*courseID)
+	if err != nil || course == nil {
+		log.Fatalf("Course %s not found", *courseID)
+	}
+	materials, err := courseRepo.GetMaterialsByCourseID(course.ID)
+	if err != nil {
+		log.Fatalf("Could not fetch materials: %v", err)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Could not create %s: %v", *out, err)
+	}
+	defer f.Close()
+
+	if err := archive.Export(f, *course, materials); err != nil {
+		os.Remove(*out)
+		log.Fatalf("Could not export course: %v", err)
+	}
+
+	fmt.Printf("Exported %q with %d materials to %s\n", course.Title, len(materials), *out)
+}
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "Path of the zip file to import")
+	instructorEmail := fs.String("instructor", "", "Email of the instructor who will own the course")
+	format := fs.String("format", "archive", "Format of the file: 'archive' for Coursify exports, 'cartridge' for IMS Common Cartridge")
+	dryRun := fs.Bool("dry-run", false, "Validate the archive and print the report without importing")
+	fs.Parse(args)
+
+	if *file == "" || *instructorEmail == "" {
+		log.Fatal("-file and -instructor are required")
+	}
+
+	read := archive.Read
+	switch *format {
+	case "archive":
+	case "cartridge":
+		read = archive.ReadCommonCartridge
+	default:
+		log.Fatalf("Unknown format %q", *format)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Could not open %s: %v", *file, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		log.Fatalf("Could not read %s: %v", *file, err)
+	}
+
+	a, report := read(f, info.Size())
+	printReport(report)
+	if !report.Valid {
+		os.Exit(1)
+	}
+	if *dryRun {
+		return
+	}
+
+	db := database.ConnectDB()
+	userRepo := repository.NewUserRepository(db)
+	courseRepo := repository.NewCourseRepository(db)
+
+	instructor, err := userRepo.GetUserByEmail(*instructorEmail)
+	if err != nil || instructor == nil || instructor.Role != "instructor" || instructor.Status != "active" {
+		log.Fatalf("Active instructor %s not found", *instructorEmail)
+	}
+
+	course, err := archive.Import(courseRepo, a, instructor.ID)
+	if err != nil {
+		log.Fatalf("Could not import course: %v", err)
+	}
+
+	fmt.Printf("Imported %q as draft course %s for %s\n", course.Title, course.ID, instructor.Email)
+}
+
+func printReport(report *archive.Report) {
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	_ "github.com/dimasrizkyfebrian/coursify/docs"
 	"github.com/go-chi/chi/v5"
@@ -16,6 +17,8 @@ import (
 	"github.com/dimasrizkyfebrian/coursify/internal/database"
 	"github.com/dimasrizkyfebrian/coursify/internal/handler"
 	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/notify"
+	"github.com/dimasrizkyfebrian/coursify/internal/payment"
 	"github.com/dimasrizkyfebrian/coursify/internal/repository"
 )
 
@@ -55,9 +58,9 @@ func main() {
 	// CORS configuration
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5173"}, // Allow port 5173
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Tus-Resumable", "Upload-Length", "Upload-Metadata", "Upload-Offset"},
+		ExposedHeaders:   []string{"Link", "X-Total-Count", "Location", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size", "Upload-Offset", "Upload-Length", "Upload-Expires"},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
@@ -70,7 +73,30 @@ func main() {
 	userRepo := repository.NewUserRepository(db)
 	userHandler := handler.NewUserHandler(userRepo)
 	courseRepo := repository.NewCourseRepository(db)
-	courseHandler := handler.NewCourseHandler(courseRepo)
+	// Text materials written before their HTML was stored are rendered once in the background
+	go func() {
+		rendered, err := courseRepo.RenderMissingTextHTML()
+		if err != nil {
+			log.Printf("Error rendering material text: %v", err)
+		} else if rendered > 0 {
+			log.Printf("Rendered the HTML of %d text materials", rendered)
+		}
+	}()
+	// Emails go through SMTP_HOST when it is set and are only logged otherwise
+	courseHandler := handler.NewCourseHandler(courseRepo, userRepo, notify.FromEnv())
+	orderRepo := repository.NewOrderRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	reviewHandler := handler.NewReviewHandler(reviewRepo, courseRepo)
+	discussionRepo := repository.NewDiscussionRepository(db)
+	discussionHandler := handler.NewDiscussionHandler(discussionRepo, courseRepo)
+	certificateRepo := repository.NewCertificateRepository(db)
+	certificateHandler := handler.NewCertificateHandler(certificateRepo)
+	uploadRepo := repository.NewUploadRepository(db)
+	uploadHandler := handler.NewUploadHandler(uploadRepo, courseRepo)
+	// Abandoned resumable uploads are removed once they expire
+	go uploadHandler.PurgeExpiredUploads(time.Hour)
+	// The fake provider refuses every webhook until FAKE_PAYMENT_SECRET is set
+	paymentHandler := handler.NewPaymentHandler(orderRepo, courseRepo, payment.NewFakeProvider(os.Getenv("FAKE_PAYMENT_SECRET")))
 
 	// --- Swagger Documentation ---
 	r.Get("/swagger/*", httpSwagger.Handler(
@@ -81,6 +107,12 @@ func main() {
 	r.With(middleware.RateLimitMiddleware).Post("/api/register", userHandler.Register)
 	r.Post("/api/login", userHandler.Login)
 	r.Get("/api/courses", courseHandler.GetAllCoursesPublic)
+	r.Get("/api/courses/{id}", courseHandler.GetCourseDetailsPublic)
+	r.Get("/api/courses/{id}/cohorts", courseHandler.GetCourseCohortsPublic)
+	r.Get("/api/courses/{id}/reviews", reviewHandler.GetCourseReviews)
+	r.Get("/api/courses/{id}/materials/{materialId}/preview", courseHandler.GetPreviewMaterial)
+	r.Post("/api/payments/webhook/{provider}", paymentHandler.PaymentWebhook)
+	r.Get("/api/certificates/{serial}", certificateHandler.VerifyCertificate)
 
 	// --- Protected Admin Routes ---
 	r.Group(func(r chi.Router) {
@@ -96,6 +128,15 @@ func main() {
 	r.Put("/api/admin/users/{id}/reject", userHandler.RejectUser)
 	r.Put("/api/admin/users/{id}", userHandler.UpdateUser)
 	r.Delete("/api/admin/users/{id}", userHandler.DeleteUser)
+	r.Put("/api/admin/courses/{id}/template", courseHandler.SetCourseTemplate)
+	r.Post("/api/admin/courses/import", courseHandler.AdminImportCourse)
+	r.Get("/api/admin/courses/{id}/reviews", reviewHandler.AdminGetCourseReviews)
+	r.Put("/api/admin/reviews/{reviewId}/hidden", reviewHandler.SetReviewHidden)
+	r.Put("/api/admin/certificates/{serial}/revoke", certificateHandler.RevokeCertificate)
+	r.Get("/api/admin/coupons", paymentHandler.GetAllCoupons)
+	r.Post("/api/admin/coupons", paymentHandler.AdminCreateCoupon)
+	r.Delete("/api/admin/coupons/{couponId}", paymentHandler.AdminDeleteCoupon)
+	r.Get("/api/admin/coupons/{couponId}/redemptions", paymentHandler.AdminGetCouponRedemptions)
 	})
 
 	// --- Protected Instructor Routes ---
@@ -107,12 +148,72 @@ func main() {
     r.Post("/api/instructor/courses", courseHandler.CreateCourse)
 	r.Put("/api/instructor/courses/{id}", courseHandler.UpdateCourse)
 	r.Get("/api/instructor/courses/{id}", courseHandler.GetMyCourseDetails)
+	r.Post("/api/instructor/courses/{id}/duplicate", courseHandler.DuplicateCourse)
+	r.Put("/api/instructor/courses/{id}/draft", courseHandler.SetCourseDraft)
+	r.Get("/api/instructor/templates", courseHandler.GetCourseTemplates)
+	r.Get("/api/instructor/courses/{id}/export", courseHandler.ExportCourse)
+	r.Post("/api/instructor/courses/import", courseHandler.ImportCourse)
+	r.Post("/api/instructor/courses/import-cartridge", courseHandler.ImportCommonCartridge)
 	r.Post("/api/instructor/courses/{id}/materials", courseHandler.AddMaterialToCourse)
 	r.Get("/api/instructor/courses/{id}/materials", courseHandler.GetMaterialsByCourseID)
+	r.Put("/api/instructor/courses/{id}/materials", courseHandler.ReorderMaterials)
 	r.Put("/api/instructor/courses/{id}/materials/{materialId}", courseHandler.UpdateMaterial)
 	r.Delete("/api/instructor/courses/{id}/materials/{materialId}", courseHandler.DeleteMaterial)
+	r.Put("/api/instructor/courses/{id}/materials/{materialId}/preview", courseHandler.SetMaterialPreview)
+	r.Put("/api/instructor/courses/{id}/materials/{materialId}/section", courseHandler.SetMaterialSection)
+	r.Put("/api/instructor/courses/{id}/materials/{materialId}/position", courseHandler.MoveMaterial)
+	r.Put("/api/instructor/courses/{id}/materials/{materialId}/release", courseHandler.SetMaterialRelease)
+	r.Post("/api/instructor/markdown/preview", courseHandler.PreviewMarkdown)
+	r.Get("/api/instructor/courses/{id}/sections", courseHandler.GetSections)
+	r.Post("/api/instructor/courses/{id}/sections", courseHandler.CreateSection)
+	r.Put("/api/instructor/courses/{id}/sections", courseHandler.ReorderSections)
+	r.Put("/api/instructor/courses/{id}/sections/{sectionId}", courseHandler.UpdateSection)
+	r.Delete("/api/instructor/courses/{id}/sections/{sectionId}", courseHandler.DeleteSection)
 	r.Post("/api/instructor/courses/{id}/upload-cover", courseHandler.UploadCourseCover)
 	r.Post("/api/instructor/courses/{id}/materials/upload-pdf", courseHandler.UploadPdfMaterial)
+	r.Post("/api/instructor/courses/{id}/materials/upload-video", courseHandler.UploadVideoMaterial)
+	r.Options("/api/instructor/uploads", uploadHandler.TusOptions)
+	r.Post("/api/instructor/uploads", uploadHandler.CreateUpload)
+	r.Head("/api/instructor/uploads/{uploadId}", uploadHandler.GetUploadOffset)
+	r.Get("/api/instructor/uploads/{uploadId}", uploadHandler.GetUpload)
+	r.Patch("/api/instructor/uploads/{uploadId}", uploadHandler.PatchUpload)
+	r.Delete("/api/instructor/uploads/{uploadId}", uploadHandler.DeleteUpload)
+	r.Get("/api/instructor/courses/{id}/staff", courseHandler.GetCourseStaff)
+	r.Post("/api/instructor/courses/{id}/staff", courseHandler.AddCourseStaff)
+	r.Delete("/api/instructor/courses/{id}/staff/{userId}", courseHandler.RemoveCourseStaff)
+	r.Get("/api/instructor/courses/{id}/prerequisites", courseHandler.GetPrerequisites)
+	r.Post("/api/instructor/courses/{id}/prerequisites", courseHandler.AddPrerequisite)
+	r.Delete("/api/instructor/courses/{id}/prerequisites/{prerequisiteId}", courseHandler.RemovePrerequisite)
+	r.Put("/api/instructor/courses/{id}/capacity", courseHandler.SetCourseCapacity)
+	r.Get("/api/instructor/courses/{id}/waitlist", courseHandler.GetWaitlist)
+	r.Put("/api/instructor/courses/{id}/waitlist", courseHandler.ReorderWaitlist)
+	r.Get("/api/instructor/courses/{id}/cohorts", courseHandler.GetCourseCohorts)
+	r.Post("/api/instructor/courses/{id}/cohorts", courseHandler.CreateCohort)
+	r.Put("/api/instructor/courses/{id}/cohorts/{cohortId}", courseHandler.UpdateCohort)
+	r.Delete("/api/instructor/courses/{id}/cohorts/{cohortId}", courseHandler.DeleteCohort)
+	r.Get("/api/instructor/courses/{id}/roster", courseHandler.GetCourseRoster)
+	r.Post("/api/instructor/courses/{id}/roster", courseHandler.AddStudentToCourse)
+	r.Delete("/api/instructor/courses/{id}/roster/{userId}", courseHandler.RemoveStudentFromCourse)
+	r.Get("/api/instructor/courses/{id}/enrollment-history", courseHandler.GetEnrollmentHistory)
+	r.Put("/api/instructor/courses/{id}/enrollment-mode", courseHandler.SetEnrollmentMode)
+	r.Put("/api/instructor/courses/{id}/price", courseHandler.SetCoursePrice)
+	r.Put("/api/instructor/courses/{id}/reviews/{reviewId}/reply", reviewHandler.ReplyToReview)
+	r.Get("/api/instructor/questions/unanswered", discussionHandler.GetUnansweredQuestions)
+	r.Get("/api/instructor/courses/{id}/announcements", courseHandler.GetCourseAnnouncements)
+	r.Post("/api/instructor/courses/{id}/announcements", courseHandler.CreateAnnouncement)
+	r.Put("/api/instructor/courses/{id}/announcements/{announcementId}", courseHandler.UpdateAnnouncement)
+	r.Put("/api/instructor/courses/{id}/announcements/{announcementId}/pin", courseHandler.SetAnnouncementPinned)
+	r.Delete("/api/instructor/courses/{id}/announcements/{announcementId}", courseHandler.DeleteAnnouncement)
+	r.Get("/api/instructor/courses/{id}/coupons", paymentHandler.GetCourseCoupons)
+	r.Post("/api/instructor/courses/{id}/coupons", paymentHandler.CreateCourseCoupon)
+	r.Delete("/api/instructor/courses/{id}/coupons/{couponId}", paymentHandler.DeleteCourseCoupon)
+	r.Get("/api/instructor/courses/{id}/coupons/{couponId}/redemptions", paymentHandler.GetCourseCouponRedemptions)
+	r.Get("/api/instructor/courses/{id}/enrollment-requests", courseHandler.GetEnrollmentRequests)
+	r.Post("/api/instructor/courses/{id}/enrollment-requests/{requestId}/approve", courseHandler.ApproveEnrollmentRequest)
+	r.Post("/api/instructor/courses/{id}/enrollment-requests/{requestId}/reject", courseHandler.RejectEnrollmentRequest)
+	r.Get("/api/instructor/courses/{id}/access-codes", courseHandler.GetAccessCodes)
+	r.Post("/api/instructor/courses/{id}/access-codes", courseHandler.CreateAccessCode)
+	r.Delete("/api/instructor/courses/{id}/access-codes/{codeId}", courseHandler.DeleteAccessCode)
 	})
 
 	// --- Protected Student Routes ---
@@ -121,14 +222,44 @@ func main() {
     r.Use(middleware.StudentOnly)
 
     r.Post("/api/courses/{id}/enroll", courseHandler.EnrollInCourse)
+	r.Delete("/api/courses/{id}/enroll", courseHandler.LeaveCourse)
+	r.Post("/api/courses/{id}/enrollment-requests", courseHandler.RequestEnrollment)
+	r.Post("/api/courses/{id}/redeem", courseHandler.RedeemAccessCode)
+	r.Post("/api/courses/{id}/checkout", paymentHandler.Checkout)
+	r.Put("/api/courses/{id}/review", reviewHandler.SaveReview)
+	r.Delete("/api/courses/{id}/review", reviewHandler.DeleteReview)
+	r.Get("/api/student/orders", paymentHandler.GetMyOrders)
 	r.Get("/api/student/my-courses", courseHandler.GetMyEnrolledCourses)
 	r.Get("/api/student/courses/{id}", courseHandler.GetEnrolledCourseDetails)
+	r.Post("/api/student/courses/{id}/materials/{materialId}/complete", courseHandler.CompleteMaterial)
+	r.Get("/api/student/courses/{id}/certificate", certificateHandler.DownloadCertificate)
+	r.Get("/api/student/certificates", certificateHandler.GetMyCertificates)
+	r.Post("/api/student/courses/{id}/announcements/{announcementId}/read", courseHandler.MarkAnnouncementRead)
 	})
 	
 	// --- Protected General Routes ---
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware)
 		r.Get("/api/profile", userHandler.GetProfile)
+
+		// Discussions are open to the course staff and to enrolled students
+		r.Get("/api/courses/{id}/threads", discussionHandler.GetThreads)
+		r.Get("/api/courses/{id}/questions", discussionHandler.GetQuestions)
+		r.Post("/api/courses/{id}/questions", discussionHandler.CreateQuestion)
+		r.Post("/api/courses/{id}/threads", discussionHandler.CreateThread)
+		r.Get("/api/courses/{id}/threads/{threadId}", discussionHandler.GetThread)
+		r.Put("/api/courses/{id}/threads/{threadId}", discussionHandler.UpdateThread)
+		r.Delete("/api/courses/{id}/threads/{threadId}", discussionHandler.DeleteThread)
+		r.Put("/api/courses/{id}/threads/{threadId}/pin", discussionHandler.SetThreadPinned)
+		r.Put("/api/courses/{id}/threads/{threadId}/lock", discussionHandler.SetThreadLocked)
+		r.Post("/api/courses/{id}/threads/{threadId}/vote", discussionHandler.VoteThread)
+		r.Delete("/api/courses/{id}/threads/{threadId}/vote", discussionHandler.UnvoteThread)
+		r.Put("/api/courses/{id}/threads/{threadId}/accepted-answer", discussionHandler.AcceptAnswer)
+		r.Post("/api/courses/{id}/threads/{threadId}/posts", discussionHandler.CreatePost)
+		r.Put("/api/courses/{id}/threads/{threadId}/posts/{postId}", discussionHandler.UpdatePost)
+		r.Delete("/api/courses/{id}/threads/{threadId}/posts/{postId}", discussionHandler.DeletePost)
+		r.Post("/api/courses/{id}/threads/{threadId}/posts/{postId}/vote", discussionHandler.VotePost)
+		r.Delete("/api/courses/{id}/threads/{threadId}/posts/{postId}/vote", discussionHandler.UnvotePost)
 	})
 
 	port := ":8080"
@@ -0,0 +1,397 @@
+// Package archive reads and writes portable course archives.
+//
+// An archive is a zip file with a manifest.json describing the course and its
+// ordered learning materials, plus every uploaded file the course references
+// stored under files/.
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/filecheck"
+	"github.com/dimasrizkyfebrian/coursify/internal/media"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/storage"
+)
+
+const (
+	// ManifestVersion is the manifest format written by Export
+	ManifestVersion = 1
+
+	manifestName = "manifest.json"
+	filesDir     = "files/"
+
+	// maxFileSize caps the uncompressed size of a single file in an archive
+	maxFileSize = 500 << 20
+)
+
+// fileExtensions lists the extensions accepted for the files of a cover and of each material content type.
+// Files are written to the publicly served uploads folder, so anything a browser could run as a page is refused.
+var fileExtensions = map[string][]string{
+	"cover": {".jpg", ".jpeg", ".png"},
+	"pdf":   {".pdf"},
+	"video": {".mp4", ".m4v", ".mov", ".webm"},
+}
+
+// Manifest describes a course and its materials inside an archive
+type Manifest struct {
+	Version   int                `json:"version"`
+	Course    ManifestCourse     `json:"course"`
+	Materials []ManifestMaterial `json:"materials"`
+}
+
+type ManifestCourse struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CoverImage  string `json:"cover_image,omitempty"` // Path of the cover inside the archive
+}
+
+type ManifestMaterial struct {
+	Title       string `json:"title"`
+	ContentType string `json:"content_type"` // 'text', 'video', 'pdf'
+	TextContent string `json:"text_content,omitempty"`
+	VideoURL    string `json:"video_url,omitempty"`
+	File        string `json:"file,omitempty"` // Path of the file inside the archive
+	Position    int    `json:"position"`
+	IsPreview   bool   `json:"is_preview"`
+}
+
+// Report is the validation result of an archive, used for dry runs and rejected imports
+type Report struct {
+	Valid         bool     `json:"valid"`
+	CourseTitle   string   `json:"course_title"`
+	MaterialCount int      `json:"material_count"`
+	FileCount     int      `json:"file_count"`
+	Errors        []string `json:"errors"`
+	Warnings      []string `json:"warnings"`
+	Skipped       []string `json:"skipped,omitempty"` // Source items that could not be mapped to materials
+}
+
+func (r *Report) errorf(format string, args ...any) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *Report) warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (r *Report) skipf(format string, args ...any) {
+	r.Skipped = append(r.Skipped, fmt.Sprintf(format, args...))
+}
+
+// Archive is an opened and validated course archive
+type Archive struct {
+	Manifest Manifest
+	files    map[string]*zip.File
+	types    map[string]string // MIME type of each cover, PDF and video file, recognised from its content
+	videos   map[string]string // Container of each video file
+	embedded []string          // Files linked from text materials rather than referenced by the manifest
+}
+
+// Open opens the file referenced by the manifest at name
+func (a *Archive) Open(name string) (io.ReadCloser, error) {
+	f, ok := a.files[name]
+	if !ok {
+		return nil, fmt.Errorf("file %q not found in archive", name)
+	}
+	return f.Open()
+}
+
+// exportFile is an uploaded file scheduled to be written to the archive
+type exportFile struct {
+	name string
+	file *os.File
+}
+
+// Export writes the course, its ordered materials and their uploaded files as a zip archive.
+// Every referenced upload is opened before anything is written, so a missing file fails early.
+func Export(w io.Writer, course model.Course, materials []model.LearningMaterial) error {
+	manifest := Manifest{
+		Version: ManifestVersion,
+		Course: ManifestCourse{
+			Title:       course.Title,
+			Description: course.Description,
+		},
+		Materials: []ManifestMaterial{},
+	}
+
+	var files []exportFile
+	defer func() {
+		for _, f := range files {
+			f.file.Close()
+		}
+	}()
+
+	addFile := func(fileURL, name string) (string, error) {
+		diskPath, ok := storage.Path(fileURL)
+		if !ok {
+			return "", nil
+		}
+		f, err := os.Open(diskPath)
+		if err != nil {
+			return "", err
+		}
+		name = filesDir + name + filepath.Ext(diskPath)
+		files = append(files, exportFile{name: name, file: f})
+		return name, nil
+	}
+
+	if course.CoverImageURL.Valid {
+		name, err := addFile(course.CoverImageURL.String, "cover")
+		if err != nil {
+			return fmt.Errorf("cover image: %w", err)
+		}
+		manifest.Course.CoverImage = name
+	}
+
+	for _, material := range materials {
+		item := ManifestMaterial{
+			Title:       material.Title,
+			ContentType: material.ContentType,
+			TextContent: material.TextContent,
+			VideoURL:    material.VideoURL,
+			Position:    material.Position,
+			IsPreview:   material.IsPreview,
+		}
+		if material.FileURL != "" {
+			name, err := addFile(material.FileURL, fmt.Sprintf("material-%d", material.Position))
+			if err != nil {
+				return fmt.Errorf("material %q: %w", material.Title, err)
+			}
+			item.File = name
+		}
+		manifest.Materials = append(manifest.Materials, item)
+	}
+
+	zw := zip.NewWriter(w)
+
+	mw, err := zw.Create(manifestName)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(fw, f.file); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// Read opens an archive and validates its manifest and files.
+// The returned report is always filled in; the archive is nil when the report has errors.
+func Read(r io.ReaderAt, size int64) (*Archive, *Report) {
+	report := &Report{Errors: []string{}, Warnings: []string{}}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		report.errorf("not a valid zip file: %v", err)
+		return nil, report
+	}
+
+	a := &Archive{files: make(map[string]*zip.File), types: make(map[string]string), videos: make(map[string]string)}
+	var manifestFile *zip.File
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if f.Name == manifestName {
+			manifestFile = f
+			continue
+		}
+		// Only plain names under files/ are accepted, so nothing can point outside the archive
+		if !strings.HasPrefix(f.Name, filesDir) || path.Clean(f.Name) != f.Name || strings.Contains(f.Name, "..") {
+			report.warnf("ignoring unexpected entry %q", f.Name)
+			continue
+		}
+		if f.UncompressedSize64 > maxFileSize {
+			report.errorf("file %q is larger than %d MB", f.Name, maxFileSize>>20)
+			continue
+		}
+		a.files[f.Name] = f
+	}
+
+	if manifestFile == nil {
+		report.errorf("archive has no %s", manifestName)
+		return nil, report
+	}
+
+	mr, err := manifestFile.Open()
+	if err != nil {
+		report.errorf("could not open %s: %v", manifestName, err)
+		return nil, report
+	}
+	defer mr.Close()
+	if err := json.NewDecoder(mr).Decode(&a.Manifest); err != nil {
+		report.errorf("invalid %s: %v", manifestName, err)
+		return nil, report
+	}
+
+	validate(a, report)
+	if len(report.Errors) > 0 {
+		return nil, report
+	}
+	report.Valid = true
+	return a, report
+}
+
+// checkContent runs a cover, PDF or video file through the checks applied to uploads of its kind and records its type.
+// A video's container is recognised from its first bytes here, its duration is read by Import once it is on disk.
+func (a *Archive) checkContent(name, kind string) error {
+	if _, ok := a.types[name]; ok {
+		return nil
+	}
+	var limit int64
+	switch kind {
+	case model.UploadKindCover:
+		limit = filecheck.MaxCoverSize
+	case model.UploadKindPDF:
+		limit = filecheck.MaxPDFSize
+	case model.UploadKindVideo:
+		return a.sniffVideo(name)
+	default:
+		return nil
+	}
+
+	f := a.files[name]
+	if f.UncompressedSize64 > uint64(limit) {
+		return fmt.Errorf("is larger than %d MB", limit>>20)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > limit {
+		return fmt.Errorf("is larger than %d MB", limit>>20)
+	}
+	mimeType, err := filecheck.Check(kind, data)
+	if err != nil {
+		return err
+	}
+	a.types[name] = mimeType
+	return nil
+}
+
+// sniffVideo recognises the container of a video file and checks that its extension matches it
+func (a *Archive) sniffVideo(name string) error {
+	rc, err := a.files[name].Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	header := make([]byte, media.SniffLen)
+	n, _ := io.ReadFull(rc, header)
+
+	container, err := media.Sniff(header[:n])
+	if err != nil {
+		return errors.New("Unsupported video format. Use an MP4, MOV or WebM file.")
+	}
+	if !slices.Contains(media.Extensions(container), strings.ToLower(path.Ext(name))) {
+		return fmt.Errorf("The video must have one of the extensions %s.", strings.Join(media.Extensions(container), ", "))
+	}
+	a.types[name] = media.MIMEType(container)
+	a.videos[name] = container
+	return nil
+}
+
+// validate checks the manifest against the rules the database and handlers enforce
+func validate(a *Archive, report *Report) {
+	m := a.Manifest
+	report.CourseTitle = m.Course.Title
+	report.MaterialCount = len(m.Materials)
+
+	if m.Version != ManifestVersion {
+		report.errorf("unsupported manifest version %d, expected %d", m.Version, ManifestVersion)
+	}
+	if strings.TrimSpace(m.Course.Title) == "" {
+		report.errorf("course title is required")
+	}
+	if len(m.Course.Title) > 255 {
+		report.errorf("course title is longer than 255 characters")
+	}
+
+	referenced := make(map[string]bool)
+	for _, name := range a.embedded {
+		referenced[name] = true
+	}
+	checkFile := func(name, owner, kind string) {
+		if name == "" {
+			return
+		}
+		if _, ok := a.files[name]; !ok {
+			report.errorf("%s references missing file %q", owner, name)
+			return
+		}
+		referenced[name] = true
+		allowed := fileExtensions[kind]
+		if !slices.Contains(allowed, strings.ToLower(path.Ext(name))) {
+			if len(allowed) == 0 {
+				report.errorf("%s cannot have a file, but references %q", owner, name)
+			} else {
+				report.errorf("%s file %q must have one of the extensions %s", owner, name, strings.Join(allowed, ", "))
+			}
+			return
+		}
+		if err := a.checkContent(name, kind); err != nil {
+			report.errorf("%s file %q: %v", owner, name, err)
+		}
+	}
+
+	checkFile(m.Course.CoverImage, "course cover", "cover")
+
+	positions := make(map[int]bool)
+	for i, material := range m.Materials {
+		owner := fmt.Sprintf("material %d (%q)", i+1, material.Title)
+		if strings.TrimSpace(material.Title) == "" {
+			report.errorf("material %d has no title", i+1)
+		}
+		if len(material.Title) > 255 {
+			report.errorf("%s has a title longer than 255 characters", owner)
+		}
+		switch material.ContentType {
+		case "text", "video":
+		case "pdf":
+			if material.File == "" {
+				report.errorf("%s is a pdf without a file", owner)
+			}
+		default:
+			report.errorf("%s has unknown content_type %q", owner, material.ContentType)
+		}
+		if positions[material.Position] {
+			report.warnf("%s shares position %d with another material, positions will be renumbered", owner, material.Position)
+		}
+		positions[material.Position] = true
+		checkFile(material.File, owner, material.ContentType)
+	}
+
+	report.FileCount = len(referenced)
+	for name := range a.files {
+		if !referenced[name] {
+			report.warnf("file %q is not referenced by the manifest and will be skipped", name)
+		}
+	}
+}
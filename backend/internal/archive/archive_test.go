@@ -0,0 +1,253 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/certificate"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+// testPNG encodes a small image to have a well formed cover to put in archives
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("could not encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// testPDF renders a certificate to have a well formed PDF to put in archives
+func testPDF(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	cert := model.Certificate{Serial: "CERT-AAAA-BBBB-CCCC-DDDD", StudentName: "Ana", CourseTitle: "Go", CompletedAt: time.Now(), IssuedAt: time.Now()}
+	if err := certificate.Render(&buf, cert); err != nil {
+		t.Fatalf("could not render pdf: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExportAndRead(t *testing.T) {
+	// Work in a temporary folder with an uploaded PDF
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(filepath.Join("uploads", "materials"), os.ModePerm); err != nil {
+		t.Fatalf("could not create uploads folder: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("uploads", "materials", "course-1-1.pdf"), testPDF(t), 0o644); err != nil {
+		t.Fatalf("could not write upload: %v", err)
+	}
+
+	course := model.Course{ID: "course-1", Title: "Course One", Description: "Desc One", CoverImageURL: sql.NullString{}}
+	materials := []model.LearningMaterial{
+		{Title: "Welcome", ContentType: "text", TextContent: "Hello", Position: 1},
+		{Title: "Slides", ContentType: "pdf", FileURL: "/uploads/materials/course-1-1.pdf", Position: 2},
+	}
+
+	// Export the course into memory
+	var buf bytes.Buffer
+	if err := Export(&buf, course, materials); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	// Read it back and check the report
+	a, report := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if !report.Valid {
+		t.Fatalf("expected a valid archive, but got errors %v", report.Errors)
+	}
+	if report.MaterialCount != 2 || report.FileCount != 1 {
+		t.Errorf("expected 2 materials and 1 file, but got %+v", report)
+	}
+	if a.Manifest.Materials[1].File != "files/material-2.pdf" {
+		t.Errorf("expected the pdf to be stored as files/material-2.pdf, but got %q", a.Manifest.Materials[1].File)
+	}
+}
+
+func TestReadReportsInvalidManifest(t *testing.T) {
+	// Build an archive whose pdf material points at a missing file
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("manifest.json")
+	w.Write([]byte(`{"version": 1, "course": {"title": ""}, "materials": [
+		{"title": "Slides", "content_type": "pdf", "file": "files/missing.pdf", "position": 1},
+		{"title": "Quiz", "content_type": "quiz", "position": 2}
+	]}`))
+	zw.Close()
+
+	a, report := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	if a != nil || report.Valid {
+		t.Fatalf("expected the archive to be rejected")
+	}
+	if len(report.Errors) != 3 {
+		t.Errorf("expected 3 errors (title, missing file, content type), but got %v", report.Errors)
+	}
+}
+
+func TestReadRejectsUnsafeFileExtensions(t *testing.T) {
+	// Files land in the served uploads folder, so pages and scripts must not get through
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name, content string) {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	add("manifest.json", `{"version": 1, "course": {"title": "Course", "cover_image": "files/cover.svg"}, "materials": [
+		{"title": "Slides", "content_type": "pdf", "file": "files/slides.html", "position": 1},
+		{"title": "Notes", "content_type": "text", "text_content": "Hi", "file": "files/notes.js", "position": 2}
+	]}`)
+	add("files/cover.svg", "<svg onload=alert(1)>")
+	add("files/slides.html", "<script>alert(1)</script>")
+	add("files/notes.js", "alert(1)")
+	zw.Close()
+
+	a, report := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	if a != nil || report.Valid {
+		t.Fatalf("expected the archive to be rejected")
+	}
+	if len(report.Errors) != 3 {
+		t.Errorf("expected 3 errors (cover, pdf and text file), but got %v", report.Errors)
+	}
+}
+
+func TestReadChecksFileContent(t *testing.T) {
+	// Covers and PDFs get the same content checks as uploads, whatever their extension says
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name, content string) {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	add("manifest.json", `{"version": 1, "course": {"title": "Course", "cover_image": "files/cover.png"}, "materials": [
+		{"title": "Slides", "content_type": "pdf", "file": "files/slides.pdf", "position": 1},
+		{"title": "Handout", "content_type": "pdf", "file": "files/handout.PDF", "position": 2},
+		{"title": "Intro", "content_type": "video", "file": "files/intro.m4v", "position": 3},
+		{"title": "Lecture", "content_type": "video", "file": "files/lecture.mp4", "position": 4},
+		{"title": "Clip", "content_type": "video", "file": "files/clip.webm", "position": 5}
+	]}`)
+	mp4Header := "\x00\x00\x00\x18ftypisom\x00\x00\x02\x00isomiso2"
+	add("files/cover.png", "<html><script>alert(1)</script></html>")
+	add("files/slides.pdf", "%PDF-1.4 test")
+	add("files/handout.PDF", string(testPDF(t)))
+	add("files/intro.m4v", mp4Header)
+	add("files/lecture.mp4", "<html><script>alert(1)</script></html>")
+	add("files/clip.webm", mp4Header)
+	zw.Close()
+
+	a, report := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	if a != nil || report.Valid {
+		t.Fatalf("expected the archive to be rejected")
+	}
+	if len(report.Errors) != 4 {
+		t.Errorf("expected 4 errors (cover, damaged pdf, page saved as video and mp4 saved as webm), but got %v", report.Errors)
+	}
+}
+
+func TestReadCommonCartridge(t *testing.T) {
+	// Build a small cartridge with a page, a pdf, a video link and a discussion topic
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name, content string) {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	add("imsmanifest.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<manifest identifier="cc" xmlns="http://www.imsglobal.org/xsd/imsccv1p3/imscp_v1p1">
+  <metadata>
+    <schema>IMS Common Cartridge</schema>
+    <schemaversion>1.3.0</schemaversion>
+    <lomimscc:lom xmlns:lomimscc="http://ltsc.ieee.org/xsd/imsccv1p3/LOM/manifest">
+      <lomimscc:general><lomimscc:title><lomimscc:string>Legacy Course</lomimscc:string></lomimscc:title></lomimscc:general>
+    </lomimscc:lom>
+  </metadata>
+  <organizations>
+    <organization identifier="org" structure="rooted-hierarchy">
+      <item identifier="root">
+        <item identifier="week1">
+          <title>Week 1</title>
+          <item identifier="i1" identifierref="r1"><title>Welcome</title></item>
+          <item identifier="i2" identifierref="r2"><title>Slides</title></item>
+          <item identifier="i5" identifierref="r5"><title>Syllabus</title></item>
+        </item>
+        <item identifier="i3" identifierref="r3"><title>Lecture video</title></item>
+        <item identifier="i4" identifierref="r4"><title>Introduce yourself</title></item>
+      </item>
+    </organization>
+  </organizations>
+  <resources>
+    <resource identifier="r1" type="webcontent" href="web/welcome.html"><file href="web/welcome.html"/></resource>
+    <resource identifier="r2" type="webcontent" href="web/slides.pdf"><file href="web/slides.pdf"/></resource>
+    <resource identifier="r3" type="imswl_xmlv1p3"><file href="links/video.xml"/></resource>
+    <resource identifier="r4" type="imsdt_xmlv1p3"><file href="topics/intro.xml"/></resource>
+    <resource identifier="r5" type="webcontent" href="web/syllabus.html"><file href="web/syllabus.html"/></resource>
+  </resources>
+</manifest>`)
+	add("web/welcome.html", "<html><body><p>Hello class</p></body></html>")
+	add("web/syllabus.html", `<html><body><img src="$IMS-CC-FILEBASE$/images/logo.png"> <a href="%24IMS-CC-FILEBASE%24/handout%20one.pdf?x=1">Handout</a>
+<script src="$IMS-CC-FILEBASE$/app.js"></script><img src="$IMS-CC-FILEBASE$/images/missing.png"></body></html>`)
+	add("web_resources/images/logo.png", string(testPNG(t)))
+	add("web_resources/handout one.pdf", string(testPDF(t)))
+	add("web_resources/app.js", "alert(1)")
+	add("web/slides.pdf", string(testPDF(t)))
+	add("links/video.xml", `<webLink xmlns="http://www.imsglobal.org/xsd/imsccv1p3/imswl_v1p3"><title>Video</title><url href="https://www.youtube.com/watch?v=abc"/></webLink>`)
+	add("topics/intro.xml", `<topic/>`)
+	zw.Close()
+
+	a, report := ReadCommonCartridge(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	if !report.Valid {
+		t.Fatalf("expected a valid cartridge, but got errors %v", report.Errors)
+	}
+	if a.Manifest.Course.Title != "Legacy Course" {
+		t.Errorf("expected course title 'Legacy Course', but got %q", a.Manifest.Course.Title)
+	}
+
+	// The four supported items are kept in outline order
+	want := []struct{ title, contentType string }{{"Welcome", "text"}, {"Slides", "pdf"}, {"Syllabus", "text"}, {"Lecture video", "video"}}
+	if len(a.Manifest.Materials) != len(want) {
+		t.Fatalf("expected %d materials, but got %+v", len(want), a.Manifest.Materials)
+	}
+	for i, w := range want {
+		m := a.Manifest.Materials[i]
+		if m.Title != w.title || m.ContentType != w.contentType || m.Position != i+1 {
+			t.Errorf("material %d: expected %s (%s) at %d, but got %+v", i, w.title, w.contentType, i+1, m)
+		}
+	}
+	if a.Manifest.Materials[0].TextContent != "<p>Hello class</p>" {
+		t.Errorf("expected the html body as text content, but got %q", a.Manifest.Materials[0].TextContent)
+	}
+
+	// Linked images and PDFs are kept and point at the stored files after import
+	if !slices.Equal(a.embedded, []string{"web_resources/images/logo.png", "web_resources/handout one.pdf"}) {
+		t.Errorf("expected the logo and handout to be embedded, but got %v", a.embedded)
+	}
+	if report.FileCount != 3 {
+		t.Errorf("expected 3 files, but got %d", report.FileCount)
+	}
+	text := linkFiles(a.Manifest.Materials[2].TextContent, map[string]string{
+		"web_resources/images/logo.png": "/uploads/materials/logo.png",
+		"web_resources/handout one.pdf": "/uploads/materials/handout.pdf",
+	})
+	for _, link := range []string{`src="/uploads/materials/logo.png"`, `href="/uploads/materials/handout.pdf"`, `src="$IMS-CC-FILEBASE$/app.js"`} {
+		if !strings.Contains(text, link) {
+			t.Errorf("expected the syllabus to contain %s, but got %q", link, text)
+		}
+	}
+
+	// The discussion topic, the script and the missing image are reported instead of dropped
+	if len(report.Skipped) != 3 {
+		t.Errorf("expected 3 skipped items, but got %v", report.Skipped)
+	}
+}
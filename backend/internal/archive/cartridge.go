@@ -0,0 +1,370 @@
+package archive
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const cartridgeManifestName = "imsmanifest.xml"
+
+// Resource types of IMS Common Cartridge 1.x that can be mapped to learning materials
+const (
+	ccWebContent = "webcontent"
+	ccWebLinkV1  = "imswl_xmlv1p"
+)
+
+// ccManifest is the subset of imsmanifest.xml the importer reads
+type ccManifest struct {
+	Metadata struct {
+		SchemaVersion string `xml:"schemaversion"`
+		LOM           struct {
+			General struct {
+				Title       ccLangString `xml:"title"`
+				Description ccLangString `xml:"description"`
+			} `xml:"general"`
+		} `xml:"lom"`
+	} `xml:"metadata"`
+	Organizations struct {
+		Organization []struct {
+			Title string   `xml:"title"`
+			Items []ccItem `xml:"item"`
+		} `xml:"organization"`
+	} `xml:"organizations"`
+	Resources struct {
+		Resource []ccResource `xml:"resource"`
+	} `xml:"resources"`
+}
+
+type ccLangString struct {
+	String string `xml:"string"`
+}
+
+type ccItem struct {
+	Identifier    string   `xml:"identifier,attr"`
+	IdentifierRef string   `xml:"identifierref,attr"`
+	Title         string   `xml:"title"`
+	Items         []ccItem `xml:"item"`
+}
+
+type ccResource struct {
+	Identifier string `xml:"identifier,attr"`
+	Type       string `xml:"type,attr"`
+	Href       string `xml:"href,attr"`
+	Files      []struct {
+		Href string `xml:"href,attr"`
+	} `xml:"file"`
+}
+
+// ccWebLink is the weblink resource document (imswl_xmlv1p1 to imswl_xmlv1p3)
+type ccWebLink struct {
+	Title string `xml:"title"`
+	URL   struct {
+		Href string `xml:"href,attr"`
+	} `xml:"url"`
+}
+
+var (
+	htmlBodyPattern = regexp.MustCompile(`(?is)<body[^>]*>(.*)</body>`)
+	videoHostSuffix = []string{"youtube.com", "youtu.be", "vimeo.com"}
+
+	// fileBasePattern matches a link to a cartridge file in web content, plain or URL encoded, up to the end of its attribute
+	fileBasePattern = regexp.MustCompile(`(?:\$|%24)IMS-CC-FILEBASE(?:\$|%24)[^"'\s<>()]*`)
+	fileBasePrefix  = regexp.MustCompile(`^(?:\$|%24)IMS-CC-FILEBASE(?:\$|%24)`)
+)
+
+// fileBaseRoot is the folder of the cartridge that $IMS-CC-FILEBASE$ links point into
+const fileBaseRoot = "web_resources"
+
+// embeddedKinds maps the extensions of files that web content may embed to the upload checks they get.
+// Anything else, scripts and pages included, stays unlinked.
+var embeddedKinds = map[string]string{
+	".jpg":  "cover",
+	".jpeg": "cover",
+	".png":  "cover",
+	".pdf":  "pdf",
+}
+
+// ReadCommonCartridge converts an IMS Common Cartridge package into an archive that Import can recreate.
+// Web content pages become text materials, PDFs become pdf materials and web links become
+// video materials for known video hosts or text materials otherwise. Images and PDFs that pages
+// link to from web_resources are checked like uploads and stored with the course on import.
+// Everything else is listed in the report's Skipped entries.
+func ReadCommonCartridge(r io.ReaderAt, size int64) (*Archive, *Report) {
+	report := &Report{Errors: []string{}, Warnings: []string{}, Skipped: []string{}}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		report.errorf("not a valid zip file: %v", err)
+		return nil, report
+	}
+
+	entries := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() {
+			entries[path.Clean(f.Name)] = f
+		}
+	}
+
+	manifestFile, ok := entries[cartridgeManifestName]
+	if !ok {
+		report.errorf("cartridge has no %s", cartridgeManifestName)
+		return nil, report
+	}
+
+	var cc ccManifest
+	if err := decodeXML(manifestFile, &cc); err != nil {
+		report.errorf("invalid %s: %v", cartridgeManifestName, err)
+		return nil, report
+	}
+	if version := cc.Metadata.SchemaVersion; !strings.HasPrefix(version, "1.3") {
+		report.warnf("cartridge schema version is %q, the importer targets 1.3.0", version)
+	}
+
+	resources := make(map[string]ccResource)
+	for _, res := range cc.Resources.Resource {
+		resources[res.Identifier] = res
+	}
+
+	a := &Archive{files: make(map[string]*zip.File), types: make(map[string]string), videos: make(map[string]string)}
+	a.Manifest = Manifest{
+		Version: ManifestVersion,
+		Course: ManifestCourse{
+			Title:       strings.TrimSpace(cc.Metadata.LOM.General.Title.String),
+			Description: strings.TrimSpace(cc.Metadata.LOM.General.Description.String),
+		},
+		Materials: []ManifestMaterial{},
+	}
+
+	used := make(map[string]bool)
+	var walk func(items []ccItem)
+	walk = func(items []ccItem) {
+		for _, item := range items {
+			if item.IdentifierRef != "" {
+				used[item.IdentifierRef] = true
+				res, ok := resources[item.IdentifierRef]
+				if !ok {
+					report.errorf("item %q references missing resource %q", item.Title, item.IdentifierRef)
+				} else if material, ok := mapResource(a, entries, item, res, report); ok {
+					material.Position = len(a.Manifest.Materials) + 1
+					a.Manifest.Materials = append(a.Manifest.Materials, material)
+				}
+			}
+			// Folders have no resource of their own, their children are flattened in order
+			walk(item.Items)
+		}
+	}
+	for _, org := range cc.Organizations.Organization {
+		if a.Manifest.Course.Title == "" {
+			a.Manifest.Course.Title = strings.TrimSpace(org.Title)
+		}
+		walk(org.Items)
+	}
+
+	for _, res := range cc.Resources.Resource {
+		if !used[res.Identifier] && isLearningResource(res) {
+			report.skipf("resource %q (%s) is not placed in the course outline", res.Identifier, res.Type)
+		}
+	}
+
+	validate(a, report)
+	if len(report.Errors) > 0 {
+		return nil, report
+	}
+	report.Valid = true
+	return a, report
+}
+
+// mapResource converts one organization item and its resource into a material
+func mapResource(a *Archive, entries map[string]*zip.File, item ccItem, res ccResource, report *Report) (ManifestMaterial, bool) {
+	material := ManifestMaterial{Title: strings.TrimSpace(item.Title)}
+	label := fmt.Sprintf("item %q", material.Title)
+
+	href := resourceHref(res)
+	if href == "" {
+		report.skipf("%s has a %q resource without a file", label, res.Type)
+		return material, false
+	}
+	entry, ok := entries[href]
+	if !ok {
+		report.skipf("%s points at %q which is not in the cartridge", label, href)
+		return material, false
+	}
+
+	switch {
+	case res.Type == ccWebContent && strings.EqualFold(path.Ext(href), ".pdf"):
+		material.ContentType = "pdf"
+		material.File = href
+		a.files[href] = entry
+
+	case res.Type == ccWebContent && isHTML(href):
+		body, err := readAll(entry)
+		if err != nil {
+			report.skipf("%s could not be read: %v", label, err)
+			return material, false
+		}
+		if m := htmlBodyPattern.FindSubmatch(body); m != nil {
+			body = m[1]
+		}
+		material.ContentType = "text"
+		material.TextContent = embedFiles(a, entries, href, strings.TrimSpace(string(body)), label, report)
+
+	case strings.HasPrefix(res.Type, ccWebLinkV1):
+		var link ccWebLink
+		if err := decodeXML(entry, &link); err != nil || link.URL.Href == "" {
+			report.skipf("%s is a web link without a usable URL", label)
+			return material, false
+		}
+		if material.Title == "" {
+			material.Title = strings.TrimSpace(link.Title)
+		}
+		if isVideoURL(link.URL.Href) {
+			material.ContentType = "video"
+			material.VideoURL = link.URL.Href
+		} else {
+			material.ContentType = "text"
+			material.TextContent = link.URL.Href
+			report.warnf("%s is a web link and was imported as a text material", label)
+		}
+
+	case res.Type == ccWebContent:
+		report.skipf("%s is web content of unsupported file type %q", label, path.Ext(href))
+		return material, false
+
+	default:
+		report.skipf("%s has unsupported resource type %q", label, res.Type)
+		return material, false
+	}
+
+	return material, true
+}
+
+// embedFiles adds the images and PDFs that web content links to with $IMS-CC-FILEBASE$ to the archive,
+// once they pass the upload checks, and rewrites each link to name the file inside the archive.
+// Import stores the files and points the links at them. Files that cannot be used are reported as skipped
+// and their links are left as they were.
+func embedFiles(a *Archive, entries map[string]*zip.File, href, text, label string, report *Report) string {
+	return fileBasePattern.ReplaceAllStringFunc(text, func(link string) string {
+		var name string
+		if ref := fileBaseRef(link); ref != "" {
+			for _, candidate := range []string{path.Join(fileBaseRoot, ref), path.Join(path.Dir(href), ref)} {
+				if _, ok := entries[candidate]; ok {
+					name = candidate
+					break
+				}
+			}
+		}
+		if name == "" {
+			report.skipf("%s links to %q which is not in the cartridge", label, link)
+			return link
+		}
+		if _, ok := a.types[name]; ok {
+			return fileBaseLink(name)
+		}
+
+		kind, ok := embeddedKinds[strings.ToLower(path.Ext(name))]
+		if !ok {
+			report.skipf("%s links to %q which is not an image or PDF", label, name)
+			return link
+		}
+		a.files[name] = entries[name]
+		if err := a.checkContent(name, kind); err != nil {
+			delete(a.files, name)
+			report.skipf("%s links to %q which cannot be used: %v", label, name, err)
+			return link
+		}
+		a.embedded = append(a.embedded, name)
+		return fileBaseLink(name)
+	})
+}
+
+// linkFiles points the links written by embedFiles at the URLs the files were stored under
+func linkFiles(text string, links map[string]string) string {
+	return fileBasePattern.ReplaceAllStringFunc(text, func(link string) string {
+		if fileURL, ok := links[fileBaseRef(link)]; ok {
+			return fileURL
+		}
+		return link
+	})
+}
+
+// fileBaseRef returns the cartridge path a $IMS-CC-FILEBASE$ link points at, relative to the file base
+func fileBaseRef(link string) string {
+	ref := link[len(fileBasePrefix.FindString(link)):]
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	ref = path.Clean("/" + ref)
+	return strings.TrimPrefix(ref, "/")
+}
+
+// fileBaseLink writes a $IMS-CC-FILEBASE$ link to a file inside the archive, read back by fileBaseRef on import
+func fileBaseLink(name string) string {
+	return "$IMS-CC-FILEBASE$/" + (&url.URL{Path: name}).EscapedPath()
+}
+
+// resourceHref returns the main file of a resource
+func resourceHref(res ccResource) string {
+	href := res.Href
+	if href == "" && len(res.Files) > 0 {
+		href = res.Files[0].Href
+	}
+	if href == "" {
+		return ""
+	}
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	return path.Clean(href)
+}
+
+// isLearningResource reports whether a resource is something a learner would see,
+// as opposed to files that only support other resources
+func isLearningResource(res ccResource) bool {
+	return res.Type != ccWebContent || res.Href != ""
+}
+
+func isHTML(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".html" || ext == ".htm"
+}
+
+func isVideoURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, suffix := range videoHostSuffix {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func readAll(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, maxFileSize))
+}
+
+func decodeXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
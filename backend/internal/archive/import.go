@@ -0,0 +1,144 @@
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/filecheck"
+	"github.com/dimasrizkyfebrian/coursify/internal/media"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/dimasrizkyfebrian/coursify/internal/storage"
+	"github.com/google/uuid"
+)
+
+// Import recreates the archived course as a draft owned by instructorID.
+// Files are written to the uploads folder first and removed again if the database insert fails.
+func Import(repo *repository.CourseRepository, a *Archive, instructorID string) (*model.Course, error) {
+	m := a.Manifest
+	course := &model.Course{
+		ID:           uuid.NewString(),
+		InstructorID: instructorID,
+		Title:        m.Course.Title,
+		Description:  m.Course.Description,
+	}
+
+	var saved []string
+	stamp := time.Now().Unix()
+	saveFile := func(name, dir, newName string) (string, error) {
+		src, err := a.Open(name)
+		if err != nil {
+			return "", err
+		}
+		defer src.Close()
+		// Covers and PDFs are named after their detected type, whatever extension they had in the archive.
+		// Videos keep theirs, which was checked against the container.
+		ext := strings.ToLower(path.Ext(name))
+		if _, ok := a.videos[name]; !ok {
+			if mimeType, ok := a.types[name]; ok {
+				ext = filecheck.Extension(mimeType)
+			}
+		}
+		fileURL, err := storage.Save(src, dir, newName+ext)
+		if err != nil {
+			return "", err
+		}
+		saved = append(saved, fileURL)
+		return fileURL, nil
+	}
+
+	if m.Course.CoverImage != "" {
+		fileURL, err := saveFile(m.Course.CoverImage, "", fmt.Sprintf("%s-%d", course.ID, stamp))
+		if err != nil {
+			storage.Remove(saved...)
+			return nil, fmt.Errorf("cover image: %w", err)
+		}
+		course.CoverImageURL.String, course.CoverImageURL.Valid = fileURL, true
+	}
+
+	// Files linked from text materials are stored first so their links can point at them
+	links := make(map[string]string)
+	for i, name := range a.embedded {
+		fileURL, err := saveFile(name, "materials", fmt.Sprintf("%s-%d-file-%d", course.ID, stamp, i+1))
+		if err != nil {
+			storage.Remove(saved...)
+			return nil, fmt.Errorf("linked file %q: %w", name, err)
+		}
+		links[name] = fileURL
+	}
+
+	// Keep the archived order but renumber positions densely from 1
+	items := append([]ManifestMaterial(nil), m.Materials...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Position < items[j].Position })
+
+	materials := make([]model.LearningMaterial, 0, len(items))
+	for i, item := range items {
+		material := model.LearningMaterial{
+			Title:       item.Title,
+			ContentType: item.ContentType,
+			TextContent: item.TextContent,
+			VideoURL:    item.VideoURL,
+			Position:    i + 1,
+			IsPreview:   item.IsPreview,
+		}
+		if len(links) > 0 && material.ContentType == "text" {
+			material.TextContent = linkFiles(material.TextContent, links)
+		}
+		if item.File != "" {
+			dir := "materials"
+			container, isVideo := a.videos[item.File]
+			if isVideo {
+				dir = "videos"
+			}
+			fileURL, err := saveFile(item.File, dir, fmt.Sprintf("%s-%d-%d", course.ID, stamp, material.Position))
+			if err == nil {
+				material.FileURL = fileURL
+				if isVideo {
+					err = probeVideo(&material, container)
+				}
+			}
+			if err != nil {
+				storage.Remove(saved...)
+				return nil, fmt.Errorf("material %q: %w", item.Title, err)
+			}
+		}
+		materials = append(materials, material)
+	}
+
+	if err := repo.ImportCourse(course, materials); err != nil {
+		storage.Remove(saved...)
+		return nil, err
+	}
+	return course, nil
+}
+
+// probeVideo reads the size and duration of a saved video material, like the upload handlers do
+func probeVideo(material *model.LearningMaterial, container string) error {
+	diskPath, _ := storage.Path(material.FileURL)
+	file, err := os.Open(diskPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	duration, err := media.Duration(file, container)
+	if err != nil {
+		return fmt.Errorf("could not read the video's duration, the file may be damaged or incomplete: %w", err)
+	}
+
+	size := info.Size()
+	material.FileSizeBytes = &size
+	if duration > 0 {
+		seconds := int(duration.Round(time.Second) / time.Second)
+		material.DurationSeconds = &seconds
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package certificate renders completion certificates as PDF documents.
+//
+// The PDF is written by hand with the standard Helvetica fonts every PDF reader
+// ships with, so no font files or external services are needed.
+package certificate
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+// Landscape A4 in PDF points
+const (
+	pageWidth    = 842.0
+	pageHeight   = 595.0
+	maxLineWidth = 700.0
+)
+
+// NewSerial returns a random serial such as "CERT-7QK2-M4XD-PA9F-3HWN".
+// Its 80 random bits make guessing the serial of another certificate impractical.
+func NewSerial() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	encoded := base32.StdEncoding.EncodeToString(raw)
+	return fmt.Sprintf("CERT-%s-%s-%s-%s", encoded[0:4], encoded[4:8], encoded[8:12], encoded[12:16]), nil
+}
+
+// font is one of the standard fonts with its glyph widths for printable ASCII, in 1/1000 of the font size
+type font struct {
+	resource string
+	name     string
+	widths   [95]int
+}
+
+var helvetica = font{resource: "F1", name: "Helvetica", widths: [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}}
+
+var helveticaBold = font{resource: "F2", name: "Helvetica-Bold", widths: [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}}
+
+// encode converts s to the WinAnsi bytes of the standard fonts.
+// Latin-1 characters are kept, anything else becomes '?'.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r <= 0x7e, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		case r == '\t' || r == '\n' || r == '\r':
+			out = append(out, ' ')
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// width returns the width of the encoded text in points
+func (f font) width(text []byte, size float64) float64 {
+	total := 0
+	for _, c := range text {
+		if c >= 0x20 && c <= 0x7e {
+			total += f.widths[c-0x20]
+		} else {
+			total += 556 // Accented letters are about as wide as a digit
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// escape makes the encoded text safe inside a PDF string literal
+func escape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		if c == '\\' || c == '(' || c == ')' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// centered draws a line of text centered on the page at height y,
+// shrinking the font size until the line fits within maxLineWidth
+func centered(content *bytes.Buffer, f font, size, y float64, text string) {
+	encoded := encode(text)
+	if w := f.width(encoded, size); w > maxLineWidth {
+		size = size * maxLineWidth / w
+	}
+	x := (pageWidth - f.width(encoded, size)) / 2
+	fmt.Fprintf(content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", f.resource, size, x, y, escape(encoded))
+}
+
+// Render writes the certificate as a single page landscape A4 PDF
+func Render(w io.Writer, cert model.Certificate) error {
+	var content bytes.Buffer
+
+	// Double frame around the page
+	content.WriteString("0.15 0.3 0.55 RG 3 w 30 30 782 535 re S 1 w 40 40 762 515 re S\n")
+	content.WriteString("0.15 0.3 0.55 rg\n")
+	centered(&content, helveticaBold, 40, 455, "Certificate of Completion")
+	content.WriteString("0.2 0.2 0.2 rg\n")
+	centered(&content, helvetica, 16, 395, "This certifies that")
+	centered(&content, helveticaBold, 32, 345, cert.StudentName)
+	centered(&content, helvetica, 16, 295, "has successfully completed the course")
+	centered(&content, helveticaBold, 24, 250, cert.CourseTitle)
+	centered(&content, helvetica, 14, 190, "Completed on "+cert.CompletedAt.UTC().Format("January 2, 2006"))
+	content.WriteString("0.45 0.45 0.45 rg\n")
+	centered(&content, helvetica, 11, 90, "Certificate serial: "+cert.Serial)
+	centered(&content, helvetica, 9, 72, "Verify this certificate at /api/certificates/"+cert.Serial)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Contents 4 0 R /Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> >>", pageWidth, pageHeight),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", helvetica.name),
+		fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", helveticaBold.name),
+		fmt.Sprintf("<< /Title (%s) /Producer (Coursify) /CreationDate (D:%s) >>",
+			escape(encode("Certificate "+cert.Serial)), cert.IssuedAt.UTC().Format("20060102150405Z")),
+	}
+
+	// Each object's byte offset goes into the cross-reference table
+	var doc bytes.Buffer
+	doc.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = doc.Len()
+		fmt.Fprintf(&doc, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := doc.Len()
+	fmt.Fprintf(&doc, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&doc, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&doc, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xref)
+
+	_, err := w.Write(doc.Bytes())
+	return err
+}
+
+// Filename is the download name of a certificate
+func Filename(cert model.Certificate) string {
+	return "certificate-" + cert.Serial + ".pdf"
+}
@@ -0,0 +1,85 @@
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+func TestRender(t *testing.T) {
+	cert := model.Certificate{
+		Serial:      "CERT-AAAA-BBBB-CCCC-DDDD",
+		StudentName: "José (Joe) O'Brien",
+		CourseTitle: "Go for Beginners",
+		CompletedAt: time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC),
+		IssuedAt:    time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, cert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc := buf.String()
+
+	if !strings.HasPrefix(doc, "%PDF-1.4\n") || !strings.HasSuffix(doc, "%%EOF\n") {
+		t.Fatalf("expected a complete PDF document")
+	}
+	// Parentheses are escaped and the accented letter is kept as WinAnsi
+	if !strings.Contains(doc, "(Jos\xe9 \\(Joe\\) O'Brien)") {
+		t.Errorf("expected the escaped student name in the document")
+	}
+	if !strings.Contains(doc, "(Completed on March 14, 2026)") || !strings.Contains(doc, "(Certificate serial: CERT-AAAA-BBBB-CCCC-DDDD)") {
+		t.Errorf("expected the completion date and serial in the document")
+	}
+
+	// Every cross-reference entry points at the start of its object
+	start, err := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(doc)[1])
+	if err != nil || !strings.HasPrefix(doc[start:], "xref\n") {
+		t.Fatalf("startxref does not point at the cross-reference table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(doc[start:], -1)
+	if len(entries) != 7 {
+		t.Fatalf("expected 7 objects in the cross-reference table, but got %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if !strings.HasPrefix(doc[offset:], fmt.Sprintf("%d 0 obj\n", i+1)) {
+			t.Errorf("cross-reference entry %d does not point at its object", i+1)
+		}
+	}
+}
+
+func TestCenteredShrinksLongLines(t *testing.T) {
+	var content bytes.Buffer
+	centered(&content, helveticaBold, 24, 250, strings.Repeat("Very Long Course Title ", 10))
+
+	var size, x float64
+	if _, err := fmt.Sscanf(content.String(), "BT /F2 %f Tf %f", &size, &x); err != nil {
+		t.Fatalf("unexpected text operator %q: %v", content.String(), err)
+	}
+	if size >= 24 {
+		t.Errorf("expected the font size to shrink, but got %.2f", size)
+	}
+	if x < (pageWidth-maxLineWidth)/2-0.01 {
+		t.Errorf("expected the line to stay within the page margins, but it starts at %.2f", x)
+	}
+}
+
+func TestNewSerial(t *testing.T) {
+	serial, err := NewSerial()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^CERT-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`).MatchString(serial) {
+		t.Errorf("unexpected serial format %q", serial)
+	}
+	if other, _ := NewSerial(); other == serial {
+		t.Errorf("expected two serials to differ")
+	}
+}
@@ -0,0 +1,67 @@
+// Package filecheck recognises uploaded files by their content rather than their name or declared type,
+// and checks that images and PDFs are well formed before they are served to students.
+//
+// Images are decoded in full and may not carry data after their end, where polyglot files hide a
+// second format. PDFs are read far enough to count their pages and find encryption and JavaScript.
+package filecheck
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/media"
+)
+
+// File types recognised besides the video containers of the media package
+const (
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+	TypePDF  = "application/pdf"
+)
+
+// SniffLen is how many leading bytes Detect needs to recognise a file
+const SniffLen = media.SniffLen
+
+var (
+	// ErrMalformed is returned when a file cannot be read as the type it claims to be
+	ErrMalformed = errors.New("malformed file")
+	// ErrTrailingData is returned for images with data after their end
+	ErrTrailingData = errors.New("data after the end of the image")
+	// ErrImageTooLarge is returned for images of more than MaxImagePixels pixels
+	ErrImageTooLarge = errors.New("image dimensions too large")
+	ErrEncrypted     = errors.New("pdf is encrypted")
+	ErrJavaScript    = errors.New("pdf contains javascript")
+	ErrNoPages       = errors.New("pdf has no pages")
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Detect returns the MIME type of a file recognised from its first bytes, or "" when it is not a
+// type accepted for upload
+func Detect(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, pngSignature):
+		return TypePNG
+	case bytes.HasPrefix(header, []byte{0xff, 0xd8, 0xff}):
+		return TypeJPEG
+	case bytes.HasPrefix(header, []byte("%PDF-")):
+		return TypePDF
+	}
+	if container, err := media.Sniff(header); err == nil {
+		return media.MIMEType(container)
+	}
+	return ""
+}
+
+// Extension returns the extension an image or PDF is saved with
+func Extension(mimeType string) string {
+	switch mimeType {
+	case TypeJPEG:
+		return ".jpg"
+	case TypePNG:
+		return ".png"
+	case TypePDF:
+		return ".pdf"
+	}
+	return ""
+}
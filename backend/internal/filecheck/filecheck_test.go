@@ -0,0 +1,178 @@
+package filecheck
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/certificate"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for x := range 16 {
+		img.Set(x, x%8, color.RGBA{R: 200, A: 255})
+	}
+	return img
+}
+
+func encodePNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		string(encodePNG(t)):                   TypePNG,
+		string(encodeJPEG(t)):                  TypeJPEG,
+		"%PDF-1.7\n":                           TypePDF,
+		"\x00\x00\x00\x18ftypisom\x00\x00\x00": "video/mp4",
+		"<html><script>alert(1)</script>":      "",
+		"GIF89a":                               "",
+	}
+	for header, want := range tests {
+		if got := Detect([]byte(header)); got != want {
+			t.Errorf("Detect(%.12q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCheckImage(t *testing.T) {
+	pngData := encodePNG(t)
+	jpegData := encodeJPEG(t)
+
+	// A PNG claiming to be 10000 by 10000 pixels, with a valid header checksum
+	huge := bytes.Clone(pngData)
+	binary.BigEndian.PutUint32(huge[16:], 10000)
+	binary.BigEndian.PutUint32(huge[20:], 10000)
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+
+	tests := []struct {
+		name     string
+		data     []byte
+		mimeType string
+		want     error
+	}{
+		{"png", pngData, TypePNG, nil},
+		{"jpeg", jpegData, TypeJPEG, nil},
+		{"multi-picture jpeg", append(bytes.Clone(jpegData), jpegData...), TypeJPEG, nil},
+		{"png with html appended", append(bytes.Clone(pngData), "<script>alert(1)</script>"...), TypePNG, ErrTrailingData},
+		{"jpeg with zip appended", append(bytes.Clone(jpegData), "PK\x03\x04"...), TypeJPEG, ErrTrailingData},
+		{"truncated png", pngData[:len(pngData)-20], TypePNG, ErrMalformed},
+		{"truncated jpeg", jpegData[:len(jpegData)/2], TypeJPEG, ErrMalformed},
+		{"png as jpeg", pngData, TypeJPEG, ErrMalformed},
+		{"huge png", huge, TypePNG, ErrImageTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckImage(tt.data, tt.mimeType); err != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// buildPDF writes a PDF of the given objects, numbered from 1, with a cross-reference table and trailer
+func buildPDF(trailer string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailer, xref)
+	return buf.Bytes()
+}
+
+// objectStream packs objects numbered from first into a compressed object stream
+func objectStream(first int, objects ...string) string {
+	var header, body strings.Builder
+	for i, object := range objects {
+		fmt.Fprintf(&header, "%d %d ", first+i, body.Len())
+		body.WriteString(object + "\n")
+	}
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte(header.String() + body.String()))
+	zw.Close()
+	return fmt.Sprintf("<< /Type /ObjStm /N %d /First %d /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+		len(objects), header.Len(), compressed.Len(), compressed.String())
+}
+
+func TestCheckPDF(t *testing.T) {
+	catalog := "<< /Type /Catalog /Pages 2 0 R >>"
+	pages := "<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>"
+	page := "<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >> >> >> >>"
+
+	tests := []struct {
+		name  string
+		data  []byte
+		pages int
+		want  error
+	}{
+		{"two pages", buildPDF("", catalog, pages, page, page), 2, nil},
+		{"strings are not read as names", buildPDF("", catalog, pages, page,
+			"<< /Type /Page /Parent 2 0 R /Title (see /JS and \\) /JavaScript) >>"), 2, nil},
+		{"open action javascript", buildPDF("", "<< /Type /Catalog /Pages 2 0 R /OpenAction << /S /JavaScript /JS (app.alert(1)) >> >>",
+			pages, page, page), 0, ErrJavaScript},
+		{"escaped javascript name", buildPDF("", catalog, pages, page, "<< /Type /Page /AA << /O << /S /J#61vaScript /J#53 5 0 R >> >> >>"),
+			0, ErrJavaScript},
+		{"encrypted", buildPDF("/Encrypt 5 0 R", catalog, pages, page, page, "<< /Filter /Standard /V 2 /R 3 >>"), 0, ErrEncrypted},
+		{"no pages", buildPDF("", catalog, "<< /Type /Pages /Kids [] /Count 0 >>"), 0, ErrNoPages},
+		{"pages in an object stream", buildPDF("", catalog, pages, objectStream(3, page, page)), 2, nil},
+		{"javascript in an object stream", buildPDF("", catalog, pages, page,
+			objectStream(4, "<< /Type /Page /Parent 2 0 R /AA << /O 5 0 R >> >>", "<< /S /JavaScript /JS (app.alert(1)) >>")), 0, ErrJavaScript},
+		{"object stream count beyond its header", buildPDF("", catalog, pages, page, page,
+			"<< /Type /ObjStm /N 1152921504606846976 /First 0 /Length 0 >>\nstream\n\nendstream"), 0, ErrMalformed},
+		{"object stream count too large to allocate", buildPDF("", catalog, pages, page, page,
+			"<< /Type /ObjStm /N 10000000000 /First 4 /Length 4 >>\nstream\n1 0 \nendstream"), 0, ErrMalformed},
+		{"not a pdf", []byte("<html></html>"), 0, ErrMalformed},
+		{"truncated", buildPDF("", catalog, pages, page, page)[:200], 0, ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := CheckPDF(tt.data)
+			if err != tt.want || count != tt.pages {
+				t.Errorf("expected %d pages and %v, got %d and %v", tt.pages, tt.want, count, err)
+			}
+		})
+	}
+}
+
+func TestCheckPDFReadsCertificates(t *testing.T) {
+	var buf bytes.Buffer
+	cert := model.Certificate{Serial: "CERT-AAAA-BBBB-CCCC-DDDD", StudentName: "Ana", CourseTitle: "Go", CompletedAt: time.Now(), IssuedAt: time.Now()}
+	if err := certificate.Render(&buf, cert); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := CheckPDF(buf.Bytes()); err != nil || count != 1 {
+		t.Errorf("expected a one page certificate, got %d pages (%v)", count, err)
+	}
+}
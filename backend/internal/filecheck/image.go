@@ -0,0 +1,138 @@
+package filecheck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// MaxImagePixels caps the decoded size of an image, so a small file cannot expand into gigabytes of pixels
+const MaxImagePixels = 40_000_000
+
+// CheckImage checks that a JPEG or PNG image ends where its format says it does and decodes it in full
+func CheckImage(data []byte, mimeType string) error {
+	var end int
+	var err error
+	var decodeConfig func([]byte) (image.Config, error)
+	var decode func([]byte) error
+	switch mimeType {
+	case TypePNG:
+		end, err = pngEnd(data)
+		decodeConfig = func(b []byte) (image.Config, error) { return png.DecodeConfig(bytes.NewReader(b)) }
+		decode = func(b []byte) error { _, err := png.Decode(bytes.NewReader(b)); return err }
+	case TypeJPEG:
+		end, err = jpegEnd(data)
+		decodeConfig = func(b []byte) (image.Config, error) { return jpeg.DecodeConfig(bytes.NewReader(b)) }
+		decode = func(b []byte) error { _, err := jpeg.Decode(bytes.NewReader(b)); return err }
+	default:
+		return ErrMalformed
+	}
+	if err != nil {
+		return err
+	}
+	if end != len(data) {
+		return ErrTrailingData
+	}
+
+	// Check the dimensions before decoding, which allocates every pixel
+	config, err := decodeConfig(data)
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return ErrMalformed
+	}
+	if int64(config.Width)*int64(config.Height) > MaxImagePixels {
+		return ErrImageTooLarge
+	}
+	if err := decode(data); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
+
+// pngEnd walks the chunks of a PNG and returns the offset after its IEND chunk
+func pngEnd(data []byte) (int, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return 0, ErrMalformed
+	}
+	offset := len(pngSignature)
+	for offset+12 <= len(data) {
+		// Length, type, data and CRC
+		length := int64(binary.BigEndian.Uint32(data[offset:]))
+		if length > int64(len(data)-offset-12) {
+			return 0, ErrMalformed
+		}
+		chunkType := string(data[offset+4 : offset+8])
+		offset += 12 + int(length)
+		if chunkType == "IEND" {
+			return offset, nil
+		}
+	}
+	return 0, ErrMalformed
+}
+
+// jpegEnd returns the offset after the end of image marker of a JPEG. Multi-picture JPEGs taken by
+// phone cameras append more JPEGs after the first, those are walked as well.
+func jpegEnd(data []byte) (int, error) {
+	offset := 0
+	for {
+		end, err := jpegImageEnd(data, offset)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.HasPrefix(data[end:], []byte{0xff, 0xd8, 0xff}) {
+			return end, nil
+		}
+		offset = end
+	}
+}
+
+// jpegImageEnd walks the segments of the JPEG starting at offset to its end of image marker
+func jpegImageEnd(data []byte, offset int) (int, error) {
+	if !bytes.HasPrefix(data[offset:], []byte{0xff, 0xd8}) {
+		return 0, ErrMalformed
+	}
+	i := offset + 2
+	for {
+		if i+2 > len(data) || data[i] != 0xff {
+			return 0, ErrMalformed
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xff: // Fill byte before a marker
+			i++
+			continue
+		case marker == 0xd9: // End of image
+			return i + 2, nil
+		case marker == 0x01 || marker >= 0xd0 && marker <= 0xd7: // Markers without a segment
+			i += 2
+			continue
+		}
+
+		if i+4 > len(data) {
+			return 0, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 0, ErrMalformed
+		}
+		i += 2 + length
+
+		if marker == 0xda {
+			// Entropy coded data follows the start of scan, up to the next marker. Within it 0xff is
+			// followed by a stuffed zero or a restart marker.
+			for {
+				next := bytes.IndexByte(data[i:], 0xff)
+				if next < 0 || i+next+1 >= len(data) {
+					return 0, ErrMalformed
+				}
+				i += next
+				if b := data[i+1]; b == 0x00 || b >= 0xd0 && b <= 0xd7 {
+					i += 2
+					continue
+				}
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package filecheck
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxObjectStreamSize caps how far a compressed object stream may inflate
+const maxObjectStreamSize = 32 << 20
+
+// pdfObject is an indirect object of a PDF, with the tokens of its dictionary
+type pdfObject struct {
+	number int
+	tokens []string
+	stream []byte // Raw stream data, nil for objects without a stream
+}
+
+// CheckPDF reads the structure of a PDF and returns its page count. Encrypted PDFs and PDFs
+// with JavaScript are rejected.
+func CheckPDF(data []byte) (int, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return 0, ErrMalformed
+	}
+	if !bytes.Contains(data[max(0, len(data)-1024):], []byte("%%EOF")) {
+		return 0, ErrMalformed
+	}
+
+	objects, trailers := parsePDF(data)
+	if len(objects) == 0 {
+		return 0, ErrMalformed
+	}
+
+	// The trailer or, since PDF 1.5, the cross-reference stream points to the encryption dictionary.
+	// Check it first since the compressed objects of an encrypted file cannot be read.
+	for _, trailer := range trailers {
+		if hasToken(trailer, "/Encrypt") {
+			return 0, ErrEncrypted
+		}
+	}
+	for _, object := range objects {
+		if hasPair(object.tokens, "/Type", "/XRef") && hasToken(object.tokens, "/Encrypt") {
+			return 0, ErrEncrypted
+		}
+	}
+
+	// Since PDF 1.5 objects may be packed into compressed object streams
+	var packed []pdfObject
+	total := 0
+	for _, object := range objects {
+		if !hasPair(object.tokens, "/Type", "/ObjStm") {
+			continue
+		}
+		unpacked, size, err := unpackObjectStream(object, maxObjectStreamSize-total)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+		packed = append(packed, unpacked...)
+	}
+	objects = append(objects, packed...)
+
+	// Count pages by object number, later definitions of an object replace earlier ones
+	pages := make(map[int]bool)
+	for _, object := range objects {
+		if hasToken(object.tokens, "/JS") || hasToken(object.tokens, "/JavaScript") {
+			return 0, ErrJavaScript
+		}
+		pages[object.number] = hasPair(object.tokens, "/Type", "/Page")
+	}
+	count := 0
+	for _, isPage := range pages {
+		if isPage {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, ErrNoPages
+	}
+	return count, nil
+}
+
+// parsePDF finds the indirect objects and trailer dictionaries of a PDF body
+func parsePDF(data []byte) ([]pdfObject, [][]string) {
+	var objects []pdfObject
+	var trailers [][]string
+	l := &pdfLexer{data: data}
+	var prev2, prev1 string
+	for {
+		token, ok := l.next()
+		if !ok {
+			break
+		}
+		switch token {
+		case "obj":
+			number, err := strconv.Atoi(prev2)
+			if _, genErr := strconv.Atoi(prev1); err != nil || genErr != nil {
+				break
+			}
+			object := pdfObject{number: number}
+			var end string
+			object.tokens, end = l.until("stream", "endobj")
+			if end == "stream" {
+				object.stream = l.stream()
+			}
+			objects = append(objects, object)
+		case "trailer":
+			tokens, _ := l.until("startxref")
+			trailers = append(trailers, tokens)
+		}
+		prev2, prev1 = prev1, token
+	}
+	return objects, trailers
+}
+
+// unpackObjectStream inflates an object stream, up to limit bytes, and returns the objects it holds
+func unpackObjectStream(object pdfObject, limit int) ([]pdfObject, int, error) {
+	filter := dictValue(object.tokens, "/Filter")
+	if filter == "[" {
+		filter = dictValue(object.tokens, "[") // A single filter in an array
+	}
+	content := object.stream
+	switch filter {
+	case "/FlateDecode":
+		reader, err := zlib.NewReader(bytes.NewReader(object.stream))
+		if err != nil {
+			return nil, 0, ErrMalformed
+		}
+		content, err = io.ReadAll(io.LimitReader(reader, int64(limit)+1))
+		if err != nil && len(content) == 0 {
+			return nil, 0, ErrMalformed
+		}
+		if len(content) > limit {
+			return nil, 0, ErrMalformed
+		}
+	case "":
+	default:
+		return nil, 0, ErrMalformed // Other filters are not used for object streams in practice
+	}
+
+	count, err := strconv.Atoi(dictValue(object.tokens, "/N"))
+	if err != nil || count < 0 {
+		return nil, 0, ErrMalformed
+	}
+	first, err := strconv.Atoi(dictValue(object.tokens, "/First"))
+	if err != nil || first < 0 || first > len(content) {
+		return nil, 0, ErrMalformed
+	}
+	// Every object takes at least a number and an offset before first, so a larger count cannot be real
+	if count > first/2 {
+		return nil, 0, ErrMalformed
+	}
+
+	// The stream starts with pairs of object number and offset from first
+	header := &pdfLexer{data: content[:first]}
+	numbers := make([]int, count)
+	offsets := make([]int, count+1)
+	for i := range count {
+		numberToken, _ := header.next()
+		offsetToken, _ := header.next()
+		number, numberErr := strconv.Atoi(numberToken)
+		offset, offsetErr := strconv.Atoi(offsetToken)
+		if numberErr != nil || offsetErr != nil || offset < 0 || first+offset > len(content) {
+			return nil, 0, ErrMalformed
+		}
+		numbers[i], offsets[i] = number, first+offset
+	}
+	offsets[count] = len(content)
+
+	objects := make([]pdfObject, 0, count)
+	for i := range count {
+		end := max(offsets[i], offsets[i+1])
+		l := &pdfLexer{data: content[offsets[i]:end]}
+		tokens, _ := l.until()
+		objects = append(objects, pdfObject{number: numbers[i], tokens: tokens})
+	}
+	return objects, len(content), nil
+}
+
+// hasToken reports whether tokens contain token
+func hasToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPair reports whether tokens contain key directly followed by value, such as /Type /Page.
+// Unlike dictValue it also finds the pair after a nested dictionary with the same key.
+func hasPair(tokens []string, key, value string) bool {
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i-1] == key && tokens[i] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// dictValue returns the token following the first occurrence of key
+func dictValue(tokens []string, key string) string {
+	for i, t := range tokens[:max(0, len(tokens)-1)] {
+		if t == key {
+			return tokens[i+1]
+		}
+	}
+	return ""
+}
+
+// pdfLexer splits PDF syntax into tokens. Names are returned with their #xx escapes decoded, and
+// strings are returned as "()" since their content does not matter here.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhitespace(b byte) bool {
+	return b == 0 || b == '\t' || b == '\n' || b == '\f' || b == '\r' || b == ' '
+}
+
+func isPDFDelimiter(b byte) bool {
+	return strings.IndexByte("()<>[]{}/%", b) >= 0
+}
+
+// next returns the next token, or false at the end of the data
+func (l *pdfLexer) next() (string, bool) {
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		switch {
+		case isPDFWhitespace(b):
+			l.pos++
+		case b == '%': // Comment to the end of the line
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case b == '(':
+			l.skipString()
+			return "()", true
+		case b == '<' && l.peek(1) == '<':
+			l.pos += 2
+			return "<<", true
+		case b == '>' && l.peek(1) == '>':
+			l.pos += 2
+			return ">>", true
+		case b == '<': // Hex string
+			if end := bytes.IndexByte(l.data[l.pos:], '>'); end >= 0 {
+				l.pos += end + 1
+			} else {
+				l.pos = len(l.data)
+			}
+			return "()", true
+		case b == '/':
+			return l.name(), true
+		case isPDFDelimiter(b):
+			l.pos++
+			return string(b), true
+		default:
+			start := l.pos
+			for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+			return string(l.data[start:l.pos]), true
+		}
+	}
+	return "", false
+}
+
+func (l *pdfLexer) peek(n int) byte {
+	if l.pos+n < len(l.data) {
+		return l.data[l.pos+n]
+	}
+	return 0
+}
+
+// skipString skips a literal string, which may contain balanced parentheses and escapes
+func (l *pdfLexer) skipString() {
+	depth := 0
+	for ; l.pos < len(l.data); l.pos++ {
+		switch l.data[l.pos] {
+		case '\\':
+			l.pos++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				l.pos++
+				return
+			}
+		}
+	}
+}
+
+// name reads a name, decoding its #xx escapes so /J#61vaScript reads as /JavaScript
+func (l *pdfLexer) name() string {
+	l.pos++ // The slash
+	out := []byte{'/'}
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		b := l.data[l.pos]
+		if b == '#' && l.pos+2 < len(l.data) {
+			if value, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				out = append(out, byte(value))
+				l.pos += 3
+				continue
+			}
+		}
+		out = append(out, b)
+		l.pos++
+	}
+	return string(out)
+}
+
+// until returns the tokens up to one of the given keywords, or to the end of the data, and the keyword found
+func (l *pdfLexer) until(keywords ...string) ([]string, string) {
+	var tokens []string
+	for {
+		token, ok := l.next()
+		if !ok {
+			return tokens, ""
+		}
+		for _, keyword := range keywords {
+			if token == keyword {
+				return tokens, keyword
+			}
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// stream returns the data of a stream whose keyword was just read and moves past its endstream keyword
+func (l *pdfLexer) stream() []byte {
+	// The keyword is followed by CRLF or LF
+	if l.peek(0) == '\r' {
+		l.pos++
+	}
+	if l.peek(0) == '\n' {
+		l.pos++
+	}
+	start := l.pos
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return l.data[start:]
+	}
+	l.pos = start + end + len("endstream")
+	// Drop the end of line before endstream, which is not part of the data
+	data := l.data[start : start+end]
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	return bytes.TrimSuffix(data, []byte("\r"))
+}
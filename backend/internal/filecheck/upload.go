@@ -0,0 +1,75 @@
+package filecheck
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/media"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+// Largest cover image and PDF accepted, whether uploaded directly or brought in by a course import
+const (
+	MaxCoverSize = 10 << 20
+	MaxPDFSize   = 100 << 20
+)
+
+// uploadTypes lists the file types accepted for each kind of upload, recognised from the file's content
+var uploadTypes = map[string][]string{
+	model.UploadKindCover: {TypeJPEG, TypePNG},
+	model.UploadKindPDF:   {TypePDF},
+	model.UploadKindVideo: {
+		media.MIMEType(media.ContainerMP4), media.MIMEType(media.ContainerQuickTime), media.MIMEType(media.ContainerWebM),
+	},
+}
+
+// uploadTypeNames describes the accepted file types of each kind of upload in error messages
+var uploadTypeNames = map[string]string{
+	model.UploadKindCover: "A cover image must be a JPEG or PNG image.",
+	model.UploadKindPDF:   "A PDF material must be a PDF document.",
+	model.UploadKindVideo: "A video must be an MP4, MOV or WebM file.",
+}
+
+// Sniff recognises a file from its first bytes and checks that its type is accepted for the kind of upload.
+// Errors are worded for the person who sent the file.
+func Sniff(kind string, header []byte) (string, error) {
+	mimeType := Detect(header)
+	if !slices.Contains(uploadTypes[kind], mimeType) {
+		return "", fmt.Errorf("Unsupported file type. %s", uploadTypeNames[kind])
+	}
+	return mimeType, nil
+}
+
+// Check checks the type and structure of a whole cover image or PDF, returning its MIME type.
+// Errors are worded for the person who sent the file.
+func Check(kind string, data []byte) (string, error) {
+	mimeType, err := Sniff(kind, data[:min(len(data), SniffLen)])
+	if err != nil {
+		return "", err
+	}
+	if mimeType == TypePDF {
+		_, err = CheckPDF(data)
+	} else {
+		err = CheckImage(data, mimeType)
+	}
+
+	switch {
+	case err == nil:
+		return mimeType, nil
+	case errors.Is(err, ErrTrailingData):
+		return "", errors.New("The image has extra data after its end. Save it again as a plain JPEG or PNG and retry.")
+	case errors.Is(err, ErrImageTooLarge):
+		return "", fmt.Errorf("The image is too large. Use an image of at most %d megapixels.", MaxImagePixels/1_000_000)
+	case errors.Is(err, ErrEncrypted):
+		return "", errors.New("The PDF is password protected. Remove its password and upload it again.")
+	case errors.Is(err, ErrJavaScript):
+		return "", errors.New("The PDF contains JavaScript, which is not allowed. Export it again without scripts or form actions.")
+	case errors.Is(err, ErrNoPages):
+		return "", errors.New("The PDF has no pages.")
+	case mimeType == TypePDF:
+		return "", errors.New("Could not read the PDF, the file may be damaged.")
+	default:
+		return "", errors.New("Could not read the image, the file may be damaged.")
+	}
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/certificate"
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CertificateHandler struct {
+	Repo *repository.CertificateRepository
+}
+
+func NewCertificateHandler(repo *repository.CertificateRepository) *CertificateHandler {
+	return &CertificateHandler{Repo: repo}
+}
+
+type revokeCertificateRequest struct {
+	Reason string `json:"reason" example:"Completion obtained by sharing an account"`
+}
+
+// @Summary      Verify a certificate
+// @Description  Tells whether a certificate serial is genuine and still valid, with the student name, course title and completion date printed on it.
+// @Tags         Public
+// @Produce      json
+// @Param        serial path      string  true  "Certificate serial"
+// @Success      200    {object}  model.CertificateVerification
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /certificates/{serial} [get]
+// VerifyCertificate handles public requests to verify a certificate
+func (h *CertificateHandler) VerifyCertificate(w http.ResponseWriter, r *http.Request) {
+	serial := strings.ToUpper(strings.TrimSpace(chi.URLParam(r, "serial")))
+
+	cert, err := h.Repo.GetCertificateBySerial(serial)
+	if err != nil {
+		http.Error(w, "Failed to verify certificate", http.StatusInternalServerError)
+		return
+	}
+	if cert == nil {
+		http.Error(w, "Certificate not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.CertificateVerification{
+		Serial:       cert.Serial,
+		Valid:        cert.RevokedAt == nil,
+		StudentName:  cert.StudentName,
+		CourseTitle:  cert.CourseTitle,
+		CompletedAt:  cert.CompletedAt,
+		IssuedAt:     cert.IssuedAt,
+		RevokedAt:    cert.RevokedAt,
+		RevokeReason: cert.RevokeReason,
+	})
+}
+
+// @Summary      Get my certificates (Student only)
+// @Description  Retrieves the certificates of the courses the logged-in student completed, revoked ones included.
+// @Tags         Student
+// @Produce      json
+// @Success      200  {array}   model.Certificate
+// @Failure      500  {object}  map[string]string
+// @Router       /student/certificates [get]
+// @Security     BearerAuth
+// GetMyCertificates handles requests from students to list their certificates
+func (h *CertificateHandler) GetMyCertificates(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+
+	certs, err := h.Repo.GetCertificatesByUserID(studentID)
+	if err != nil {
+		http.Error(w, "Failed to fetch certificates", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(certs)
+}
+
+// @Summary      Download a course certificate (Student only)
+// @Description  Downloads the PDF certificate of a course the logged-in student completed.
+// @Tags         Student
+// @Produce      application/pdf
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {file}    file
+// @Failure      404  {object}  map[string]string "Course not completed"
+// @Failure      410  {object}  map[string]string "Certificate revoked"
+// @Failure      500  {object}  map[string]string
+// @Router       /student/courses/{id}/certificate [get]
+// @Security     BearerAuth
+// DownloadCertificate handles requests from students to download a certificate
+func (h *CertificateHandler) DownloadCertificate(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+	courseID := chi.URLParam(r, "id")
+	if uuid.Validate(courseID) != nil {
+		http.Error(w, "You have not completed this course", http.StatusNotFound)
+		return
+	}
+
+	cert, err := h.Repo.GetCertificate(studentID, courseID)
+	if err != nil {
+		http.Error(w, "Failed to fetch certificate", http.StatusInternalServerError)
+		return
+	}
+	if cert == nil {
+		http.Error(w, "You have not completed this course", http.StatusNotFound)
+		return
+	}
+	if cert.RevokedAt != nil {
+		http.Error(w, "This certificate has been revoked", http.StatusGone)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := certificate.Render(&buf, *cert); err != nil {
+		http.Error(w, "Could not generate certificate", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", certificate.Filename(*cert)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// @Summary      Revoke a certificate (Admin only)
+// @Description  Revokes a certificate so verification reports it as invalid and the student can no longer download it. Revoking it again updates the reason.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        serial path      string  true  "Certificate serial"
+// @Param        revoke body      revokeCertificateRequest true "Reason for revoking"
+// @Success      200    {object}  map[string]string
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /admin/certificates/{serial}/revoke [put]
+// @Security     BearerAuth
+// RevokeCertificate handles requests from admins to revoke a certificate
+func (h *CertificateHandler) RevokeCertificate(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve user ID from context", http.StatusInternalServerError)
+		return
+	}
+	serial := strings.ToUpper(strings.TrimSpace(chi.URLParam(r, "serial")))
+
+	var req revokeCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" || len(req.Reason) > 1000 {
+		http.Error(w, "Reason must be between 1 and 1000 characters", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.RevokeCertificate(serial, adminID, req.Reason); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Certificate not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to revoke certificate", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Certificate revoked successfully"})
+}
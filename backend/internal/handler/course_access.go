@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+)
+
+// coursePermission is an action a staff member may take on a course
+type coursePermission string
+
+const (
+	permViewCourse      coursePermission = "view_course"
+	permEditCourse      coursePermission = "edit_course"
+	permManageMaterials coursePermission = "manage_materials"
+	permManageStaff     coursePermission = "manage_staff"
+	permManageRoster    coursePermission = "manage_roster"
+	permModerate        coursePermission = "moderate_discussions"
+)
+
+// staffPermissions maps each course_staff role to the permissions it grants
+var staffPermissions = map[string][]coursePermission{
+	model.StaffRoleOwner:        {permViewCourse, permEditCourse, permManageMaterials, permManageStaff, permManageRoster, permModerate},
+	model.StaffRoleCoInstructor: {permViewCourse, permEditCourse, permManageMaterials, permManageRoster, permModerate},
+	model.StaffRoleTA:           {permViewCourse, permModerate},
+}
+
+func staffRoleAllows(role string, perm coursePermission) bool {
+	for _, p := range staffPermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeCourse loads the course and checks that the logged-in user's staff role grants perm.
+// It writes the error response and returns nil when the course is missing or access is denied.
+func authorizeCourse(repo *repository.CourseRepository, w http.ResponseWriter, r *http.Request, courseID string, perm coursePermission) *model.Course {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve user ID from context", http.StatusInternalServerError)
+		return nil
+	}
+
+	course, err := repo.GetCourseByID(courseID)
+	if err != nil || course == nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return nil
+	}
+
+	role, err := repo.GetCourseStaffRole(courseID, userID)
+	if err != nil {
+		http.Error(w, "Failed to verify course access", http.StatusInternalServerError)
+		return nil
+	}
+	if role == "" {
+		http.Error(w, "Forbidden: You are not a staff member of this course", http.StatusForbidden)
+		return nil
+	}
+	if !staffRoleAllows(role, perm) {
+		http.Error(w, "Forbidden: Your course role does not allow this action", http.StatusForbidden)
+		return nil
+	}
+
+	return course
+}
@@ -0,0 +1,341 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/notify"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxAnnouncementLength limits the body of announcements
+const maxAnnouncementLength = 20000
+
+type announcementRequest struct {
+	Title     string `json:"title" example:"Deadline moved"`
+	Body      string `json:"body" example:"The project is now due on Friday, March 13."`
+	Pinned    bool   `json:"pinned,omitempty" example:"false"` // Only used when creating, pin later with the pin endpoint
+	SendEmail bool   `json:"send_email,omitempty" example:"true"`
+}
+
+type pinAnnouncementRequest struct {
+	Pinned bool `json:"pinned" example:"true"`
+}
+
+// validate trims the request and checks the title and body
+func (req *announcementRequest) validate() error {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Body = strings.TrimSpace(req.Body)
+	switch {
+	case req.Title == "" || len(req.Title) > 255:
+		return errors.New("Title must be between 1 and 255 characters")
+	case req.Body == "" || len(req.Body) > maxAnnouncementLength:
+		return errors.New("Body must be between 1 and 20000 characters")
+	}
+	return nil
+}
+
+// emailAnnouncement sends the announcement to the active students enrolled in the course.
+// Delivery happens in the background, failures are only logged.
+func (h *CourseHandler) emailAnnouncement(course *model.Course, announcement *model.Announcement) error {
+	emails, err := h.Repo.GetEnrolledStudentEmails(course.ID)
+	if err != nil {
+		return err
+	}
+	if err := h.Repo.MarkAnnouncementEmailed(announcement); err != nil {
+		return err
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	msg := notify.Message{
+		To:      emails,
+		Subject: "[" + course.Title + "] " + announcement.Title,
+		Body:    announcement.Body,
+	}
+	go func() {
+		if err := h.Notifier.Send(msg); err != nil {
+			log.Printf("Error emailing announcement %s: %v", announcement.ID, err)
+		}
+	}()
+	return nil
+}
+
+// @Summary      List announcements (Instructor only)
+// @Description  Retrieves the announcements of a course the logged-in instructor is on the staff of, pinned ones first and then newest first.
+// @Tags         Instructor - Announcements
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.Announcement
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/announcements [get]
+// @Security     BearerAuth
+// GetCourseAnnouncements handles requests to list the announcements of a course
+func (h *CourseHandler) GetCourseAnnouncements(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	announcements, err := h.Repo.GetAnnouncements(courseID, "")
+	if err != nil {
+		http.Error(w, "Failed to fetch announcements", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(announcements)
+}
+
+// @Summary      Create an announcement (Instructor only)
+// @Description  Posts an announcement to the students of a course, who see it in their course details until they mark it read. With send_email it is also emailed to every active enrolled student.
+// @Tags         Instructor - Announcements
+// @Accept       json
+// @Produce      json
+// @Param        id           path      string  true  "Course ID"
+// @Param        announcement body      announcementRequest true "Announcement"
+// @Success      201          {object}  model.Announcement
+// @Failure      400          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Failure      404          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Router       /instructor/courses/{id}/announcements [post]
+// @Security     BearerAuth
+// CreateAnnouncement handles requests to post an announcement to a course
+func (h *CourseHandler) CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	course := authorizeCourse(h.Repo, w, r, courseID, permEditCourse)
+	if course == nil {
+		return
+	}
+	authorID := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req announcementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	announcement := model.Announcement{CourseID: courseID, AuthorID: &authorID, Title: req.Title, Body: req.Body, IsPinned: req.Pinned}
+	if err := h.Repo.CreateAnnouncement(&announcement); err != nil {
+		http.Error(w, "Failed to create announcement", http.StatusInternalServerError)
+		return
+	}
+
+	if req.SendEmail {
+		if err := h.emailAnnouncement(course, &announcement); err != nil {
+			http.Error(w, "Announcement created, but it could not be emailed", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(announcement)
+}
+
+// @Summary      Edit an announcement (Instructor only)
+// @Description  Changes the title and body of an announcement. With send_email the edited announcement is emailed to every active enrolled student again. Read receipts are kept.
+// @Tags         Instructor - Announcements
+// @Accept       json
+// @Produce      json
+// @Param        id             path      string  true  "Course ID"
+// @Param        announcementId path      string  true  "Announcement ID"
+// @Param        announcement   body      announcementRequest true "Announcement, pinned is ignored"
+// @Success      200            {object}  model.Announcement
+// @Failure      400            {object}  map[string]string
+// @Failure      403            {object}  map[string]string
+// @Failure      404            {object}  map[string]string
+// @Failure      500            {object}  map[string]string
+// @Router       /instructor/courses/{id}/announcements/{announcementId} [put]
+// @Security     BearerAuth
+// UpdateAnnouncement handles requests to edit an announcement
+func (h *CourseHandler) UpdateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	announcementID := chi.URLParam(r, "announcementId")
+
+	course := authorizeCourse(h.Repo, w, r, courseID, permEditCourse)
+	if course == nil {
+		return
+	}
+	if uuid.Validate(announcementID) != nil {
+		http.Error(w, "Announcement not found in this course", http.StatusNotFound)
+		return
+	}
+
+	var req announcementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	announcement := model.Announcement{ID: announcementID, CourseID: courseID, Title: req.Title, Body: req.Body}
+	if err := h.Repo.UpdateAnnouncement(&announcement); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Announcement not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update announcement", http.StatusInternalServerError)
+		return
+	}
+
+	if req.SendEmail {
+		if err := h.emailAnnouncement(course, &announcement); err != nil {
+			http.Error(w, "Announcement updated, but it could not be emailed", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(announcement)
+}
+
+// @Summary      Pin an announcement (Instructor only)
+// @Description  Pins an announcement above the others, or unpins it.
+// @Tags         Instructor - Announcements
+// @Accept       json
+// @Produce      json
+// @Param        id             path      string  true  "Course ID"
+// @Param        announcementId path      string  true  "Announcement ID"
+// @Param        pin            body      pinAnnouncementRequest true "Whether the announcement is pinned"
+// @Success      200            {object}  map[string]string
+// @Failure      400            {object}  map[string]string
+// @Failure      403            {object}  map[string]string
+// @Failure      404            {object}  map[string]string
+// @Failure      500            {object}  map[string]string
+// @Router       /instructor/courses/{id}/announcements/{announcementId}/pin [put]
+// @Security     BearerAuth
+// SetAnnouncementPinned handles requests to pin or unpin an announcement
+func (h *CourseHandler) SetAnnouncementPinned(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	announcementID := chi.URLParam(r, "announcementId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+	if uuid.Validate(announcementID) != nil {
+		http.Error(w, "Announcement not found in this course", http.StatusNotFound)
+		return
+	}
+
+	var req pinAnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.SetAnnouncementPinned(courseID, announcementID, req.Pinned); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Announcement not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update announcement", http.StatusInternalServerError)
+		return
+	}
+
+	message := "Announcement unpinned"
+	if req.Pinned {
+		message = "Announcement pinned"
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// @Summary      Delete an announcement (Instructor only)
+// @Description  Deletes an announcement of a course.
+// @Tags         Instructor - Announcements
+// @Produce      json
+// @Param        id             path      string  true  "Course ID"
+// @Param        announcementId path      string  true  "Announcement ID"
+// @Success      200            {object}  map[string]string
+// @Failure      403            {object}  map[string]string
+// @Failure      404            {object}  map[string]string
+// @Failure      500            {object}  map[string]string
+// @Router       /instructor/courses/{id}/announcements/{announcementId} [delete]
+// @Security     BearerAuth
+// DeleteAnnouncement handles requests to delete an announcement
+func (h *CourseHandler) DeleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	announcementID := chi.URLParam(r, "announcementId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+	if uuid.Validate(announcementID) != nil {
+		http.Error(w, "Announcement not found in this course", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.DeleteAnnouncement(courseID, announcementID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Announcement not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete announcement", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Announcement deleted successfully"})
+}
+
+// @Summary      Mark an announcement read (Student only)
+// @Description  Marks an announcement of an enrolled course as read by the logged-in student.
+// @Tags         Student
+// @Produce      json
+// @Param        id             path      string  true  "Course ID"
+// @Param        announcementId path      string  true  "Announcement ID"
+// @Success      200            {object}  map[string]string
+// @Failure      403            {object}  map[string]string
+// @Failure      404            {object}  map[string]string
+// @Failure      500            {object}  map[string]string
+// @Router       /student/courses/{id}/announcements/{announcementId}/read [post]
+// @Security     BearerAuth
+// MarkAnnouncementRead handles requests from students to mark an announcement read
+func (h *CourseHandler) MarkAnnouncementRead(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	announcementID := chi.URLParam(r, "announcementId")
+
+	studentID, ok := authorizeEnrollment(h.Repo, w, r, courseID)
+	if !ok {
+		return
+	}
+	if uuid.Validate(announcementID) != nil {
+		http.Error(w, "Announcement not found in this course", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.MarkAnnouncementRead(courseID, announcementID, studentID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Announcement not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to mark announcement read", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Announcement marked as read"})
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type cohortRequest struct {
+	Name               string     `json:"name" example:"Spring 2026"`
+	EnrollmentOpensAt  time.Time  `json:"enrollment_opens_at" example:"2026-01-05T00:00:00Z"`
+	EnrollmentClosesAt time.Time  `json:"enrollment_closes_at" example:"2026-02-01T00:00:00Z"`
+	AccessStartsAt     time.Time  `json:"access_starts_at" example:"2026-02-01T00:00:00Z"`
+	AccessEndsAt       *time.Time `json:"access_ends_at,omitempty" example:"2026-06-30T00:00:00Z"`
+}
+
+// cohort validates the request and converts it into a cohort of courseID
+func (req cohortRequest) cohort(courseID string) (*model.Cohort, error) {
+	name := strings.TrimSpace(req.Name)
+	switch {
+	case name == "":
+		return nil, errors.New("Name is required")
+	case len(name) > 255:
+		return nil, errors.New("Name must be at most 255 characters")
+	case req.EnrollmentOpensAt.IsZero() || req.EnrollmentClosesAt.IsZero() || req.AccessStartsAt.IsZero():
+		return nil, errors.New("enrollment_opens_at, enrollment_closes_at and access_starts_at are required")
+	case !req.EnrollmentClosesAt.After(req.EnrollmentOpensAt):
+		return nil, errors.New("enrollment_closes_at must be after enrollment_opens_at")
+	case req.AccessEndsAt != nil && !req.AccessEndsAt.After(req.AccessStartsAt):
+		return nil, errors.New("access_ends_at must be after access_starts_at")
+	}
+
+	return &model.Cohort{
+		CourseID:           courseID,
+		Name:               name,
+		EnrollmentOpensAt:  req.EnrollmentOpensAt,
+		EnrollmentClosesAt: req.EnrollmentClosesAt,
+		AccessStartsAt:     req.AccessStartsAt,
+		AccessEndsAt:       req.AccessEndsAt,
+	}, nil
+}
+
+// @Summary      Get course cohorts
+// @Description  Retrieves the cohorts of a published course with their enrollment and access windows. Students pick one of them when enrolling.
+// @Tags         Courses
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.Cohort
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /courses/{id}/cohorts [get]
+// GetCourseCohortsPublic handles requests to list the cohorts of a course
+func (h *CourseHandler) GetCourseCohortsPublic(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	course, err := h.Repo.GetCourseByID(courseID)
+	if err != nil || course == nil || course.IsDraft {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return
+	}
+
+	cohorts, err := h.Repo.GetCohortsByCourseID(courseID)
+	if err != nil {
+		http.Error(w, "Failed to fetch cohorts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(cohorts)
+}
+
+// @Summary      Get course cohorts (Instructor only)
+// @Description  Retrieves the cohorts of a course the logged-in instructor is on the staff of.
+// @Tags         Instructor - Cohorts
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.Cohort
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/cohorts [get]
+// @Security     BearerAuth
+// GetCourseCohorts handles requests to list the cohorts of a course
+func (h *CourseHandler) GetCourseCohorts(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	cohorts, err := h.Repo.GetCohortsByCourseID(courseID)
+	if err != nil {
+		http.Error(w, "Failed to fetch cohorts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(cohorts)
+}
+
+// @Summary      Create a cohort (Instructor only)
+// @Description  Adds a cohort to a course. Once a course has cohorts, students must enroll into one of them while its enrollment window is open.
+// @Tags         Instructor - Cohorts
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true  "Course ID"
+// @Param        cohort body      cohortRequest true "Cohort name and windows"
+// @Success      201    {object}  model.Cohort
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /instructor/courses/{id}/cohorts [post]
+// @Security     BearerAuth
+// CreateCohort handles requests to add a cohort to a course
+func (h *CourseHandler) CreateCohort(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+
+	var req cohortRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cohort, err := req.cohort(courseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.CreateCohort(cohort); err != nil {
+		http.Error(w, "Failed to create cohort", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cohort)
+}
+
+// @Summary      Update a cohort (Instructor only)
+// @Description  Changes the name and windows of a cohort. Students already enrolled keep their enrollment and follow the new access window.
+// @Tags         Instructor - Cohorts
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        cohortId path      string  true  "Cohort ID"
+// @Param        cohort   body      cohortRequest true "Cohort name and windows"
+// @Success      200      {object}  model.Cohort
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /instructor/courses/{id}/cohorts/{cohortId} [put]
+// @Security     BearerAuth
+// UpdateCohort handles requests to change a cohort
+func (h *CourseHandler) UpdateCohort(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	cohortID := chi.URLParam(r, "cohortId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+	if uuid.Validate(cohortID) != nil {
+		http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+		return
+	}
+
+	var req cohortRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cohort, err := req.cohort(courseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cohort.ID = cohortID
+
+	if err := h.Repo.UpdateCohort(cohort); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update cohort", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(cohort)
+}
+
+// @Summary      Delete a cohort (Instructor only)
+// @Description  Deletes a cohort that has no enrolled students. Students waiting for a seat in it are removed from the waitlist.
+// @Tags         Instructor - Cohorts
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        cohortId path      string  true  "Cohort ID"
+// @Success      200      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      409      {object}  map[string]string "Cohort still has enrolled students"
+// @Failure      500      {object}  map[string]string
+// @Router       /instructor/courses/{id}/cohorts/{cohortId} [delete]
+// @Security     BearerAuth
+// DeleteCohort handles requests to delete a cohort
+func (h *CourseHandler) DeleteCohort(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	cohortID := chi.URLParam(r, "cohortId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+	if uuid.Validate(cohortID) != nil {
+		http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.DeleteCohort(courseID, cohortID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+			return
+		}
+		// Code '23503' is the standard PostgreSQL error code for foreign key violation.
+		if strings.Contains(err.Error(), "23503") {
+			http.Error(w, "Cohort still has enrolled students", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to delete cohort", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Cohort deleted successfully"})
+}
@@ -0,0 +1,485 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type waitlistResponse struct {
+	Message          string `json:"message" example:"Course is full, you have been added to the waitlist"`
+	WaitlistPosition int    `json:"waitlist_position" example:"3"`
+}
+
+type setCapacityRequest struct {
+	Capacity *int `json:"capacity" example:"30"` // null removes the limit
+}
+
+type reorderWaitlistRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+type addStudentRequest struct {
+	Email    string `json:"email" example:"student@example.com"`
+	CohortID string `json:"cohort_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+}
+
+// authorizeEnrollment checks that the logged-in student is enrolled in the course and, for cohort
+// enrollments, that the cohort's access window is open. It writes the error response and returns
+// false when access is denied.
+func authorizeEnrollment(repo *repository.CourseRepository, w http.ResponseWriter, r *http.Request, courseID string) (string, bool) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return "", false
+	}
+
+	access, err := repo.GetEnrollmentAccess(studentID, courseID)
+	if err != nil {
+		http.Error(w, "Failed to verify enrollment", http.StatusInternalServerError)
+		return "", false
+	}
+	if access == nil {
+		http.Error(w, "Forbidden: You are not enrolled in this course", http.StatusForbidden)
+		return "", false
+	}
+
+	// Self-paced enrollments have no cohort and no access window
+	if access.CohortID == "" {
+		return studentID, true
+	}
+	now := time.Now()
+	if now.Before(access.StartsAt) {
+		http.Error(w, fmt.Sprintf("Forbidden: Access to this course opens on %s", access.StartsAt.Format(time.RFC3339)), http.StatusForbidden)
+		return "", false
+	}
+	if access.EndsAt != nil && !now.Before(*access.EndsAt) {
+		http.Error(w, fmt.Sprintf("Forbidden: Access to this course ended on %s", access.EndsAt.Format(time.RFC3339)), http.StatusForbidden)
+		return "", false
+	}
+
+	return studentID, true
+}
+
+// writeEnrollmentError responds to errors returned by the repository's enrollment methods
+func writeEnrollmentError(w http.ResponseWriter, err error, message string) {
+	var modeErr *repository.EnrollmentModeError
+	switch {
+	case err == sql.ErrNoRows:
+		http.Error(w, "Course not found", http.StatusNotFound)
+	case errors.As(err, &modeErr):
+		switch modeErr.Mode {
+		case model.EnrollmentModeApproval:
+			http.Error(w, "This course requires instructor approval, send an enrollment request instead", http.StatusForbidden)
+		case model.EnrollmentModeCode:
+			http.Error(w, "This course requires an access code", http.StatusForbidden)
+		default:
+			http.Error(w, "This course is open for enrollment, enroll directly instead", http.StatusBadRequest)
+		}
+	case errors.Is(err, repository.ErrInvalidAccessCode):
+		http.Error(w, "Invalid or expired access code", http.StatusForbidden)
+	case errors.Is(err, repository.ErrCohortRequired):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrCohortNotFound):
+		http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+	case errors.Is(err, repository.ErrEnrollmentClosed):
+		http.Error(w, "Enrollment in this cohort is not open", http.StatusForbidden)
+	case errors.Is(err, repository.ErrAlreadyEnrolled):
+		http.Error(w, "You are already enrolled in this course", http.StatusConflict)
+	case errors.Is(err, repository.ErrAlreadyWaitlisted):
+		http.Error(w, "You are already on the waitlist of this course", http.StatusConflict)
+	case errors.Is(err, repository.ErrPaymentRequired):
+		http.Error(w, "This course is paid, buy it through checkout instead", http.StatusPaymentRequired)
+	case errors.Is(err, repository.ErrFreeCourse):
+		http.Error(w, "This course is free, enroll directly instead", http.StatusBadRequest)
+	case errors.Is(err, repository.ErrCourseFull):
+		http.Error(w, "This course is full", http.StatusConflict)
+	case errors.Is(err, repository.ErrInvalidCoupon):
+		http.Error(w, "Invalid or expired coupon", http.StatusBadRequest)
+	case errors.Is(err, repository.ErrCouponUsedUp):
+		http.Error(w, "This coupon has been used up", http.StatusConflict)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// writeEnrollmentResult responds with 201 for an enrollment or 202 with the position for a waitlist entry
+func writeEnrollmentResult(w http.ResponseWriter, position int) {
+	if position > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(waitlistResponse{Message: "Course is full, you have been added to the waitlist", WaitlistPosition: position})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Successfully enrolled in the course"})
+}
+
+// @Summary      Set course capacity (Instructor only)
+// @Description  Sets the maximum number of enrolled students, or removes the limit with null. Seats freed by a higher capacity are given to the waitlist in order. Lowering the capacity never removes enrolled students.
+// @Tags         Instructor - Enrollment
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        capacity body      setCapacityRequest true "Capacity, null for unlimited"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /instructor/courses/{id}/capacity [put]
+// @Security     BearerAuth
+// SetCourseCapacity handles requests to change the seat limit of a course
+func (h *CourseHandler) SetCourseCapacity(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+
+	var req setCapacityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Capacity != nil && *req.Capacity < 1 {
+		http.Error(w, "Capacity must be at least 1, or null for unlimited", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.SetCourseCapacity(courseID, req.Capacity); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Course not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update course capacity", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Course capacity updated successfully"})
+}
+
+// @Summary      Get course waitlist (Instructor only)
+// @Description  Retrieves the students waiting for a seat, in the order they will be promoted.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.WaitlistEntry
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/waitlist [get]
+// @Security     BearerAuth
+// GetWaitlist handles requests to list the waitlist of a course
+func (h *CourseHandler) GetWaitlist(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	waitlist, err := h.Repo.GetWaitlist(courseID)
+	if err != nil {
+		http.Error(w, "Failed to fetch waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(waitlist)
+}
+
+// @Summary      Reorder course waitlist (Instructor only)
+// @Description  Sets the promotion order of the waitlist. The list must contain every waiting student exactly once.
+// @Tags         Instructor - Enrollment
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true  "Course ID"
+// @Param        order body      reorderWaitlistRequest true "User IDs in the new order"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /instructor/courses/{id}/waitlist [put]
+// @Security     BearerAuth
+// ReorderWaitlist handles requests to change the waitlist order
+func (h *CourseHandler) ReorderWaitlist(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+
+	var req reorderWaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.ReorderWaitlist(courseID, req.UserIDs); err != nil {
+		if errors.Is(err, repository.ErrWaitlistMismatch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Course not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to reorder waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Waitlist reordered successfully"})
+}
+
+// @Summary      Leave a course (Student only)
+// @Description  Unenrolls the logged-in student from a course, or removes them from its waitlist. A freed seat is given to the next student on the waitlist.
+// @Tags         Student
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string "Not enrolled in or waitlisted for this course"
+// @Failure      500  {object}  map[string]string
+// @Router       /courses/{id}/enroll [delete]
+// @Security     BearerAuth
+// LeaveCourse handles requests from students to leave a course
+func (h *CourseHandler) LeaveCourse(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+
+	courseID := chi.URLParam(r, "id")
+
+	if err := h.Repo.Unenroll(studentID, studentID, courseID, model.EnrollmentActionLeft); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "You are not enrolled in or waitlisted for this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to leave course", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "You have left the course"})
+}
+
+// @Summary      Get course roster (Instructor only)
+// @Description  Retrieves a page of the students enrolled in a course with their enrollment dates. The total is returned in X-Total-Count and the next page in the Link header.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id           path      string  true   "Course ID"
+// @Param        cohort_id    query     string  false  "Only students of this cohort"
+// @Param        limit        query     int     false  "Page size (1-100, default 50)"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (enrollment_date, full_name), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Enrolled on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Enrolled before (RFC3339) or on (YYYY-MM-DD)"
+// @Success      200  {array}   model.RosterEntry
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/roster [get]
+// @Security     BearerAuth
+// GetCourseRoster handles requests to list the students of a course
+func (h *CourseHandler) GetCourseRoster(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	params, err := parseListParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cohortID := r.URL.Query().Get("cohort_id")
+	if cohortID != "" && uuid.Validate(cohortID) != nil {
+		http.Error(w, "cohort_id must be a valid ID", http.StatusBadRequest)
+		return
+	}
+
+	roster, pageInfo, err := h.Repo.GetCourseRoster(courseID, cohortID, params)
+	if err != nil {
+		writeListError(w, err, "Failed to fetch roster")
+		return
+	}
+
+	writePageHeaders(w, r, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(roster)
+}
+
+// @Summary      Add a student (Instructor only)
+// @Description  Enrolls an active student account by email. Staff may enroll past the course capacity and outside a cohort's enrollment window. A waitlisted student is taken off the waitlist.
+// @Tags         Instructor - Enrollment
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string  true  "Course ID"
+// @Param        student body      addStudentRequest true "Student to enroll"
+// @Success      201     {object}  map[string]string
+// @Failure      400     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string "Course, cohort or user not found"
+// @Failure      409     {object}  map[string]string "Student is already enrolled"
+// @Failure      500     {object}  map[string]string
+// @Router       /instructor/courses/{id}/roster [post]
+// @Security     BearerAuth
+// AddStudentToCourse handles requests from staff to enroll a student
+func (h *CourseHandler) AddStudentToCourse(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageRoster) == nil {
+		return
+	}
+	actorID := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req addStudentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.CohortID != "" && uuid.Validate(req.CohortID) != nil {
+		http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+		return
+	}
+
+	// Look up the student by email
+	user, err := h.UserRepo.GetUserByEmail(strings.TrimSpace(req.Email))
+	if err != nil {
+		http.Error(w, "Failed to look up user", http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if user.Role != "student" || user.Status != "active" {
+		http.Error(w, "Only active student accounts can be enrolled", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.AddStudent(actorID, user.ID, courseID, req.CohortID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAlreadyEnrolled):
+			http.Error(w, "Student is already enrolled in this course", http.StatusConflict)
+		case errors.Is(err, repository.ErrCohortRequired):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrCohortNotFound):
+			http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to add student", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Student added successfully"})
+}
+
+// @Summary      Remove a student (Instructor only)
+// @Description  Unenrolls a student from the course, or removes them from its waitlist. A freed seat is given to the next student on the waitlist.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id     path      string  true  "Course ID"
+// @Param        userId path      string  true  "User ID of the student"
+// @Success      200    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /instructor/courses/{id}/roster/{userId} [delete]
+// @Security     BearerAuth
+// RemoveStudentFromCourse handles requests from staff to unenroll a student
+func (h *CourseHandler) RemoveStudentFromCourse(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageRoster) == nil {
+		return
+	}
+	actorID := r.Context().Value(middleware.UserIDKey).(string)
+
+	if uuid.Validate(userID) != nil {
+		http.Error(w, "Student not found in this course", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.Unenroll(actorID, userID, courseID, model.EnrollmentActionRemoved); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Student not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to remove student", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Student removed successfully"})
+}
+
+// @Summary      Get enrollment history (Instructor only)
+// @Description  Retrieves a page of every enrollment change of a course: enrollments, waitlist joins and promotions, and students added, removed or leaving. The total is returned in X-Total-Count and the next page in the Link header.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id           path      string  true   "Course ID"
+// @Param        user_id      query     string  false  "Only changes for this student"
+// @Param        limit        query     int     false  "Page size (1-100, default 50)"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Changed on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Changed before (RFC3339) or on (YYYY-MM-DD)"
+// @Success      200  {array}   model.EnrollmentEvent
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/enrollment-history [get]
+// @Security     BearerAuth
+// GetEnrollmentHistory handles requests to list the enrollment history of a course
+func (h *CourseHandler) GetEnrollmentHistory(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	params, err := parseListParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID := r.URL.Query().Get("user_id")
+	if userID != "" && uuid.Validate(userID) != nil {
+		http.Error(w, "user_id must be a valid ID", http.StatusBadRequest)
+		return
+	}
+
+	history, pageInfo, err := h.Repo.GetEnrollmentHistory(courseID, userID, params)
+	if err != nil {
+		writeListError(w, err, "Failed to fetch enrollment history")
+		return
+	}
+
+	writePageHeaders(w, r, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history)
+}
@@ -0,0 +1,481 @@
+package handler
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// accessCodeAlphabet leaves out characters that are easily confused, such as 0 and O
+	accessCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	accessCodeLength   = 8
+)
+
+type setEnrollmentModeRequest struct {
+	Mode string `json:"mode" enums:"open,approval,code"`
+}
+
+type enrollmentRequestBody struct {
+	CohortID string `json:"cohort_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+	Message  string `json:"message,omitempty" example:"I completed the prerequisite lab last term."`
+}
+
+type decideRequestResponse struct {
+	Message          string `json:"message" example:"Enrollment request approved"`
+	WaitlistPosition int    `json:"waitlist_position,omitempty" example:"0"`
+}
+
+type createAccessCodeRequest struct {
+	CohortID  string     `json:"cohort_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+	MaxUses   *int       `json:"max_uses,omitempty" example:"30"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-02-01T00:00:00Z"`
+}
+
+type redeemCodeRequest struct {
+	Code     string `json:"code" example:"K7P4QX2M"`
+	CohortID string `json:"cohort_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+}
+
+// generateAccessCode returns a random code of accessCodeLength characters
+func generateAccessCode() (string, error) {
+	buf := make([]byte, accessCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = accessCodeAlphabet[int(b)%len(accessCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// @Summary      Set enrollment mode (Instructor only)
+// @Description  Sets how students join the course: 'open' lets anyone enroll, 'approval' takes enrollment requests for staff to approve, and 'code' requires an access code. A paid course is bought through checkout and must stay 'open'.
+// @Tags         Instructor - Enrollment
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Param        mode body      setEnrollmentModeRequest true "Enrollment mode"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string "The course is paid"
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/enrollment-mode [put]
+// @Security     BearerAuth
+// SetEnrollmentMode handles requests to change how students join a course
+func (h *CourseHandler) SetEnrollmentMode(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+
+	var req setEnrollmentModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Mode != model.EnrollmentModeOpen && req.Mode != model.EnrollmentModeApproval && req.Mode != model.EnrollmentModeCode {
+		http.Error(w, "Mode must be 'open', 'approval' or 'code'", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.SetEnrollmentMode(courseID, req.Mode); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Course not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrPaidCourseMode) {
+			http.Error(w, "A paid course must use open enrollment, set its price to 0 first", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to update enrollment mode", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Enrollment mode updated successfully"})
+}
+
+// @Summary      Request enrollment (Student only)
+// @Description  Asks the staff of a course in 'approval' mode to enroll the logged-in student. Prerequisites and the cohort's enrollment window are checked when the request is sent.
+// @Tags         Student
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string  true  "Course ID"
+// @Param        request body      enrollmentRequestBody false "Cohort and a message for the instructor"
+// @Success      201     {object}  model.EnrollmentRequest
+// @Failure      400     {object}  map[string]string
+// @Failure      403     {object}  unmetPrerequisitesResponse "Prerequisites not met, the cohort's enrollment window is closed, or the course does not take requests"
+// @Failure      404     {object}  map[string]string
+// @Failure      409     {object}  map[string]string "Already enrolled, or a request is already pending"
+// @Failure      500     {object}  map[string]string
+// @Router       /courses/{id}/enrollment-requests [post]
+// @Security     BearerAuth
+// RequestEnrollment handles requests from students to join a course that requires approval
+func (h *CourseHandler) RequestEnrollment(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+
+	courseID := chi.URLParam(r, "id")
+
+	if !checkPrerequisites(h.Repo, w, studentID, courseID) {
+		return
+	}
+
+	var body enrollmentRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	request := model.EnrollmentRequest{CourseID: courseID, UserID: studentID, Message: strings.TrimSpace(body.Message)}
+	if body.CohortID != "" {
+		if uuid.Validate(body.CohortID) != nil {
+			http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+			return
+		}
+		request.CohortID = &body.CohortID
+	}
+
+	if err := h.Repo.CreateEnrollmentRequest(&request); err != nil {
+		// Code '23505' is the standard PostgreSQL error code for unique constraint violation.
+		if strings.Contains(err.Error(), "23505") {
+			http.Error(w, "You already have a pending request for this course", http.StatusConflict)
+			return
+		}
+		writeEnrollmentError(w, err, "Failed to send enrollment request")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(request)
+}
+
+// @Summary      Get enrollment requests (Instructor only)
+// @Description  Retrieves the enrollment requests of a course, oldest first.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id     path      string  true   "Course ID"
+// @Param        status query     string  false  "Only requests with this status (pending, approved, rejected)"
+// @Success      200    {array}   model.EnrollmentRequest
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /instructor/courses/{id}/enrollment-requests [get]
+// @Security     BearerAuth
+// GetEnrollmentRequests handles requests to list the enrollment requests of a course
+func (h *CourseHandler) GetEnrollmentRequests(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != model.RequestStatusPending && status != model.RequestStatusApproved && status != model.RequestStatusRejected {
+		http.Error(w, "status must be one of pending, approved, rejected", http.StatusBadRequest)
+		return
+	}
+
+	requests, err := h.Repo.GetEnrollmentRequests(courseID, status)
+	if err != nil {
+		http.Error(w, "Failed to fetch enrollment requests", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(requests)
+}
+
+// @Summary      Approve an enrollment request (Instructor only)
+// @Description  Approves a pending request and enrolls the student, or adds them to the waitlist when the course is full.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id        path      string  true  "Course ID"
+// @Param        requestId path      string  true  "Enrollment Request ID"
+// @Success      200       {object}  decideRequestResponse
+// @Failure      402       {object}  map[string]string "The course is paid and must be bought through checkout"
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      409       {object}  map[string]string "Student is already enrolled or waitlisted"
+// @Failure      500       {object}  map[string]string
+// @Router       /instructor/courses/{id}/enrollment-requests/{requestId}/approve [post]
+// @Security     BearerAuth
+// ApproveEnrollmentRequest handles requests to approve an enrollment request
+func (h *CourseHandler) ApproveEnrollmentRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideEnrollmentRequest(w, r, true)
+}
+
+// @Summary      Reject an enrollment request (Instructor only)
+// @Description  Rejects a pending enrollment request.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id        path      string  true  "Course ID"
+// @Param        requestId path      string  true  "Enrollment Request ID"
+// @Success      200       {object}  decideRequestResponse
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Router       /instructor/courses/{id}/enrollment-requests/{requestId}/reject [post]
+// @Security     BearerAuth
+// RejectEnrollmentRequest handles requests to reject an enrollment request
+func (h *CourseHandler) RejectEnrollmentRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideEnrollmentRequest(w, r, false)
+}
+
+func (h *CourseHandler) decideEnrollmentRequest(w http.ResponseWriter, r *http.Request, approve bool) {
+	courseID := chi.URLParam(r, "id")
+	requestID := chi.URLParam(r, "requestId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageRoster) == nil {
+		return
+	}
+	actorID := r.Context().Value(middleware.UserIDKey).(string)
+
+	if uuid.Validate(requestID) != nil {
+		http.Error(w, "Pending enrollment request not found", http.StatusNotFound)
+		return
+	}
+
+	position, err := h.Repo.DecideEnrollmentRequest(actorID, courseID, requestID, approve)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Pending enrollment request not found", http.StatusNotFound)
+			return
+		}
+		writeEnrollmentError(w, err, "Failed to decide enrollment request")
+		return
+	}
+
+	response := decideRequestResponse{Message: "Enrollment request rejected"}
+	if approve {
+		response = decideRequestResponse{Message: "Enrollment request approved", WaitlistPosition: position}
+		if position > 0 {
+			response.Message = "Enrollment request approved, the course is full so the student was added to the waitlist"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary      Get access codes (Instructor only)
+// @Description  Retrieves the access codes of a course with their usage.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.AccessCode
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/access-codes [get]
+// @Security     BearerAuth
+// GetAccessCodes handles requests to list the access codes of a course
+func (h *CourseHandler) GetAccessCodes(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageRoster) == nil {
+		return
+	}
+
+	codes, err := h.Repo.GetAccessCodes(courseID)
+	if err != nil {
+		http.Error(w, "Failed to fetch access codes", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(codes)
+}
+
+// @Summary      Create an access code (Instructor only)
+// @Description  Generates a new access code for a course in 'code' mode, optionally bound to a cohort, limited in uses and expiring at a given time.
+// @Tags         Instructor - Enrollment
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Param        code body      createAccessCodeRequest false "Cohort, usage limit and expiry"
+// @Success      201  {object}  model.AccessCode
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/access-codes [post]
+// @Security     BearerAuth
+// CreateAccessCode handles requests to generate an access code
+func (h *CourseHandler) CreateAccessCode(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageRoster) == nil {
+		return
+	}
+	actorID := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req createAccessCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.MaxUses != nil && *req.MaxUses < 1 {
+		http.Error(w, "max_uses must be at least 1", http.StatusBadRequest)
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		http.Error(w, "expires_at must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	code := model.AccessCode{CourseID: courseID, MaxUses: req.MaxUses, ExpiresAt: req.ExpiresAt, CreatedBy: actorID}
+	if req.CohortID != "" {
+		cohorts, err := h.Repo.GetCohortsByCourseID(courseID)
+		if err != nil {
+			http.Error(w, "Failed to verify cohort", http.StatusInternalServerError)
+			return
+		}
+		found := false
+		for _, cohort := range cohorts {
+			found = found || cohort.ID == req.CohortID
+		}
+		if !found {
+			http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+			return
+		}
+		code.CohortID = &req.CohortID
+	}
+
+	// Generated codes are random, a rare collision with an existing code is retried
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		if code.Code, err = generateAccessCode(); err != nil {
+			break
+		}
+		// Code '23505' is the standard PostgreSQL error code for unique constraint violation.
+		if err = h.Repo.CreateAccessCode(&code); err == nil || !strings.Contains(err.Error(), "23505") {
+			break
+		}
+	}
+	if err != nil {
+		http.Error(w, "Failed to create access code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(code)
+}
+
+// @Summary      Delete an access code (Instructor only)
+// @Description  Deletes an access code so it can no longer be redeemed. Students who already redeemed it stay enrolled.
+// @Tags         Instructor - Enrollment
+// @Produce      json
+// @Param        id     path      string  true  "Course ID"
+// @Param        codeId path      string  true  "Access Code ID"
+// @Success      200    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /instructor/courses/{id}/access-codes/{codeId} [delete]
+// @Security     BearerAuth
+// DeleteAccessCode handles requests to delete an access code
+func (h *CourseHandler) DeleteAccessCode(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	codeID := chi.URLParam(r, "codeId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageRoster) == nil {
+		return
+	}
+
+	if uuid.Validate(codeID) != nil {
+		http.Error(w, "Access code not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.DeleteAccessCode(courseID, codeID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Access code not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete access code", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Access code deleted successfully"})
+}
+
+// @Summary      Redeem an access code (Student only)
+// @Description  Enrolls the logged-in student into a course in 'code' mode. A code bound to a cohort enrolls into that cohort; otherwise a cohort may be given for courses that run in cohorts. When the course is full the student is added to the waitlist.
+// @Tags         Student
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Param        code body      redeemCodeRequest true "Access code"
+// @Success      201  {object}  map[string]string
+// @Success      202  {object}  waitlistResponse "Course is full, added to the waitlist"
+// @Failure      400  {object}  map[string]string
+// @Failure      402  {object}  map[string]string "The course is paid and must be bought through checkout"
+// @Failure      403  {object}  unmetPrerequisitesResponse "Prerequisites not met, invalid or expired code, or the course does not use codes"
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string "Student is already enrolled in or waitlisted for this course"
+// @Failure      500  {object}  map[string]string
+// @Router       /courses/{id}/redeem [post]
+// @Security     BearerAuth
+// RedeemAccessCode handles requests from students to enroll with an access code
+func (h *CourseHandler) RedeemAccessCode(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+
+	courseID := chi.URLParam(r, "id")
+
+	var req redeemCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if code == "" {
+		http.Error(w, "Code is required", http.StatusBadRequest)
+		return
+	}
+	if req.CohortID != "" && uuid.Validate(req.CohortID) != nil {
+		http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+		return
+	}
+
+	if !checkPrerequisites(h.Repo, w, studentID, courseID) {
+		return
+	}
+
+	position, err := h.Repo.RedeemAccessCode(studentID, courseID, code, req.CohortID)
+	if err != nil {
+		writeEnrollmentError(w, err, "Failed to redeem access code")
+		return
+	}
+
+	writeEnrollmentResult(w, position)
+}
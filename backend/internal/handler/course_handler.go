@@ -1,28 +1,36 @@
 package handler
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/dimasrizkyfebrian/coursify/internal/archive"
+	"github.com/dimasrizkyfebrian/coursify/internal/filecheck"
 	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
 	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/notify"
 	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/dimasrizkyfebrian/coursify/internal/storage"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type CourseHandler struct {
-    Repo *repository.CourseRepository
+    Repo     *repository.CourseRepository
+    UserRepo *repository.UserRepository
+    Notifier notify.Notifier
 }
 
-func NewCourseHandler(repo *repository.CourseRepository) *CourseHandler {
-    return &CourseHandler{Repo: repo}
+func NewCourseHandler(repo *repository.CourseRepository, userRepo *repository.UserRepository, notifier notify.Notifier) *CourseHandler {
+    return &CourseHandler{Repo: repo, UserRepo: userRepo, Notifier: notifier}
 }
 
 type createCourseRequest struct {
@@ -33,13 +41,45 @@ type createCourseRequest struct {
 type addMaterialRequest struct {
 	Title       string `json:"title" example:"Chapter 1: Introduction"`
 	ContentType string `json:"content_type" enums:"text,video,pdf"`
-	TextContent string `json:"text_content,omitempty" example:"This is the lesson content."`
+	TextContent string `json:"text_content,omitempty" example:"This is the **lesson** content."` // Markdown, rendered to text_html
 	VideoURL    string `json:"video_url,omitempty" example:"https://youtube.com/watch?v=..."`
 }
 
+type enrollRequest struct {
+	CohortID string `json:"cohort_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+}
+
+type setPreviewRequest struct {
+	IsPreview bool `json:"is_preview" example:"true"`
+}
+
+type addStaffRequest struct {
+	Email string `json:"email" example:"co.teacher@example.com"`
+	Role  string `json:"role" enums:"co_instructor,ta"`
+}
+
+type duplicateCourseRequest struct {
+	Title string `json:"title,omitempty" example:"Introduction to Go - Spring 2026"`
+}
+
+type setDraftRequest struct {
+	IsDraft bool `json:"is_draft" example:"false"`
+}
+
+type setTemplateRequest struct {
+	IsTemplate bool `json:"is_template" example:"true"`
+}
+
+type importCourseResponse struct {
+    Course model.Course   `json:"course"`
+    Report archive.Report `json:"report"`
+}
+
 type courseWithMaterials struct {
     model.Course
-    Materials []model.LearningMaterial `json:"materials"`
+    model.CourseContent
+    Announcements       []model.Announcement     `json:"announcements"` // Pinned first, then newest first
+    UnreadAnnouncements int                      `json:"unread_announcements"`
 }
 
 // @Summary      Create a new course (Instructor only)
@@ -92,10 +132,16 @@ func (h *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary      Get my courses (Instructor only)
-// @Description  Retrieves a list of all courses created by the logged-in instructor.
+// @Description  Retrieves a page of courses the logged-in instructor owns or co-teaches. The total is returned in X-Total-Count and the next page in the Link header.
 // @Tags         Instructor
 // @Produce      json
+// @Param        limit        query     int     false  "Page size (1-100), every item is returned when neither limit nor cursor is given"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, updated_at, title, rating), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Created before (RFC3339) or on (YYYY-MM-DD)"
 // @Success      200  {array}   model.Course
+// @Failure      400  {object}  map[string]string
 // @Failure      403  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /instructor/courses [get]
@@ -109,14 +155,21 @@ func (h *CourseHandler) GetMyCourses(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    params, err := parseWholeListParams(r)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
 	// Fetch courses owned by the instructor from the database
-    courses, err := h.Repo.GetCoursesByInstructorID(instructorID)
+    courses, pageInfo, err := h.Repo.GetCoursesByInstructorID(instructorID, params)
     if err != nil {
-        http.Error(w, "Failed to fetch courses", http.StatusInternalServerError)
+        writeListError(w, err, "Failed to fetch courses")
         return
     }
 
 	// Respond with the list of courses
+    writePageHeaders(w, r, pageInfo)
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(courses)
@@ -135,26 +188,12 @@ func (h *CourseHandler) GetMyCourses(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // GetMyCourseDetails handles requests to retrieve details of a specific course
 func (h *CourseHandler) GetMyCourseDetails(w http.ResponseWriter, r *http.Request) {
-    // Get the instructor ID from the JWT context
-    instructorID, ok := r.Context().Value(middleware.UserIDKey).(string)
-    if !ok {
-        http.Error(w, "Could not retrieve instructor ID from context", http.StatusInternalServerError)
-        return
-    }
-
     // Get the course ID from the URL parameter
     courseID := chi.URLParam(r, "id")
 
-    // Get course from repo
-    course, err := h.Repo.GetCourseByID(courseID)
-    if err != nil || course == nil {
-        http.Error(w, "Course not found", http.StatusNotFound)
-        return
-    }
-
-    // Check if the course belongs to the instructor
-    if course.InstructorID != instructorID {
-        http.Error(w, "Forbidden: You are not the owner of this course", http.StatusForbidden)
+    // Get course from repo and check access
+    course := authorizeCourse(h.Repo, w, r, courseID, permViewCourse)
+    if course == nil {
         return
     }
 
@@ -180,24 +219,11 @@ func (h *CourseHandler) GetMyCourseDetails(w http.ResponseWriter, r *http.Reques
 // @Security     BearerAuth
 // UpdateCourse handles request to edit courses
 func (h *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
-    // Get instructor ID from context JWT
-    instructorID, ok := r.Context().Value(middleware.UserIDKey).(string)
-    if !ok {
-        http.Error(w, "Could not retrieve instructor ID", http.StatusInternalServerError)
-        return
-    }
-
     // Get course id from url parameter
     courseID := chi.URLParam(r, "id")
 
-    // Check if the course exists and belongs to the instructor
-    existingCourse, err := h.Repo.GetCourseByID(courseID)
-    if err != nil || existingCourse == nil {
-        http.Error(w, "Course not found", http.StatusNotFound)
-        return
-    }
-    if existingCourse.InstructorID != instructorID {
-        http.Error(w, "Forbidden: You are not the owner of this course", http.StatusForbidden)
+    // Check if the course exists and the user may edit it
+    if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
         return
     }
 
@@ -223,7 +249,7 @@ func (h *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary      Add material to a course (Instructor only)
-// @Description  Adds a new learning material to a specific course.
+// @Description  Adds a new learning material to a specific course. The text_content of text materials is Markdown (CommonMark with tables), returned along with its sanitized HTML rendering in text_html.
 // @Tags         Instructor - Materials
 // @Accept       json
 // @Produce      json
@@ -238,22 +264,10 @@ func (h *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // AddMaterialToCourse handles request to add material to a course
 func (h *CourseHandler) AddMaterialToCourse(w http.ResponseWriter, r *http.Request) {
-    instructorID, ok := r.Context().Value(middleware.UserIDKey).(string)
-    if !ok {
-        http.Error(w, "Could not retrieve instructor ID", http.StatusInternalServerError)
-        return
-    }
-
     courseID := chi.URLParam(r, "id")
 
-    // Verify course ownership before adding material
-    existingCourse, err := h.Repo.GetCourseByID(courseID)
-    if err != nil || existingCourse == nil {
-        http.Error(w, "Course not found", http.StatusNotFound)
-        return
-    }
-    if existingCourse.InstructorID != instructorID {
-        http.Error(w, "Forbidden: You are not the owner of this course", http.StatusForbidden)
+    // Verify course access before adding material
+    if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
         return
     }
 
@@ -268,6 +282,9 @@ func (h *CourseHandler) AddMaterialToCourse(w http.ResponseWriter, r *http.Reque
         http.Error(w, "Title and content_type are required", http.StatusBadRequest)
         return
     }
+    if !checkTextContent(w, material.TextContent) {
+        return
+    }
 
     material.CourseID = courseID // Set course id from URL
 
@@ -282,11 +299,11 @@ func (h *CourseHandler) AddMaterialToCourse(w http.ResponseWriter, r *http.Reque
 }
 
 // @Summary      Get course materials (Instructor only)
-// @Description  Retrieves all learning materials for a specific course.
+// @Description  Retrieves all learning materials for a specific course arranged in its sections. Materials outside any section are listed separately.
 // @Tags         Instructor - Materials
 // @Produce      json
 // @Param        id   path      string  true  "Course ID"
-// @Success      200  {array}   model.LearningMaterial
+// @Success      200  {object}  model.CourseContent
 // @Failure      403  {object}  map[string]string
 // @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
@@ -294,40 +311,28 @@ func (h *CourseHandler) AddMaterialToCourse(w http.ResponseWriter, r *http.Reque
 // @Security     BearerAuth
 // GetMaterialsByCourseID handles request to retrieve materials of a course
 func (h *CourseHandler) GetMaterialsByCourseID(w http.ResponseWriter, r *http.Request) {
-    instructorID, ok := r.Context().Value(middleware.UserIDKey).(string)
-    if !ok {
-        http.Error(w, "Could not retrieve instructor ID", http.StatusInternalServerError)
-        return
-    }
-
     courseId := chi.URLParam(r, "id")
 
-    // Verify course ownership before retrieving materials
-    existingCourse, err := h.Repo.GetCourseByID(courseId)
-    if err != nil || existingCourse == nil {
-        http.Error(w, "Course not found", http.StatusNotFound)
-        return
-    }
-    if existingCourse.InstructorID != instructorID {
-        http.Error(w, "Forbidden: You are not the owner of this course", http.StatusForbidden)
+    // Verify course access before retrieving materials
+    if authorizeCourse(h.Repo, w, r, courseId, permViewCourse) == nil {
         return
     }
 
-    // Take material from repository
-    materials, err := h.Repo.GetMaterialsByCourseID(courseId)
+    // Take the materials and their sections from repository
+    content, err := h.Repo.GetCourseContent(courseId)
     if err != nil {
         http.Error(w, "Failed to fetch materials", http.StatusInternalServerError)
         return
     }
 
-    // Respond with the list of materials
+    // Respond with the materials arranged in their sections
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
-    json.NewEncoder(w).Encode(materials)
+    json.NewEncoder(w).Encode(content)
 }
 
 // @Summary      Update course material (Instructor only)
-// @Description  Updates a specific learning material within a course.
+// @Description  Updates a specific learning material within a course. Changing text_content renders its Markdown to text_html again.
 // @Tags         Instructor - Materials
 // @Accept       json
 // @Produce      json
@@ -343,18 +348,11 @@ func (h *CourseHandler) GetMaterialsByCourseID(w http.ResponseWriter, r *http.Re
 // @Security     BearerAuth
 // UpdateMaterial handles requests to edit course materials
 func (h *CourseHandler) UpdateMaterial(w http.ResponseWriter, r *http.Request) {
-	instructorID, _ := r.Context().Value(middleware.UserIDKey).(string)
 	courseID := chi.URLParam(r, "id")
 	materialID := chi.URLParam(r, "materialId")
 
-	// Verify course ownership
-	existingCourse, err := h.Repo.GetCourseByID(courseID)
-	if err != nil || existingCourse == nil {
-		http.Error(w, "Course not found", http.StatusNotFound)
-		return
-	}
-	if existingCourse.InstructorID != instructorID {
-		http.Error(w, "Forbidden: You are not the owner of this course", http.StatusForbidden)
+	// Verify course access
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
 		return
 	}
 
@@ -364,6 +362,9 @@ func (h *CourseHandler) UpdateMaterial(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if !checkTextContent(w, materialUpdates.TextContent) {
+		return
+	}
 
 	// Set ID from URL
 	materialUpdates.ID = materialID
@@ -398,19 +399,11 @@ func (h *CourseHandler) UpdateMaterial(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // DeleteMaterial handles requests to delete course materials
 func (h *CourseHandler) DeleteMaterial(w http.ResponseWriter, r *http.Request) {
-    // Get instructor ID from context JWT
-	instructorID, _ := r.Context().Value(middleware.UserIDKey).(string)
 	courseID := chi.URLParam(r, "id")
 	materialID := chi.URLParam(r, "materialId")
 
-	// Verify course ownership
-	existingCourse, err := h.Repo.GetCourseByID(courseID)
-	if err != nil || existingCourse == nil {
-		http.Error(w, "Course not found", http.StatusNotFound)
-		return
-	}
-	if existingCourse.InstructorID != instructorID {
-		http.Error(w, "Forbidden: You are not the owner of this course", http.StatusForbidden)
+	// Verify course access
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
 		return
 	}
 
@@ -429,36 +422,159 @@ func (h *CourseHandler) DeleteMaterial(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Material deleted successfully"})
 }
 
+// @Summary      Flag a material as free preview (Instructor only)
+// @Description  Marks or unmarks a material as a free preview item that anyone can open.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "Course ID"
+// @Param        materialId path      string  true  "Material ID"
+// @Param        preview    body      setPreviewRequest true "Preview flag"
+// @Success      200        {object}  map[string]string
+// @Failure      400        {object}  map[string]string
+// @Failure      403        {object}  map[string]string
+// @Failure      404        {object}  map[string]string
+// @Failure      500        {object}  map[string]string
+// @Router       /instructor/courses/{id}/materials/{materialId}/preview [put]
+// @Security     BearerAuth
+// SetMaterialPreview handles requests to flag materials as free previews
+func (h *CourseHandler) SetMaterialPreview(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	materialID := chi.URLParam(r, "materialId")
+
+	// Verify course access
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+
+	var req setPreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.SetMaterialPreview(courseID, materialID, req.IsPreview); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Material not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update material", http.StatusInternalServerError)
+		return
+	}
+
+    // Respond with success message
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Material preview updated successfully"})
+}
+
 // @Summary      Get public course catalog
-// @Description  Retrieves a list of all available courses for anyone to see.
+// @Description  Retrieves a page of available courses for anyone to see, with the average rating and number of visible reviews of each. The total is returned in X-Total-Count and the next page in the Link header.
 // @Tags         Public
 // @Produce      json
+// @Param        limit        query     int     false  "Page size (1-100), every item is returned when neither limit nor cursor is given"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, updated_at, title, rating), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Created before (RFC3339) or on (YYYY-MM-DD)"
 // @Success      200  {array}   model.Course
+// @Failure      400  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /courses [get]
 // GetAllCoursesPublic handles requests to retrieve public course catalog
 func (h *CourseHandler) GetAllCoursesPublic(w http.ResponseWriter, r *http.Request) {
-    courses, err := h.Repo.GetAllCourses()
+    params, err := parseWholeListParams(r)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    courses, pageInfo, err := h.Repo.GetAllCourses(params)
     if err != nil {
-        http.Error(w, "Could not fetch courses", http.StatusInternalServerError)
+        writeListError(w, err, "Could not fetch courses")
         return
     }
 
     // Respond with the list of courses
+    writePageHeaders(w, r, pageInfo)
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(courses)
 }
 
+// @Summary      Get public course details
+// @Description  Retrieves a course with its instructor name and a syllabus outline of material titles and types, without any content.
+// @Tags         Public
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {object}  model.CourseDetail
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /courses/{id} [get]
+// GetCourseDetailsPublic handles requests to retrieve the public course detail and syllabus
+func (h *CourseHandler) GetCourseDetailsPublic(w http.ResponseWriter, r *http.Request) {
+    courseID := chi.URLParam(r, "id")
+
+    detail, err := h.Repo.GetCourseDetailByID(courseID)
+    if err != nil {
+        http.Error(w, "Could not fetch course", http.StatusInternalServerError)
+        return
+    }
+    if detail == nil {
+        http.Error(w, "Course not found", http.StatusNotFound)
+        return
+    }
+
+    // Respond with the course detail and outline
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(detail)
+}
+
+// @Summary      Open a free preview material
+// @Description  Retrieves the full content of a material that the instructor flagged as a free preview.
+// @Tags         Public
+// @Produce      json
+// @Param        id         path      string  true  "Course ID"
+// @Param        materialId path      string  true  "Material ID"
+// @Success      200        {object}  model.LearningMaterial
+// @Failure      404        {object}  map[string]string "Material not found or not a preview item"
+// @Failure      500        {object}  map[string]string
+// @Router       /courses/{id}/materials/{materialId}/preview [get]
+// GetPreviewMaterial handles requests to open free preview materials
+func (h *CourseHandler) GetPreviewMaterial(w http.ResponseWriter, r *http.Request) {
+    courseID := chi.URLParam(r, "id")
+    materialID := chi.URLParam(r, "materialId")
+
+    material, err := h.Repo.GetPreviewMaterial(courseID, materialID)
+    if err != nil {
+        http.Error(w, "Could not fetch material", http.StatusInternalServerError)
+        return
+    }
+    if material == nil {
+        http.Error(w, "Preview material not found", http.StatusNotFound)
+        return
+    }
+
+    // Respond with the preview material
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(material)
+}
+
 // @Summary      Enroll in a course (Student only)
-// @Description  Enrolls the currently logged-in student into a specific course.
+// @Description  Enrolls the currently logged-in student into a specific course. Enrollment is refused with the list of unmet prerequisites when the student has not enrolled in or completed the required courses. When the course is full the student is added to the end of its waitlist and promoted automatically once a seat frees up. Courses that run in cohorts require a cohort whose enrollment window is open. Paid courses are bought through checkout instead.
 // @Tags         Student
+// @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Course ID"
+// @Param        enrollment body enrollRequest false "Cohort to enroll in, required when the course has cohorts"
 // @Success      201  {object}  map[string]string
-// @Failure      403  {object}  map[string]string
-// @Failure      404  {object}  map[string]string "Course not found (due to foreign key constraint)"
-// @Failure      409  {object}  map[string]string "Student is already enrolled in this course"
+// @Success      202  {object}  waitlistResponse "Course is full, added to the waitlist"
+// @Failure      400  {object}  map[string]string "The course runs in cohorts and no cohort was given"
+// @Failure      402  {object}  map[string]string "The course is paid"
+// @Failure      403  {object}  unmetPrerequisitesResponse "Prerequisites not met, the cohort's enrollment window is closed, or the course requires approval or an access code"
+// @Failure      404  {object}  map[string]string "Course or cohort not found, or the course is still a draft"
+// @Failure      409  {object}  map[string]string "Student is already enrolled in or waitlisted for this course"
 // @Failure      500  {object}  map[string]string
 // @Router       /courses/{id}/enroll [post]
 // @Security     BearerAuth
@@ -474,29 +590,43 @@ func (h *CourseHandler) EnrollInCourse(w http.ResponseWriter, r *http.Request) {
     // Get course id from url parameter
     courseID := chi.URLParam(r, "id")
 
-    // Call repository to register students
-    err := h.Repo.EnrollStudent(studentID, courseID)
+    // Refuse enrollment until every prerequisite is satisfied
+    if !checkPrerequisites(h.Repo, w, studentID, courseID) {
+        return
+    }
+
+    // The body is optional and only names the cohort for courses that run in cohorts
+    var req enrollRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+    if req.CohortID != "" && uuid.Validate(req.CohortID) != nil {
+        http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+        return
+    }
+
+    // Call repository to register students, a full course puts them on the waitlist
+    position, err := h.Repo.EnrollStudent(studentID, courseID, req.CohortID)
     if err != nil {
-        // Check if the error is caused by duplication (unique constraint violation)
-        // Code '23505' is the standard PostgreSQL error code for this.
-        if strings.Contains(err.Error(), "23505") {
-            http.Error(w, "You are already enrolled in this course", http.StatusConflict) // 409 Conflict
-            return
-        }
-        http.Error(w, "Failed to enroll in course", http.StatusInternalServerError)
+        writeEnrollmentError(w, err, "Failed to enroll in course")
         return
     }
 
-    // Respond with success message
-    w.WriteHeader(http.StatusCreated)
-    json.NewEncoder(w).Encode(map[string]string{"message": "Successfully enrolled in the course"})
+    writeEnrollmentResult(w, position)
 }
 
 // @Summary      Get my enrolled courses (Student only)
-// @Description  Retrieves a list of all courses the logged-in student is enrolled in.
+// @Description  Retrieves a page of courses the logged-in student is enrolled in. The total is returned in X-Total-Count and the next page in the Link header.
 // @Tags         Student
 // @Produce      json
+// @Param        limit        query     int     false  "Page size (1-100), every item is returned when neither limit nor cursor is given"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (enrollment_date, created_at, title), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Enrolled on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Enrolled before (RFC3339) or on (YYYY-MM-DD)"
 // @Success      200  {array}   model.Course
+// @Failure      400  {object}  map[string]string
 // @Failure      403  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /student/my-courses [get]
@@ -510,69 +640,76 @@ func (h *CourseHandler) GetMyEnrolledCourses(w http.ResponseWriter, r *http.Requ
         return
     }
 
+    params, err := parseWholeListParams(r)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
     // Fetch enrolled courses from the repository
-    courses, err := h.Repo.GetEnrolledCoursesByStudentID(studentID)
+    courses, pageInfo, err := h.Repo.GetEnrolledCoursesByStudentID(studentID, params)
     if err != nil {
-        http.Error(w, "Failed to fetch enrolled courses", http.StatusInternalServerError)
+        writeListError(w, err, "Failed to fetch enrolled courses")
         return
     }
 
     // Respond with the list of courses
+    writePageHeaders(w, r, pageInfo)
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(courses)
 }
 
 // @Summary      Get enrolled course details (Student only)
-// @Description  Retrieves details and all materials for a specific course the student is enrolled in.
+// @Description  Retrieves details, all materials arranged in their sections and the announcements for a specific course the student is enrolled in, with the announcements the student has not read yet. Materials not released to the student yet are locked and come without content, with the time they unlock unless they wait for another material to be completed. Students of a cohort only have access between the cohort's access start and end dates.
 // @Tags         Student
 // @Produce      json
 // @Param        id   path      string  true  "Course ID"
 // @Success      200  {object}  handler.courseWithMaterials
-// @Failure      403  {object}  map[string]string "Returned if the student is not enrolled in the course"
+// @Failure      403  {object}  map[string]string "Returned if the student is not enrolled in the course or is outside the cohort's access window"
 // @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /student/courses/{id} [get]
 // @Security     BearerAuth
 // GetEnrolledCourseDetails handles requests to retrieve enrolled course details
 func (h *CourseHandler) GetEnrolledCourseDetails(w http.ResponseWriter, r *http.Request) {
-    // Get the student ID from the JWT context
-    studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
-    if !ok {
-        http.Error(w, "Could not retrieve student ID", http.StatusInternalServerError)
-        return
-    }
-
     courseID := chi.URLParam(r, "id")
 
-    // Verify enrollment
-    isEnrolled, err := h.Repo.IsStudentEnrolled(studentID, courseID)
-    if err != nil {
-        http.Error(w, "Failed to verify enrollment", http.StatusInternalServerError)
-        return
-    }
-    if !isEnrolled {
-        http.Error(w, "Forbidden: You are not enrolled in this course", http.StatusForbidden)
+    // Verify enrollment and the cohort's access window
+    studentID, ok := authorizeEnrollment(h.Repo, w, r, courseID)
+    if !ok {
         return
     }
 
-    // Get course details and materials
+    // Get course details, materials and announcements
     course, err := h.Repo.GetCourseByID(courseID)
     if err != nil || course == nil {
         http.Error(w, "Course not found", http.StatusNotFound)
         return
     }
 
-    materials, err := h.Repo.GetMaterialsByCourseID(courseID)
+    content, err := h.Repo.GetStudentCourseContent(studentID, courseID)
     if err != nil {
         http.Error(w, "Failed to fetch materials", http.StatusInternalServerError)
         return
     }
 
+    announcements, err := h.Repo.GetAnnouncements(courseID, studentID)
+    if err != nil {
+        http.Error(w, "Failed to fetch announcements", http.StatusInternalServerError)
+        return
+    }
+
     // Combine into one response
     response := courseWithMaterials{
-        Course:    *course,
-        Materials: materials,
+        Course:        *course,
+        CourseContent: *content,
+        Announcements: announcements,
+    }
+    for _, announcement := range announcements {
+        if !announcement.IsRead {
+            response.UnreadAnnouncements++
+        }
     }
 
     // Respond with the course details and materials
@@ -582,7 +719,7 @@ func (h *CourseHandler) GetEnrolledCourseDetails(w http.ResponseWriter, r *http.
 }
 
 // @Summary      Upload a cover image for a course (Instructor only)
-// @Description  Uploads a cover image for a specific course owned by the logged-in instructor.
+// @Description  Uploads a cover image for a specific course owned by the logged-in instructor. The image must be a JPEG or PNG of up to 10MB and 40 megapixels. Its type is recognised from its content, and images that fail to decode or carry data after their end are rejected.
 // @Tags         Instructor
 // @Accept       multipart/form-data
 // @Produce      json
@@ -597,64 +734,52 @@ func (h *CourseHandler) GetEnrolledCourseDetails(w http.ResponseWriter, r *http.
 // @Security     BearerAuth
 // UploadCourseCover handles requests to upload course covers
 func (h *CourseHandler) UploadCourseCover(w http.ResponseWriter, r *http.Request) {
-    instructorID, _ := r.Context().Value(middleware.UserIDKey).(string)
     courseID := chi.URLParam(r, "id")
 
-    // Course ownership verification
-    existingCourse, err := h.Repo.GetCourseByID(courseID)
-    if err != nil || existingCourse == nil {
-        http.Error(w, "Course not found", http.StatusNotFound)
-        return
-    }
-    if existingCourse.InstructorID != instructorID {
-        http.Error(w, "Forbidden: You are not the owner of this course", http.StatusForbidden)
+    // Course access verification
+    if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
         return
     }
 
     // Parse the multipart form, with a size limit of 10 MB
-    if err := r.ParseMultipartForm(10 << 20); err != nil {
+    r.Body = http.MaxBytesReader(w, r.Body, maxUploadSizes[model.UploadKindCover]+1<<20)
+    if err := r.ParseMultipartForm(maxUploadSizes[model.UploadKindCover]); err != nil {
         http.Error(w, "File is too large. Max size is 10MB.", http.StatusBadRequest)
         return
     }
 
     // Retrieve the file from form-data
-    file, handler, err := r.FormFile("cover")
+    file, _, err := r.FormFile("cover")
     if err != nil {
         http.Error(w, "No file uploaded. Please use 'cover' as the key.", http.StatusBadRequest)
         return
     }
     defer file.Close()
 
-    // Create a unique file name to avoid conflicts
-    // Example: <courseID>-<timestamp>.<extension> -> abc-123-1678886400.png
-    ext := filepath.Ext(handler.Filename)
-    fileName := fmt.Sprintf("%s-%d%s", courseID, time.Now().Unix(), ext)
-
-    // Create an 'uploads' folder if it doesn't exist
-    if err := os.MkdirAll("./uploads", os.ModePerm); err != nil {
-        http.Error(w, "Could not create uploads directory", http.StatusInternalServerError)
+    // Check the image by its content, the client's filename and type are not trusted
+    data, err := io.ReadAll(file)
+    if err != nil {
+        http.Error(w, "Could not read the uploaded file", http.StatusBadRequest)
         return
     }
-
-    // Create a new file on the server
-    filePath := filepath.Join("uploads", fileName)
-    dst, err := os.Create(filePath)
+    mimeType, err := filecheck.Check(model.UploadKindCover, data)
     if err != nil {
-        http.Error(w, "Could not save the file", http.StatusInternalServerError)
+        http.Error(w, err.Error(), http.StatusBadRequest)
         return
     }
-    defer dst.Close()
 
-    // Copy the uploaded file's content to the new file on the server
-    if _, err := io.Copy(dst, file); err != nil {
-        http.Error(w, "Could not copy the file", http.StatusInternalServerError)
+    // Create a unique file name to avoid conflicts, with the extension of the detected type
+    // Example: <courseID>-<timestamp>.<extension> -> abc-123-1678886400.png
+    fileName := fmt.Sprintf("%s-%d%s", courseID, time.Now().Unix(), filecheck.Extension(mimeType))
+    fileURL, err := storage.Save(bytes.NewReader(data), "", fileName)
+    if err != nil {
+        http.Error(w, "Could not save the file", http.StatusInternalServerError)
         return
     }
 
-    // Save the file path to the database
-    // The saved path will become the URL, e.g., /uploads/abc-123-1678886400.png
-    fileURL := "/" + filePath
+    // Save the file URL to the database, e.g., /uploads/abc-123-1678886400.png
     if err := h.Repo.UpdateCourseCoverImage(courseID, fileURL); err != nil {
+        storage.Remove(fileURL)
         http.Error(w, "Could not update course cover image in DB", http.StatusInternalServerError)
         return
     }
@@ -665,7 +790,7 @@ func (h *CourseHandler) UploadCourseCover(w http.ResponseWriter, r *http.Request
 }
 
 // @Summary      Upload a PDF material for a course (Instructor only)
-// @Description  Uploads a PDF file as a new learning material for a course.
+// @Description  Uploads a PDF file of up to 10MB as a new learning material for a course. The file must be a readable PDF with at least one page; encrypted PDFs and PDFs containing JavaScript are rejected.
 // @Tags         Instructor - Materials
 // @Accept       multipart/form-data
 // @Produce      json
@@ -681,28 +806,22 @@ func (h *CourseHandler) UploadCourseCover(w http.ResponseWriter, r *http.Request
 // @Security     BearerAuth
 // UploadPdfMaterial handles requests to upload PDF materials
 func (h *CourseHandler) UploadPdfMaterial(w http.ResponseWriter, r *http.Request) {
-    instructorID, _ := r.Context().Value(middleware.UserIDKey).(string)
     courseID := chi.URLParam(r, "id")
 
-    // Course ownership verification
-    existingCourse, err := h.Repo.GetCourseByID(courseID)
-    if err != nil || existingCourse == nil {
-        http.Error(w, "Course not found", http.StatusNotFound)
-        return
-    }
-    if existingCourse.InstructorID != instructorID {
-        http.Error(w, "Forbidden: You are not the owner of this course", http.StatusForbidden)
+    // Course access verification
+    if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
         return
     }
 
     // Parse form, maximum size 10 MB
+    r.Body = http.MaxBytesReader(w, r.Body, 10<<20+1<<20)
     if err := r.ParseMultipartForm(10 << 20); err != nil {
         http.Error(w, "File is too large. Max size is 10MB.", http.StatusBadRequest)
         return
     }
 
     // Retrieve the file from form-data with the key 'pdf'
-    file, handler, err := r.FormFile("pdf")
+    file, _, err := r.FormFile("pdf")
     if err != nil {
         http.Error(w, "No file uploaded. Please use 'pdf' as the file key.", http.StatusBadRequest)
         return
@@ -716,28 +835,23 @@ func (h *CourseHandler) UploadPdfMaterial(w http.ResponseWriter, r *http.Request
         return
     }
 
-    // Create a unique file name
-    ext := filepath.Ext(handler.Filename)
-    fileName := fmt.Sprintf("%s-%d%s", courseID, time.Now().Unix(), ext)
-
-    // Create the 'uploads/materials' directory if it doesn't exist
-    uploadDir := filepath.Join("uploads", "materials")
-    if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-        http.Error(w, "Could not create uploads directory", http.StatusInternalServerError)
+    // Check the PDF by its content, the client's filename and type are not trusted
+    data, err := io.ReadAll(file)
+    if err != nil {
+        http.Error(w, "Could not read the uploaded file", http.StatusBadRequest)
         return
     }
-
-    // Save the file to the server
-    filePath := filepath.Join(uploadDir, fileName)
-    dst, err := os.Create(filePath)
+    mimeType, err := filecheck.Check(model.UploadKindPDF, data)
     if err != nil {
-        http.Error(w, "Could not save the file", http.StatusInternalServerError)
+        http.Error(w, err.Error(), http.StatusBadRequest)
         return
     }
-    defer dst.Close()
 
-    if _, err := io.Copy(dst, file); err != nil {
-        http.Error(w, "Could not copy the file content", http.StatusInternalServerError)
+    // Save the file under a unique name in 'uploads/materials'
+    fileName := fmt.Sprintf("%s-%d%s", courseID, time.Now().Unix(), filecheck.Extension(mimeType))
+    fileURL, err := storage.Save(bytes.NewReader(data), "materials", fileName)
+    if err != nil {
+        http.Error(w, "Could not save the file", http.StatusInternalServerError)
         return
     }
 
@@ -745,11 +859,12 @@ func (h *CourseHandler) UploadPdfMaterial(w http.ResponseWriter, r *http.Request
     material := &model.LearningMaterial{
         CourseID: courseID,
         Title:    title,
-        FileURL:  "/" + filePath, // Save as URL path
+        FileURL:  fileURL,
     }
 
     // Call the repository to create a new material entry
     if err := h.Repo.AddFileMaterialToCourse(material); err != nil {
+        storage.Remove(fileURL)
         http.Error(w, "Could not create material in database", http.StatusInternalServerError)
         return
     }
@@ -757,4 +872,555 @@ func (h *CourseHandler) UploadPdfMaterial(w http.ResponseWriter, r *http.Request
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusCreated)
     json.NewEncoder(w).Encode(material)
+}
+
+// @Summary      Get course staff (Instructor only)
+// @Description  Retrieves the owner, co-instructors and teaching assistants of a course.
+// @Tags         Instructor - Staff
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.CourseStaff
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/staff [get]
+// @Security     BearerAuth
+// GetCourseStaff handles requests to list the staff of a course
+func (h *CourseHandler) GetCourseStaff(w http.ResponseWriter, r *http.Request) {
+    courseID := chi.URLParam(r, "id")
+
+    // Verify course access
+    if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+        return
+    }
+
+    staff, err := h.Repo.GetCourseStaff(courseID)
+    if err != nil {
+        http.Error(w, "Failed to fetch course staff", http.StatusInternalServerError)
+        return
+    }
+
+    // Respond with the list of staff members
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(staff)
+}
+
+// @Summary      Invite a staff member (Instructor only)
+// @Description  Adds an active instructor account to the course as co-instructor or teaching assistant. Only the course owner may invite staff.
+// @Tags         Instructor - Staff
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true  "Course ID"
+// @Param        staff body      addStaffRequest true "Staff member to invite"
+// @Success      201   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string "Course or user not found"
+// @Failure      409   {object}  map[string]string "User is already on the course staff"
+// @Failure      500   {object}  map[string]string
+// @Router       /instructor/courses/{id}/staff [post]
+// @Security     BearerAuth
+// AddCourseStaff handles requests to invite staff to a course
+func (h *CourseHandler) AddCourseStaff(w http.ResponseWriter, r *http.Request) {
+    courseID := chi.URLParam(r, "id")
+
+    // Only roles that may manage staff can invite
+    if authorizeCourse(h.Repo, w, r, courseID, permManageStaff) == nil {
+        return
+    }
+
+    var req addStaffRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    // The owner role is reserved for the course creator
+    if req.Role != model.StaffRoleCoInstructor && req.Role != model.StaffRoleTA {
+        http.Error(w, "Role must be 'co_instructor' or 'ta'", http.StatusBadRequest)
+        return
+    }
+
+    // Look up the invited user by email
+    user, err := h.UserRepo.GetUserByEmail(strings.TrimSpace(req.Email))
+    if err != nil {
+        http.Error(w, "Failed to look up user", http.StatusInternalServerError)
+        return
+    }
+    if user == nil {
+        http.Error(w, "User not found", http.StatusNotFound)
+        return
+    }
+    if user.Role != "instructor" || user.Status != "active" {
+        http.Error(w, "Only active instructor accounts can join a course staff", http.StatusBadRequest)
+        return
+    }
+
+    if err := h.Repo.AddCourseStaff(courseID, user.ID, req.Role); err != nil {
+        // Code '23505' is the standard PostgreSQL error code for unique constraint violation.
+        if strings.Contains(err.Error(), "23505") {
+            http.Error(w, "User is already on the course staff", http.StatusConflict)
+            return
+        }
+        http.Error(w, "Failed to add staff member", http.StatusInternalServerError)
+        return
+    }
+
+    // Respond with success message
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]string{"message": "Staff member added successfully"})
+}
+
+// @Summary      Remove a staff member (Instructor only)
+// @Description  Removes a co-instructor or teaching assistant from the course. The owner cannot be removed.
+// @Tags         Instructor - Staff
+// @Produce      json
+// @Param        id     path      string  true  "Course ID"
+// @Param        userId path      string  true  "User ID of the staff member"
+// @Success      200    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /instructor/courses/{id}/staff/{userId} [delete]
+// @Security     BearerAuth
+// RemoveCourseStaff handles requests to remove staff from a course
+func (h *CourseHandler) RemoveCourseStaff(w http.ResponseWriter, r *http.Request) {
+    courseID := chi.URLParam(r, "id")
+    userID := chi.URLParam(r, "userId")
+
+    // Only roles that may manage staff can remove
+    if authorizeCourse(h.Repo, w, r, courseID, permManageStaff) == nil {
+        return
+    }
+
+    if err := h.Repo.RemoveCourseStaff(courseID, userID); err != nil {
+        if err == sql.ErrNoRows {
+            http.Error(w, "Staff member not found in this course", http.StatusNotFound)
+            return
+        }
+        http.Error(w, "Failed to remove staff member", http.StatusInternalServerError)
+        return
+    }
+
+    // Respond with success message
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]string{"message": "Staff member removed successfully"})
+}
+
+// @Summary      Duplicate a course (Instructor only)
+// @Description  Deep-copies a course, its ordered materials and its uploaded files into a new draft owned by the logged-in instructor. Staff may duplicate their own courses and any instructor may start from a template.
+// @Tags         Instructor
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true  "Source Course ID"
+// @Param        course body      duplicateCourseRequest false "Title of the copy, defaults to the source title with ' (Copy)'"
+// @Success      201    {object}  model.Course
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /instructor/courses/{id}/duplicate [post]
+// @Security     BearerAuth
+// DuplicateCourse handles requests to copy a course into a new draft
+func (h *CourseHandler) DuplicateCourse(w http.ResponseWriter, r *http.Request) {
+    instructorID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        http.Error(w, "Could not retrieve instructor ID from context", http.StatusInternalServerError)
+        return
+    }
+
+    courseID := chi.URLParam(r, "id")
+
+    source, err := h.Repo.GetCourseByID(courseID)
+    if err != nil || source == nil {
+        http.Error(w, "Course not found", http.StatusNotFound)
+        return
+    }
+
+    // Templates can be used by any instructor, other courses only by staff who may edit them
+    if !source.IsTemplate && authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+        return
+    }
+
+    // The body is optional
+    var req duplicateCourseRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    title := strings.TrimSpace(req.Title)
+    if title == "" {
+        title = source.Title + " (Copy)"
+    }
+
+    course, err := h.Repo.DuplicateCourse(courseID, instructorID, title)
+    if err != nil {
+        http.Error(w, "Failed to duplicate course", http.StatusInternalServerError)
+        return
+    }
+
+    // Give the copy its own uploaded files so deleting one course never breaks the other
+    if err := h.copyCourseFiles(course); err != nil {
+        h.Repo.DeleteCourse(course.ID)
+        http.Error(w, "Failed to copy course files", http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(course)
+}
+
+// copyCourseFiles copies the cover image and material files of a duplicated course
+// and points the copy's rows at the new files
+func (h *CourseHandler) copyCourseFiles(course *model.Course) error {
+    var copied []string
+    fail := func(err error) error {
+        storage.Remove(copied...)
+        return err
+    }
+    stamp := time.Now().Unix()
+
+    if course.CoverImageURL.Valid {
+        fileURL, err := storage.Copy(course.CoverImageURL.String, fmt.Sprintf("%s-%d", course.ID, stamp))
+        if err != nil {
+            return fail(err)
+        }
+        if fileURL != course.CoverImageURL.String {
+            copied = append(copied, fileURL)
+            if err := h.Repo.UpdateCourseCoverImage(course.ID, fileURL); err != nil {
+                return fail(err)
+            }
+            course.CoverImageURL.String = fileURL
+        }
+    }
+
+    materials, err := h.Repo.GetMaterialsByCourseID(course.ID)
+    if err != nil {
+        return fail(err)
+    }
+    for _, material := range materials {
+        if material.FileURL == "" {
+            continue
+        }
+        fileURL, err := storage.Copy(material.FileURL, fmt.Sprintf("%s-%d-%d", course.ID, stamp, material.Position))
+        if err != nil {
+            return fail(err)
+        }
+        if fileURL == material.FileURL {
+            continue
+        }
+        copied = append(copied, fileURL)
+        if err := h.Repo.UpdateMaterialFileURL(material.ID, fileURL); err != nil {
+            return fail(err)
+        }
+    }
+
+    return nil
+}
+
+// @Summary      Publish or unpublish a course (Instructor only)
+// @Description  Sets whether a course is a draft. Drafts are hidden from the public catalog and cannot be enrolled in.
+// @Tags         Instructor
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true  "Course ID"
+// @Param        draft body      setDraftRequest true "Draft flag"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /instructor/courses/{id}/draft [put]
+// @Security     BearerAuth
+// SetCourseDraft handles requests to publish or unpublish courses
+func (h *CourseHandler) SetCourseDraft(w http.ResponseWriter, r *http.Request) {
+    courseID := chi.URLParam(r, "id")
+
+    // Verify course access
+    if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+        return
+    }
+
+    var req setDraftRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    if err := h.Repo.SetCourseDraft(courseID, req.IsDraft); err != nil {
+        if err == sql.ErrNoRows {
+            http.Error(w, "Course not found", http.StatusNotFound)
+            return
+        }
+        http.Error(w, "Failed to update course", http.StatusInternalServerError)
+        return
+    }
+
+    // Respond with success message
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]string{"message": "Course draft status updated successfully"})
+}
+
+// @Summary      Get course templates (Instructor only)
+// @Description  Retrieves a page of courses marked as templates that instructors can duplicate. The total is returned in X-Total-Count and the next page in the Link header.
+// @Tags         Instructor
+// @Produce      json
+// @Param        limit        query     int     false  "Page size (1-100, default 50)"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, updated_at, title, rating), prefix with '-' for descending"
+// @Success      200  {array}   model.Course
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/templates [get]
+// @Security     BearerAuth
+// GetCourseTemplates handles requests to list course templates
+func (h *CourseHandler) GetCourseTemplates(w http.ResponseWriter, r *http.Request) {
+    params, err := parseListParams(r)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    courses, pageInfo, err := h.Repo.GetTemplateCourses(params)
+    if err != nil {
+        writeListError(w, err, "Failed to fetch templates")
+        return
+    }
+
+    // Respond with the list of templates
+    writePageHeaders(w, r, pageInfo)
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(courses)
+}
+
+// @Summary      Mark a course as template (Admin only)
+// @Description  Marks or unmarks a course as a template that any instructor can start from.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        template body      setTemplateRequest true "Template flag"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /admin/courses/{id}/template [put]
+// @Security     BearerAuth
+// SetCourseTemplate handles requests to mark courses as templates
+func (h *CourseHandler) SetCourseTemplate(w http.ResponseWriter, r *http.Request) {
+    courseID := chi.URLParam(r, "id")
+
+    var req setTemplateRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    if err := h.Repo.SetCourseTemplate(courseID, req.IsTemplate); err != nil {
+        if err == sql.ErrNoRows {
+            http.Error(w, "Course not found", http.StatusNotFound)
+            return
+        }
+        http.Error(w, "Failed to update course", http.StatusInternalServerError)
+        return
+    }
+
+    // Respond with success message
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]string{"message": "Course template status updated successfully"})
+}
+
+// maxArchiveSize caps the size of an uploaded course archive
+const maxArchiveSize = 512 << 20
+
+// @Summary      Export a course archive (Instructor only)
+// @Description  Downloads a zip archive with a manifest of the course and its ordered materials plus every referenced uploaded file.
+// @Tags         Instructor
+// @Produce      application/zip
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {file}    file
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/export [get]
+// @Security     BearerAuth
+// ExportCourse handles requests to export a course as a portable archive
+func (h *CourseHandler) ExportCourse(w http.ResponseWriter, r *http.Request) {
+    courseID := chi.URLParam(r, "id")
+
+    // Exporting includes every material, so it needs edit access
+    course := authorizeCourse(h.Repo, w, r, courseID, permEditCourse)
+    if course == nil {
+        return
+    }
+
+    materials, err := h.Repo.GetMaterialsByCourseID(courseID)
+    if err != nil {
+        http.Error(w, "Failed to fetch materials", http.StatusInternalServerError)
+        return
+    }
+
+    // Build the archive in a temporary file so a missing upload still returns a proper error
+    tmp, err := os.CreateTemp("", "coursify-export-*.zip")
+    if err != nil {
+        http.Error(w, "Could not create export file", http.StatusInternalServerError)
+        return
+    }
+    defer os.Remove(tmp.Name())
+    defer tmp.Close()
+
+    if err := archive.Export(tmp, *course, materials); err != nil {
+        log.Printf("Error exporting course %s: %v", courseID, err)
+        http.Error(w, "Could not export course", http.StatusInternalServerError)
+        return
+    }
+    if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+        http.Error(w, "Could not read export file", http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/zip")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"course-%s.zip\"", courseID))
+    w.WriteHeader(http.StatusOK)
+    io.Copy(w, tmp)
+}
+
+// @Summary      Import a course archive (Instructor only)
+// @Description  Recreates a course from an exported archive as a draft owned by the logged-in instructor. With dry_run=true only the validation report is returned.
+// @Tags         Instructor
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        archive formData  file    true   "Course archive (.zip)"
+// @Param        dry_run query     bool    false  "Validate without importing"
+// @Success      200     {object}  archive.Report "Dry-run report"
+// @Success      201     {object}  handler.importCourseResponse
+// @Failure      400     {object}  archive.Report "The archive failed validation"
+// @Failure      403     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Router       /instructor/courses/import [post]
+// @Security     BearerAuth
+// ImportCourse handles requests to import a course archive for the logged-in instructor
+func (h *CourseHandler) ImportCourse(w http.ResponseWriter, r *http.Request) {
+    instructorID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        http.Error(w, "Could not retrieve instructor ID from context", http.StatusInternalServerError)
+        return
+    }
+
+    if !parseArchiveForm(w, r) {
+        return
+    }
+
+    h.importCourseArchive(w, r, instructorID, "archive", archive.Read)
+}
+
+// @Summary      Import a course archive for an instructor (Admin only)
+// @Description  Recreates a course from an exported archive as a draft owned by the chosen instructor. With dry_run=true only the validation report is returned.
+// @Tags         Admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        archive       formData  file    true   "Course archive (.zip)"
+// @Param        instructor_id formData  string  true   "ID of the instructor who will own the course"
+// @Param        dry_run       query     bool    false  "Validate without importing"
+// @Success      200     {object}  archive.Report "Dry-run report"
+// @Success      201     {object}  handler.importCourseResponse
+// @Failure      400     {object}  archive.Report "The archive failed validation"
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string "Instructor not found or not active"
+// @Failure      500     {object}  map[string]string
+// @Router       /admin/courses/import [post]
+// @Security     BearerAuth
+// AdminImportCourse handles requests to import a course archive under a chosen instructor
+func (h *CourseHandler) AdminImportCourse(w http.ResponseWriter, r *http.Request) {
+    if !parseArchiveForm(w, r) {
+        return
+    }
+
+    // The chosen instructor must be an active instructor account
+    instructor, err := h.UserRepo.GetUserByID(r.FormValue("instructor_id"))
+    if err != nil || instructor == nil || instructor.Role != "instructor" || instructor.Status != "active" {
+        http.Error(w, "Instructor not found", http.StatusNotFound)
+        return
+    }
+
+    h.importCourseArchive(w, r, instructor.ID, "archive", archive.Read)
+}
+
+// @Summary      Import an IMS Common Cartridge (Instructor only)
+// @Description  Recreates a course from an IMS Common Cartridge 1.3 package as a draft owned by the logged-in instructor. Web content, links and PDFs become materials in outline order; anything that cannot be mapped is listed in the report's skipped entries. With dry_run=true only the report is returned.
+// @Tags         Instructor
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        cartridge formData  file    true   "Common Cartridge package (.imscc or .zip)"
+// @Param        dry_run   query     bool    false  "Validate without importing"
+// @Success      200     {object}  archive.Report "Dry-run report"
+// @Success      201     {object}  handler.importCourseResponse
+// @Failure      400     {object}  archive.Report "The cartridge failed validation"
+// @Failure      403     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Router       /instructor/courses/import-cartridge [post]
+// @Security     BearerAuth
+// ImportCommonCartridge handles requests to import IMS Common Cartridge packages
+func (h *CourseHandler) ImportCommonCartridge(w http.ResponseWriter, r *http.Request) {
+    instructorID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        http.Error(w, "Could not retrieve instructor ID from context", http.StatusInternalServerError)
+        return
+    }
+
+    if !parseArchiveForm(w, r) {
+        return
+    }
+
+    h.importCourseArchive(w, r, instructorID, "cartridge", archive.ReadCommonCartridge)
+}
+
+// parseArchiveForm parses the multipart form of an archive upload, limited to maxArchiveSize
+func parseArchiveForm(w http.ResponseWriter, r *http.Request) bool {
+    r.Body = http.MaxBytesReader(w, r.Body, maxArchiveSize)
+    if err := r.ParseMultipartForm(32 << 20); err != nil {
+        http.Error(w, "Archive is too large or the form is invalid", http.StatusBadRequest)
+        return false
+    }
+    return true
+}
+
+// importCourseArchive reads the uploaded file at formKey with read and, unless dry_run is set, imports it for instructorID
+func (h *CourseHandler) importCourseArchive(w http.ResponseWriter, r *http.Request, instructorID, formKey string, read func(io.ReaderAt, int64) (*archive.Archive, *archive.Report)) {
+    file, fileHeader, err := r.FormFile(formKey)
+    if err != nil {
+        http.Error(w, fmt.Sprintf("No file uploaded. Please use '%s' as the file key.", formKey), http.StatusBadRequest)
+        return
+    }
+    defer file.Close()
+
+    a, report := read(file, fileHeader.Size)
+
+    // Invalid archives and dry runs both answer with the report
+    if !report.Valid || r.URL.Query().Get("dry_run") == "true" {
+        status := http.StatusOK
+        if !report.Valid {
+            status = http.StatusBadRequest
+        }
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(status)
+        json.NewEncoder(w).Encode(report)
+        return
+    }
+
+    course, err := archive.Import(h.Repo, a, instructorID)
+    if err != nil {
+        http.Error(w, "Failed to import course", http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(importCourseResponse{Course: *course, Report: *report})
 }
\ No newline at end of file
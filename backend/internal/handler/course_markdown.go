@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/markdown"
+)
+
+type previewMarkdownRequest struct {
+	TextContent string `json:"text_content" example:"# Chapter 1\n\nThis is the **lesson** content."`
+}
+
+type previewMarkdownResponse struct {
+	TextContent string `json:"text_content"`
+	TextHTML    string `json:"text_html"`
+}
+
+// checkTextContent rejects Markdown too large to be stored as a material
+func checkTextContent(w http.ResponseWriter, textContent string) bool {
+	if len(textContent) > markdown.MaxSourceLen {
+		http.Error(w, fmt.Sprintf("Text content is too long. Max size is %dMB.", markdown.MaxSourceLen>>20), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// @Summary      Preview Markdown (Instructor only)
+// @Description  Renders the Markdown of a text material to the sanitized HTML students will see, without saving anything. Text materials are written in CommonMark with tables; raw HTML is allowed but scripts, event handlers and unsafe links are removed.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        markdown body      previewMarkdownRequest true "Markdown to render"
+// @Success      200      {object}  previewMarkdownResponse
+// @Failure      400      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /instructor/markdown/preview [post]
+// @Security     BearerAuth
+// PreviewMarkdown handles requests to render Markdown without saving it
+func (h *CourseHandler) PreviewMarkdown(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, markdown.MaxSourceLen+1<<10)
+	var req previewMarkdownRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !checkTextContent(w, req.TextContent) {
+		return
+	}
+
+	textHTML, err := markdown.Render(req.TextContent)
+	if err != nil {
+		http.Error(w, "Failed to render Markdown", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(previewMarkdownResponse{TextContent: req.TextContent, TextHTML: textHTML})
+}
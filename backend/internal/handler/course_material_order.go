@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type reorderMaterialsRequest struct {
+	MaterialIDs []string `json:"material_ids"`
+}
+
+type moveMaterialRequest struct {
+	BeforeID string `json:"before_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"` // Set either before_id or after_id
+	AfterID  string `json:"after_id,omitempty"`
+}
+
+// @Summary      Reorder course materials (Instructor only)
+// @Description  Sets the order of all materials of a course. The list must contain every material exactly once. Positions are renumbered from 1 without gaps.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true  "Course ID"
+// @Param        order body      reorderMaterialsRequest true "Material IDs in the new order"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /instructor/courses/{id}/materials [put]
+// @Security     BearerAuth
+// ReorderMaterials handles requests to change the order of a course's materials
+func (h *CourseHandler) ReorderMaterials(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+
+	var req reorderMaterialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.ReorderMaterials(courseID, req.MaterialIDs); err != nil {
+		if errors.Is(err, repository.ErrMaterialOrderMismatch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Course not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to reorder materials", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Materials reordered successfully"})
+}
+
+// @Summary      Move a material (Instructor only)
+// @Description  Moves a material right before or after another material of the course. When the other material is in a different section, the moved material joins that section.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "Course ID"
+// @Param        materialId path      string  true  "Material ID"
+// @Param        move       body      moveMaterialRequest true "The material to move next to"
+// @Success      200        {object}  map[string]string
+// @Failure      400        {object}  map[string]string "Neither or both of before_id and after_id set, or the material itself"
+// @Failure      403        {object}  map[string]string
+// @Failure      404        {object}  map[string]string
+// @Failure      500        {object}  map[string]string
+// @Router       /instructor/courses/{id}/materials/{materialId}/position [put]
+// @Security     BearerAuth
+// MoveMaterial handles requests to move a material next to another one
+func (h *CourseHandler) MoveMaterial(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	materialID := chi.URLParam(r, "materialId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+
+	var req moveMaterialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if (req.BeforeID == "") == (req.AfterID == "") {
+		http.Error(w, "Set exactly one of before_id and after_id", http.StatusBadRequest)
+		return
+	}
+	anchorID, after := req.BeforeID, false
+	if req.AfterID != "" {
+		anchorID, after = req.AfterID, true
+	}
+	if uuid.Validate(materialID) != nil || uuid.Validate(anchorID) != nil {
+		http.Error(w, "Material not found in this course", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.MoveMaterial(courseID, materialID, anchorID, after); err != nil {
+		if errors.Is(err, repository.ErrMaterialMoveSelf) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Material not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to move material", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Material moved successfully"})
+}
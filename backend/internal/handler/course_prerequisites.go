@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+type addPrerequisiteRequest struct {
+	PrerequisiteID string `json:"prerequisite_id" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+	Requirement    string `json:"requirement" enums:"enrolled,completed"`
+}
+
+// unmetPrerequisitesResponse is the error body returned when enrollment is refused
+type unmetPrerequisitesResponse struct {
+	Error              string               `json:"error" example:"Prerequisites not met"`
+	UnmetPrerequisites []model.Prerequisite `json:"unmet_prerequisites"`
+}
+
+type completeMaterialResponse struct {
+	Message         string `json:"message" example:"Material marked as completed"`
+	CourseCompleted bool   `json:"course_completed"`
+}
+
+// checkPrerequisites refuses enrollment with the list of unmet prerequisites.
+// It writes the error response and returns false when any prerequisite is unmet.
+func checkPrerequisites(repo *repository.CourseRepository, w http.ResponseWriter, studentID, courseID string) bool {
+	unmet, err := repo.GetUnmetPrerequisites(studentID, courseID)
+	if err != nil {
+		http.Error(w, "Failed to verify prerequisites", http.StatusInternalServerError)
+		return false
+	}
+	if len(unmet) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(unmetPrerequisitesResponse{Error: "Prerequisites not met", UnmetPrerequisites: unmet})
+		return false
+	}
+	return true
+}
+
+// @Summary      List prerequisites (Instructor only)
+// @Description  Retrieves the prerequisite courses of a course the logged-in instructor is on the staff of.
+// @Tags         Instructor - Prerequisites
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.Prerequisite
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/prerequisites [get]
+// @Security     BearerAuth
+// GetPrerequisites handles requests to list the prerequisites of a course
+func (h *CourseHandler) GetPrerequisites(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	prerequisites, err := h.Repo.GetPrerequisites(courseID)
+	if err != nil {
+		http.Error(w, "Failed to fetch prerequisites", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(prerequisites)
+}
+
+// @Summary      Add a prerequisite (Instructor only)
+// @Description  Declares another course that students must be enrolled in or have completed before enrolling in this course. A completed course keeps counting after the student leaves it.
+// @Tags         Instructor - Prerequisites
+// @Accept       json
+// @Produce      json
+// @Param        id           path      string  true  "Course ID"
+// @Param        prerequisite body      addPrerequisiteRequest true "Prerequisite course and requirement"
+// @Success      201          {object}  map[string]string
+// @Failure      400          {object}  map[string]string "Invalid requirement, or the prerequisite would create a cycle"
+// @Failure      403          {object}  map[string]string
+// @Failure      404          {object}  map[string]string "Course or prerequisite course not found"
+// @Failure      409          {object}  map[string]string "Prerequisite already declared"
+// @Failure      500          {object}  map[string]string
+// @Router       /instructor/courses/{id}/prerequisites [post]
+// @Security     BearerAuth
+// AddPrerequisite handles requests to declare a prerequisite course
+func (h *CourseHandler) AddPrerequisite(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+
+	var req addPrerequisiteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Requirement != model.RequirementEnrolled && req.Requirement != model.RequirementCompleted {
+		http.Error(w, "Requirement must be 'enrolled' or 'completed'", http.StatusBadRequest)
+		return
+	}
+	req.PrerequisiteID = strings.TrimSpace(req.PrerequisiteID)
+	if req.PrerequisiteID == courseID {
+		http.Error(w, "A course cannot be its own prerequisite", http.StatusBadRequest)
+		return
+	}
+
+	prerequisite, err := h.Repo.GetCourseByID(req.PrerequisiteID)
+	if err != nil || prerequisite == nil || prerequisite.IsDraft {
+		http.Error(w, "Prerequisite course not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.AddPrerequisite(courseID, req.PrerequisiteID, req.Requirement); err != nil {
+		if errors.Is(err, repository.ErrPrerequisiteCycle) {
+			http.Error(w, "Prerequisite would create a cycle", http.StatusBadRequest)
+			return
+		}
+		// Code '23505' is the standard PostgreSQL error code for unique constraint violation.
+		if strings.Contains(err.Error(), "23505") {
+			http.Error(w, "Prerequisite already declared", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to add prerequisite", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Prerequisite added successfully"})
+}
+
+// @Summary      Remove a prerequisite (Instructor only)
+// @Description  Removes a prerequisite course from a course.
+// @Tags         Instructor - Prerequisites
+// @Produce      json
+// @Param        id             path      string  true  "Course ID"
+// @Param        prerequisiteId path      string  true  "Prerequisite Course ID"
+// @Success      200            {object}  map[string]string
+// @Failure      403            {object}  map[string]string
+// @Failure      404            {object}  map[string]string
+// @Failure      500            {object}  map[string]string
+// @Router       /instructor/courses/{id}/prerequisites/{prerequisiteId} [delete]
+// @Security     BearerAuth
+// RemovePrerequisite handles requests to remove a prerequisite course
+func (h *CourseHandler) RemovePrerequisite(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	prerequisiteID := chi.URLParam(r, "prerequisiteId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+
+	if err := h.Repo.RemovePrerequisite(courseID, prerequisiteID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Prerequisite not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to remove prerequisite", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Prerequisite removed successfully"})
+}
+
+// @Summary      Complete a material (Student only)
+// @Description  Marks a material of an enrolled course as completed. The course is completed once every material is, which satisfies 'completed' prerequisites of other courses. Materials that are not released to the student yet cannot be completed.
+// @Tags         Student
+// @Produce      json
+// @Param        id         path      string  true  "Course ID"
+// @Param        materialId path      string  true  "Material ID"
+// @Success      200        {object}  completeMaterialResponse
+// @Failure      403        {object}  map[string]string "Not enrolled, or the material is not released yet"
+// @Failure      404        {object}  map[string]string
+// @Failure      500        {object}  map[string]string
+// @Router       /student/courses/{id}/materials/{materialId}/complete [post]
+// @Security     BearerAuth
+// CompleteMaterial handles requests to mark a material as completed
+func (h *CourseHandler) CompleteMaterial(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	materialID := chi.URLParam(r, "materialId")
+
+	// Verify enrollment and the cohort's access window
+	studentID, ok := authorizeEnrollment(h.Repo, w, r, courseID)
+	if !ok {
+		return
+	}
+
+	completed, err := h.Repo.CompleteMaterial(studentID, courseID, materialID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Material not found in this course", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrMaterialLocked) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, "Failed to complete material", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(completeMaterialResponse{Message: "Material marked as completed", CourseCompleted: completed})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxPriceCents caps course prices to catch typos in the minor unit
+const maxPriceCents = 100_000_000
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type setCoursePriceRequest struct {
+	PriceCents int    `json:"price_cents" example:"4900"`
+	Currency   string `json:"currency" example:"USD"`
+}
+
+// @Summary      Set course price (Instructor only)
+// @Description  Sets the price of a course in the currency's minor unit, e.g. 4900 for 49.00 USD. Students buy a paid course through checkout instead of enrolling directly, so only courses with open enrollment can have a price. A price of 0 makes the course free again. Checking out after a price change opens a new order at the new price.
+// @Tags         Instructor - Enrollment
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true  "Course ID"
+// @Param        price body      setCoursePriceRequest true "Price and ISO 4217 currency"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]string "The course takes students by approval or access code"
+// @Failure      500   {object}  map[string]string
+// @Router       /instructor/courses/{id}/price [put]
+// @Security     BearerAuth
+// SetCoursePrice handles requests to change the price of a course
+func (h *CourseHandler) SetCoursePrice(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+
+	var req setCoursePriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if req.PriceCents < 0 || req.PriceCents > maxPriceCents {
+		http.Error(w, "price_cents must be between 0 and 100000000", http.StatusBadRequest)
+		return
+	}
+	if !currencyPattern.MatchString(req.Currency) {
+		http.Error(w, "Currency must be a three-letter ISO 4217 code", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.SetCoursePrice(courseID, req.PriceCents, req.Currency); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Course not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrPaidCourseMode) {
+			http.Error(w, "Only a course with open enrollment can have a price", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to update course price", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Course price updated successfully"})
+}
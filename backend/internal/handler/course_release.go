@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type setReleaseRequest struct {
+	ReleaseAt              *time.Time `json:"release_at" example:"2026-09-01T08:00:00Z"`
+	ReleaseAfterDays       *int       `json:"release_after_days" example:"7"`
+	ReleaseAfterMaterialID *string    `json:"release_after_material_id" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+}
+
+// @Summary      Schedule a material's release (Instructor only)
+// @Description  Sets when students can open a material: at release_at, release_after_days days after they enrolled, or once they completed the material release_after_material_id, which must not wait for this material itself. Set at most one of them, or none to release the material right after enrolling. A free preview with a rule is only open to the public once it is released to everyone, so never while it waits for release_after_days or release_after_material_id.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "Course ID"
+// @Param        materialId path      string  true  "Material ID"
+// @Param        release    body      setReleaseRequest true "Release rule"
+// @Success      200        {object}  map[string]string
+// @Failure      400        {object}  map[string]string "More than one rule, negative days, or the required material is not an earlier one"
+// @Failure      403        {object}  map[string]string
+// @Failure      404        {object}  map[string]string
+// @Failure      500        {object}  map[string]string
+// @Router       /instructor/courses/{id}/materials/{materialId}/release [put]
+// @Security     BearerAuth
+// SetMaterialRelease handles requests to schedule when a material is released to students
+func (h *CourseHandler) SetMaterialRelease(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	materialID := chi.URLParam(r, "materialId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+
+	var req setReleaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rules := 0
+	for _, set := range []bool{req.ReleaseAt != nil, req.ReleaseAfterDays != nil, req.ReleaseAfterMaterialID != nil} {
+		if set {
+			rules++
+		}
+	}
+	if rules > 1 {
+		http.Error(w, "Set at most one of release_at, release_after_days and release_after_material_id", http.StatusBadRequest)
+		return
+	}
+	if req.ReleaseAfterDays != nil && *req.ReleaseAfterDays < 0 {
+		http.Error(w, "release_after_days cannot be negative", http.StatusBadRequest)
+		return
+	}
+	if uuid.Validate(materialID) != nil {
+		http.Error(w, "Material not found in this course", http.StatusNotFound)
+		return
+	}
+	if req.ReleaseAfterMaterialID != nil && uuid.Validate(*req.ReleaseAfterMaterialID) != nil {
+		http.Error(w, repository.ErrReleaseMaterial.Error(), http.StatusBadRequest)
+		return
+	}
+
+	material := &model.LearningMaterial{
+		ID:                     materialID,
+		CourseID:               courseID,
+		ReleaseAt:              req.ReleaseAt,
+		ReleaseAfterDays:       req.ReleaseAfterDays,
+		ReleaseAfterMaterialID: req.ReleaseAfterMaterialID,
+	}
+	if err := h.Repo.SetMaterialRelease(material); err != nil {
+		if errors.Is(err, repository.ErrReleaseMaterial) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Material not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to schedule material release", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Material release updated successfully"})
+}
@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type sectionRequest struct {
+	Title    string  `json:"title" example:"Week 1: Getting started"`
+	ParentID *string `json:"parent_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"` // Leave out for a top-level section
+}
+
+type reorderSectionsRequest struct {
+	ParentID   *string  `json:"parent_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"` // Leave out to reorder the top-level sections
+	SectionIDs []string `json:"section_ids"`
+}
+
+type setMaterialSectionRequest struct {
+	SectionID *string `json:"section_id" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"` // null moves the material out of any section
+}
+
+// section validates the request and converts it into a section of courseID
+func (req sectionRequest) section(courseID string) (*model.Section, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len(title) > 255 {
+		return nil, errors.New("Title must be between 1 and 255 characters")
+	}
+	if req.ParentID != nil && uuid.Validate(*req.ParentID) != nil {
+		return nil, errors.New("parent_id must be a section ID")
+	}
+	return &model.Section{CourseID: courseID, ParentID: req.ParentID, Title: title}, nil
+}
+
+// writeSectionError maps the errors of section changes to responses
+func writeSectionError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrSectionNesting), errors.Is(err, repository.ErrSectionOrderMismatch):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err == sql.ErrNoRows:
+		http.Error(w, "Section not found in this course", http.StatusNotFound)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// @Summary      Get course sections (Instructor only)
+// @Description  Retrieves the sections of a course as a tree, each top-level section with its subsections. Materials are left out, get them with the course materials.
+// @Tags         Instructor - Materials
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.Section
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/sections [get]
+// @Security     BearerAuth
+// GetSections handles requests to list the sections of a course
+func (h *CourseHandler) GetSections(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	sections, err := h.Repo.GetSectionTree(courseID)
+	if err != nil {
+		http.Error(w, "Failed to fetch sections", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sections)
+}
+
+// @Summary      Create a section (Instructor only)
+// @Description  Adds a section at the end of the top level, or at the end of a top-level section's subsections when parent_id is set. Sections nest one level.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string  true  "Course ID"
+// @Param        section body      sectionRequest true "Section title and parent"
+// @Success      201     {object}  model.Section
+// @Failure      400     {object}  map[string]string "Invalid title, or the parent is a subsection"
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string "Course or parent section not found"
+// @Failure      500     {object}  map[string]string
+// @Router       /instructor/courses/{id}/sections [post]
+// @Security     BearerAuth
+// CreateSection handles requests to add a section to a course
+func (h *CourseHandler) CreateSection(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+
+	var req sectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	section, err := req.section(courseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.CreateSection(section); err != nil {
+		writeSectionError(w, err, "Failed to create section")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(section)
+}
+
+// @Summary      Reorder sections (Instructor only)
+// @Description  Sets the order of the top-level sections, or of the subsections of parent_id. The list must contain every section of that level exactly once.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true  "Course ID"
+// @Param        order body      reorderSectionsRequest true "Section IDs in the new order"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /instructor/courses/{id}/sections [put]
+// @Security     BearerAuth
+// ReorderSections handles requests to change the order of sections
+func (h *CourseHandler) ReorderSections(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+
+	var req reorderSectionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ParentID != nil && uuid.Validate(*req.ParentID) != nil {
+		http.Error(w, "parent_id must be a section ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.ReorderSections(courseID, req.ParentID, req.SectionIDs); err != nil {
+		writeSectionError(w, err, "Failed to reorder sections")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Sections reordered successfully"})
+}
+
+// @Summary      Update a section (Instructor only)
+// @Description  Renames a section and moves it under parent_id, or to the top level when parent_id is left out. A moved section goes to the end of its new level. Sections with subsections stay at the top level.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string  true  "Course ID"
+// @Param        sectionId path      string  true  "Section ID"
+// @Param        section   body      sectionRequest true "Section title and parent"
+// @Success      200       {object}  model.Section
+// @Failure      400       {object}  map[string]string "Invalid title, or the move would nest sections two levels deep"
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Router       /instructor/courses/{id}/sections/{sectionId} [put]
+// @Security     BearerAuth
+// UpdateSection handles requests to rename or move a section
+func (h *CourseHandler) UpdateSection(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	sectionID := chi.URLParam(r, "sectionId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+	if uuid.Validate(sectionID) != nil {
+		http.Error(w, "Section not found in this course", http.StatusNotFound)
+		return
+	}
+
+	var req sectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	section, err := req.section(courseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	section.ID = sectionID
+
+	if err := h.Repo.UpdateSection(section); err != nil {
+		writeSectionError(w, err, "Failed to update section")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(section)
+}
+
+// @Summary      Delete a section (Instructor only)
+// @Description  Deletes a section without subsections. Its materials move to the parent section, or out of any section for a top-level section.
+// @Tags         Instructor - Materials
+// @Produce      json
+// @Param        id        path      string  true  "Course ID"
+// @Param        sectionId path      string  true  "Section ID"
+// @Success      200       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      409       {object}  map[string]string "Section still has subsections"
+// @Failure      500       {object}  map[string]string
+// @Router       /instructor/courses/{id}/sections/{sectionId} [delete]
+// @Security     BearerAuth
+// DeleteSection handles requests to delete a section
+func (h *CourseHandler) DeleteSection(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	sectionID := chi.URLParam(r, "sectionId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+	if uuid.Validate(sectionID) != nil {
+		http.Error(w, "Section not found in this course", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.DeleteSection(courseID, sectionID); err != nil {
+		// Code '23503' is the standard PostgreSQL error code for foreign key violation.
+		if strings.Contains(err.Error(), "23503") {
+			http.Error(w, "Section still has subsections", http.StatusConflict)
+			return
+		}
+		writeSectionError(w, err, "Failed to delete section")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Section deleted successfully"})
+}
+
+// @Summary      Move a material to a section (Instructor only)
+// @Description  Moves a material into a section of its course, or out of any section when section_id is null.
+// @Tags         Instructor - Materials
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "Course ID"
+// @Param        materialId path      string  true  "Material ID"
+// @Param        section    body      setMaterialSectionRequest true "Target section"
+// @Success      200        {object}  map[string]string
+// @Failure      400        {object}  map[string]string
+// @Failure      403        {object}  map[string]string
+// @Failure      404        {object}  map[string]string "Material or section not found in this course"
+// @Failure      500        {object}  map[string]string
+// @Router       /instructor/courses/{id}/materials/{materialId}/section [put]
+// @Security     BearerAuth
+// SetMaterialSection handles requests to move a material between sections
+func (h *CourseHandler) SetMaterialSection(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	materialID := chi.URLParam(r, "materialId")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+
+	var req setMaterialSectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if uuid.Validate(materialID) != nil || (req.SectionID != nil && uuid.Validate(*req.SectionID) != nil) {
+		http.Error(w, "Material or section not found in this course", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.SetMaterialSection(courseID, materialID, req.SectionID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Material or section not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to move material", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Material moved successfully"})
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/media"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/storage"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxVideoSize caps the size of an uploaded video, the rest of the form may add up to 1 MB
+const maxVideoSize = 4 << 30
+
+// videoUpload is a video streamed from the upload form to storage
+type videoUpload struct {
+	fileURL  string
+	size     int64
+	duration time.Duration
+}
+
+// checkVideo recognises the container from the first bytes of a video and checks
+// that the declared MIME type and the file extension agree with it
+func checkVideo(header []byte, filename, contentType string) (string, error) {
+	container, err := media.Sniff(header)
+	if err != nil {
+		return "", errors.New("Unsupported video format. Upload an MP4, MOV or WebM file.")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != media.MIMEType(container) {
+		return "", fmt.Errorf("The video must be sent as %s, not %q.", media.MIMEType(container), contentType)
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !slices.Contains(media.Extensions(container), ext) {
+		return "", fmt.Errorf("The video must have one of the extensions %s.", strings.Join(media.Extensions(container), ", "))
+	}
+	return container, nil
+}
+
+// probeVideo reads the size and duration of a saved video file
+func probeVideo(path, container string) (int64, time.Duration, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	duration, err := media.Duration(file, container)
+	if err != nil {
+		return 0, 0, errors.New("Could not read the video's duration, the file may be damaged or incomplete.")
+	}
+	return info.Size(), duration, nil
+}
+
+// videoMaterial is the new material of an uploaded video
+func videoMaterial(courseID, title, fileURL string, size int64, duration time.Duration) *model.LearningMaterial {
+	material := &model.LearningMaterial{
+		CourseID:      courseID,
+		Title:         title,
+		FileURL:       fileURL,
+		FileSizeBytes: &size,
+	}
+	if duration > 0 {
+		seconds := int(duration.Round(time.Second) / time.Second)
+		material.DurationSeconds = &seconds
+	}
+	return material
+}
+
+// saveVideo checks the container and declared MIME type of an uploaded video part,
+// streams it to storage and reads its duration back from disk
+func saveVideo(part io.Reader, filename, contentType, courseID string) (*videoUpload, int, error) {
+	// Peek at the header to recognise the container without consuming it
+	buffered := bufio.NewReaderSize(part, media.SniffLen)
+	header, err := buffered.Peek(media.SniffLen)
+	if err != nil && err != io.EOF {
+		return nil, http.StatusBadRequest, errors.New("Could not read the uploaded video.")
+	}
+	container, err := checkVideo(header, filename, contentType)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	fileName := fmt.Sprintf("%s-%d%s", courseID, time.Now().Unix(), strings.ToLower(filepath.Ext(filename)))
+	fileURL, err := storage.Save(buffered, "videos", fileName)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("Video is too large. Max size is %dGB.", maxVideoSize>>30)
+		}
+		return nil, http.StatusInternalServerError, errors.New("Could not save the file")
+	}
+
+	path, _ := storage.Path(fileURL)
+	size, duration, err := probeVideo(path, container)
+	if err != nil {
+		storage.Remove(fileURL)
+		return nil, http.StatusBadRequest, err
+	}
+	return &videoUpload{fileURL: fileURL, size: size, duration: duration}, 0, nil
+}
+
+// @Summary      Upload a video material for a course (Instructor only)
+// @Description  Uploads an MP4, MOV or WebM video of up to 4GB as a new learning material. The upload is streamed to storage, so send the title field before the video. The container must match the file's MIME type and extension. The video's duration and file size are recorded on the material; WebM recordings that do not state their duration have none.
+// @Tags         Instructor - Materials
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      string  true  "Course ID"
+// @Param        title formData  string  true  "Title of the material"
+// @Param        video formData  file    true  "Video file to upload"
+// @Success      201   {object}  model.LearningMaterial
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      413   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /instructor/courses/{id}/materials/upload-video [post]
+// @Security     BearerAuth
+// UploadVideoMaterial handles requests to upload video materials
+func (h *CourseHandler) UploadVideoMaterial(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.Repo, w, r, courseID, permManageMaterials) == nil {
+		return
+	}
+
+	// Read the form part by part instead of parsing it, which would buffer the video in memory or a temp file
+	r.Body = http.MaxBytesReader(w, r.Body, maxVideoSize+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected a multipart form with a title and a video.", http.StatusBadRequest)
+		return
+	}
+
+	var title string
+	var upload *videoUpload
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if upload != nil {
+				storage.Remove(upload.fileURL)
+			}
+			http.Error(w, "Invalid multipart form.", http.StatusBadRequest)
+			return
+		}
+
+		switch part.FormName() {
+		case "title":
+			value, err := io.ReadAll(io.LimitReader(part, 1024))
+			if err != nil {
+				if upload != nil {
+					storage.Remove(upload.fileURL)
+				}
+				http.Error(w, "Invalid multipart form.", http.StatusBadRequest)
+				return
+			}
+			title = strings.TrimSpace(string(value))
+		case "video":
+			if upload != nil {
+				storage.Remove(upload.fileURL)
+				http.Error(w, "Upload one video at a time.", http.StatusBadRequest)
+				return
+			}
+			var status int
+			upload, status, err = saveVideo(part, part.FileName(), part.Header.Get("Content-Type"), courseID)
+			if err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+		}
+		part.Close()
+	}
+
+	if upload == nil {
+		http.Error(w, "No file uploaded. Please use 'video' as the file key.", http.StatusBadRequest)
+		return
+	}
+	if title == "" {
+		storage.Remove(upload.fileURL)
+		http.Error(w, "Title is required.", http.StatusBadRequest)
+		return
+	}
+
+	material := videoMaterial(courseID, title, upload.fileURL, upload.size, upload.duration)
+	if err := h.Repo.AddVideoMaterialToCourse(material); err != nil {
+		storage.Remove(upload.fileURL)
+		http.Error(w, "Could not create material in database", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(material)
+}
@@ -0,0 +1,628 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxDiscussionLength limits the body of threads and replies
+const maxDiscussionLength = 10000
+
+// discussionEditWindow is how long students may edit their own threads and replies after posting them
+const discussionEditWindow = 30 * time.Minute
+
+type DiscussionHandler struct {
+	Repo       *repository.DiscussionRepository
+	CourseRepo *repository.CourseRepository
+}
+
+func NewDiscussionHandler(repo *repository.DiscussionRepository, courseRepo *repository.CourseRepository) *DiscussionHandler {
+	return &DiscussionHandler{Repo: repo, CourseRepo: courseRepo}
+}
+
+type threadRequest struct {
+	Title      string  `json:"title" example:"Question about exercise 3"`
+	Body       string  `json:"body" example:"Why does the loop stop one item early?"`
+	MaterialID *string `json:"material_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"` // Only used when creating, leave out for a course-wide thread
+}
+
+type postRequest struct {
+	Body     string  `json:"body" example:"The range end is exclusive."`
+	ParentID *string `json:"parent_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"` // Only used when creating, leave out to reply to the thread
+}
+
+type pinThreadRequest struct {
+	Pinned bool `json:"pinned" example:"true"`
+}
+
+type lockThreadRequest struct {
+	Locked bool `json:"locked" example:"true"`
+}
+
+// validate trims the request and checks the title and body
+func (req *threadRequest) validate() error {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Body = strings.TrimSpace(req.Body)
+	switch {
+	case req.Title == "" || len(req.Title) > 255:
+		return errors.New("Title must be between 1 and 255 characters")
+	case req.Body == "" || len(req.Body) > maxDiscussionLength:
+		return errors.New("Body must be between 1 and 10000 characters")
+	}
+	return nil
+}
+
+// validate trims the request and checks the body
+func (req *postRequest) validate() error {
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" || len(req.Body) > maxDiscussionLength {
+		return errors.New("Body must be between 1 and 10000 characters")
+	}
+	return nil
+}
+
+// discussionAccess is who is taking part in a course's discussions
+type discussionAccess struct {
+	userID    string
+	moderator bool
+}
+
+// canEdit reports whether the participant may edit a thread or reply written by authorID at createdAt.
+// Moderators edit anything, authors only within the edit window.
+func (a discussionAccess) canEdit(authorID *string, createdAt time.Time) bool {
+	if a.moderator {
+		return true
+	}
+	return authorID != nil && *authorID == a.userID && time.Since(createdAt) < discussionEditWindow
+}
+
+// authorizeDiscussion lets the course staff and enrolled students into a course's discussions.
+// It writes the error response and returns false when the course is missing or access is denied.
+func (h *DiscussionHandler) authorizeDiscussion(w http.ResponseWriter, r *http.Request, courseID string) (discussionAccess, bool) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve user ID from context", http.StatusInternalServerError)
+		return discussionAccess{}, false
+	}
+
+	course, err := h.CourseRepo.GetCourseByID(courseID)
+	if err != nil || course == nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return discussionAccess{}, false
+	}
+
+	role, err := h.CourseRepo.GetCourseStaffRole(courseID, userID)
+	if err != nil {
+		http.Error(w, "Failed to verify course access", http.StatusInternalServerError)
+		return discussionAccess{}, false
+	}
+	if role != "" {
+		return discussionAccess{userID: userID, moderator: staffRoleAllows(role, permModerate)}, true
+	}
+
+	// Students follow the same enrollment and access window rules as for the course content
+	if _, ok := authorizeEnrollment(h.CourseRepo, w, r, courseID); !ok {
+		return discussionAccess{}, false
+	}
+	return discussionAccess{userID: userID}, true
+}
+
+// loadThread authorizes the participant and loads the thread from the URL.
+// It writes the error response and returns nil when access is denied or the thread is missing.
+func (h *DiscussionHandler) loadThread(w http.ResponseWriter, r *http.Request) (*model.Thread, discussionAccess) {
+	courseID := chi.URLParam(r, "id")
+	threadID := chi.URLParam(r, "threadId")
+
+	access, ok := h.authorizeDiscussion(w, r, courseID)
+	if !ok {
+		return nil, access
+	}
+	if uuid.Validate(threadID) != nil {
+		http.Error(w, "Thread not found in this course", http.StatusNotFound)
+		return nil, access
+	}
+
+	thread, err := h.Repo.GetThread(courseID, threadID, access.userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch thread", http.StatusInternalServerError)
+		return nil, access
+	}
+	if thread == nil {
+		http.Error(w, "Thread not found in this course", http.StatusNotFound)
+		return nil, access
+	}
+	return thread, access
+}
+
+// @Summary      List discussion threads
+// @Description  Retrieves the discussion threads of a course, pinned threads first and then by latest activity. Questions of the Q&A area are listed separately. Only the course staff and enrolled students have access.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id          path      string  true   "Course ID"
+// @Param        material_id query     string  false  "Only the threads about this material"
+// @Success      200  {array}   model.Thread
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /courses/{id}/threads [get]
+// @Security     BearerAuth
+// GetThreads handles requests to list the threads of a course
+func (h *DiscussionHandler) GetThreads(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	materialID := r.URL.Query().Get("material_id")
+
+	if _, ok := h.authorizeDiscussion(w, r, courseID); !ok {
+		return
+	}
+	if materialID != "" && uuid.Validate(materialID) != nil {
+		http.Error(w, "Invalid material_id", http.StatusBadRequest)
+		return
+	}
+
+	threads, err := h.Repo.GetThreads(courseID, materialID)
+	if err != nil {
+		http.Error(w, "Failed to fetch threads", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(threads)
+}
+
+// @Summary      Start a discussion thread
+// @Description  Starts a thread about the whole course, or about one of its materials when material_id is set.
+// @Tags         Discussions
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true  "Course ID"
+// @Param        thread body      threadRequest true "Thread title and body"
+// @Success      201    {object}  model.Thread
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string "Course or material not found"
+// @Failure      500    {object}  map[string]string
+// @Router       /courses/{id}/threads [post]
+// @Security     BearerAuth
+// CreateThread handles requests to start a thread
+func (h *DiscussionHandler) CreateThread(w http.ResponseWriter, r *http.Request) {
+	h.createThread(w, r, model.ThreadKindDiscussion)
+}
+
+// createThread starts a thread of the given kind in the course from the URL
+func (h *DiscussionHandler) createThread(w http.ResponseWriter, r *http.Request, kind string) {
+	courseID := chi.URLParam(r, "id")
+
+	access, ok := h.authorizeDiscussion(w, r, courseID)
+	if !ok {
+		return
+	}
+
+	var req threadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.MaterialID != nil && uuid.Validate(*req.MaterialID) != nil {
+		http.Error(w, "Material not found in this course", http.StatusNotFound)
+		return
+	}
+
+	thread := model.Thread{CourseID: courseID, MaterialID: req.MaterialID, Kind: kind, AuthorID: &access.userID, Title: req.Title, Body: req.Body}
+	if err := h.Repo.CreateThread(&thread); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Material not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to create thread", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(thread)
+}
+
+// @Summary      Get a discussion thread
+// @Description  Retrieves a thread or question with its replies nested as a tree, oldest first. The answers to a question are ordered by upvotes, with the accepted answer on top.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Thread ID"
+// @Success      200      {object}  model.ThreadDetail
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId} [get]
+// @Security     BearerAuth
+// GetThread handles requests to read a thread with its replies
+func (h *DiscussionHandler) GetThread(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+
+	posts, err := h.Repo.GetPosts(thread, access.userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch replies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.ThreadDetail{Thread: *thread, Posts: posts})
+}
+
+// @Summary      Edit a discussion thread
+// @Description  Changes the title and body of a thread. Students can edit their own threads for 30 minutes after posting them, the course staff can edit any thread.
+// @Tags         Discussions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Thread ID"
+// @Param        thread   body      threadRequest true "Thread title and body"
+// @Success      200      {object}  model.Thread
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId} [put]
+// @Security     BearerAuth
+// UpdateThread handles requests to edit a thread
+func (h *DiscussionHandler) UpdateThread(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if !access.canEdit(thread.AuthorID, thread.CreatedAt) {
+		http.Error(w, "Forbidden: You can no longer edit this thread", http.StatusForbidden)
+		return
+	}
+
+	var req threadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	thread.Title, thread.Body = req.Title, req.Body
+	if err := h.Repo.UpdateThread(thread); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Thread not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update thread", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(thread)
+}
+
+// @Summary      Delete a discussion thread (Course staff only)
+// @Description  Deletes a thread with all its replies.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Thread ID"
+// @Success      200      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId} [delete]
+// @Security     BearerAuth
+// DeleteThread handles requests from the course staff to delete a thread
+func (h *DiscussionHandler) DeleteThread(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if !access.moderator {
+		http.Error(w, "Forbidden: Only the course staff can delete threads", http.StatusForbidden)
+		return
+	}
+
+	if err := h.Repo.DeleteThread(thread.CourseID, thread.ID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Thread not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete thread", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Thread deleted successfully"})
+}
+
+// @Summary      Pin a discussion thread (Course staff only)
+// @Description  Pins a thread above the others or unpins it.
+// @Tags         Discussions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Thread ID"
+// @Param        pin      body      pinThreadRequest true "Whether the thread is pinned"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/pin [put]
+// @Security     BearerAuth
+// SetThreadPinned handles requests from the course staff to pin or unpin a thread
+func (h *DiscussionHandler) SetThreadPinned(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if !access.moderator {
+		http.Error(w, "Forbidden: Only the course staff can pin threads", http.StatusForbidden)
+		return
+	}
+
+	var req pinThreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.SetThreadPinned(thread.CourseID, thread.ID, req.Pinned); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Thread not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update thread", http.StatusInternalServerError)
+		return
+	}
+
+	message := "Thread unpinned successfully"
+	if req.Pinned {
+		message = "Thread pinned successfully"
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// @Summary      Lock a discussion thread (Course staff only)
+// @Description  Locks a thread so students can no longer reply to it, or unlocks it. The course staff can still reply to locked threads.
+// @Tags         Discussions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Thread ID"
+// @Param        lock     body      lockThreadRequest true "Whether the thread is locked"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/lock [put]
+// @Security     BearerAuth
+// SetThreadLocked handles requests from the course staff to lock or unlock a thread
+func (h *DiscussionHandler) SetThreadLocked(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if !access.moderator {
+		http.Error(w, "Forbidden: Only the course staff can lock threads", http.StatusForbidden)
+		return
+	}
+
+	var req lockThreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.SetThreadLocked(thread.CourseID, thread.ID, req.Locked); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Thread not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update thread", http.StatusInternalServerError)
+		return
+	}
+
+	message := "Thread unlocked successfully"
+	if req.Locked {
+		message = "Thread locked successfully"
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// @Summary      Reply in a discussion thread
+// @Description  Replies to a thread, or to another reply in it when parent_id is set. Students cannot reply to locked threads.
+// @Tags         Discussions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Thread ID"
+// @Param        post     body      postRequest true "Reply body and parent"
+// @Success      201      {object}  model.Post
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string "Not a participant, or the thread is locked"
+// @Failure      404      {object}  map[string]string "Thread or parent reply not found"
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/posts [post]
+// @Security     BearerAuth
+// CreatePost handles requests to reply in a thread
+func (h *DiscussionHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if thread.IsLocked && !access.moderator {
+		http.Error(w, "Forbidden: This thread is locked", http.StatusForbidden)
+		return
+	}
+
+	var req postRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.ParentID != nil && uuid.Validate(*req.ParentID) != nil {
+		http.Error(w, "Parent reply not found in this thread", http.StatusNotFound)
+		return
+	}
+
+	post := model.Post{ThreadID: thread.ID, ParentID: req.ParentID, AuthorID: &access.userID, Body: req.Body}
+	if err := h.Repo.CreatePost(&post); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Parent reply not found in this thread", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to post reply", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(post)
+}
+
+// @Summary      Edit a discussion reply
+// @Description  Changes the body of a reply. Students can edit their own replies for 30 minutes after posting them, the course staff can edit any reply.
+// @Tags         Discussions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Thread ID"
+// @Param        postId   path      string  true  "Reply ID"
+// @Param        post     body      postRequest true "Reply body"
+// @Success      200      {object}  model.Post
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/posts/{postId} [put]
+// @Security     BearerAuth
+// UpdatePost handles requests to edit a reply
+func (h *DiscussionHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	post := h.loadPost(w, r, thread.ID)
+	if post == nil {
+		return
+	}
+	if !access.canEdit(post.AuthorID, post.CreatedAt) {
+		http.Error(w, "Forbidden: You can no longer edit this reply", http.StatusForbidden)
+		return
+	}
+
+	var req postRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	post.Body = req.Body
+	if err := h.Repo.UpdatePost(post); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Reply not found in this thread", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update reply", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(post)
+}
+
+// @Summary      Delete a discussion reply (Course staff only)
+// @Description  Deletes a reply together with the replies nested under it.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Thread ID"
+// @Param        postId   path      string  true  "Reply ID"
+// @Success      200      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/posts/{postId} [delete]
+// @Security     BearerAuth
+// DeletePost handles requests from the course staff to delete a reply
+func (h *DiscussionHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if !access.moderator {
+		http.Error(w, "Forbidden: Only the course staff can delete replies", http.StatusForbidden)
+		return
+	}
+	postID := chi.URLParam(r, "postId")
+	if uuid.Validate(postID) != nil {
+		http.Error(w, "Reply not found in this thread", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.DeletePost(thread.ID, postID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Reply not found in this thread", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete reply", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Reply deleted successfully"})
+}
+
+// loadPost loads the reply from the URL.
+// It writes the error response and returns nil when the thread has no such reply.
+func (h *DiscussionHandler) loadPost(w http.ResponseWriter, r *http.Request, threadID string) *model.Post {
+	postID := chi.URLParam(r, "postId")
+	if uuid.Validate(postID) != nil {
+		http.Error(w, "Reply not found in this thread", http.StatusNotFound)
+		return nil
+	}
+
+	post, err := h.Repo.GetPost(threadID, postID)
+	if err != nil {
+		http.Error(w, "Failed to fetch reply", http.StatusInternalServerError)
+		return nil
+	}
+	if post == nil {
+		http.Error(w, "Reply not found in this thread", http.StatusNotFound)
+		return nil
+	}
+	return post
+}
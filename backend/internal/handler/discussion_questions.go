@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type acceptAnswerRequest struct {
+	PostID *string `json:"post_id" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"` // null clears the accepted answer
+}
+
+type voteResponse struct {
+	VoteCount int  `json:"vote_count" example:"3"`
+	Voted     bool `json:"voted" example:"true"`
+}
+
+// @Summary      List course questions
+// @Description  Retrieves a page of the questions in the Q&A area of a course. Sort by votes, created_at or last_activity_at, and set unanswered=true to only get questions nobody has answered yet. The total is returned in X-Total-Count and the next page in the Link header.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id           path      string  true   "Course ID"
+// @Param        unanswered   query     bool    false  "Only questions without any answer"
+// @Param        limit        query     int     false  "Page size (1-100, default 50)"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, last_activity_at, votes), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Asked on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Asked before (RFC3339) or on (YYYY-MM-DD)"
+// @Success      200  {array}   model.CourseQuestion
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /courses/{id}/questions [get]
+// @Security     BearerAuth
+// GetQuestions handles requests to list the questions of a course
+func (h *DiscussionHandler) GetQuestions(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	access, ok := h.authorizeDiscussion(w, r, courseID)
+	if !ok {
+		return
+	}
+
+	params, err := parseListParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	unanswered := r.URL.Query().Get("unanswered") == "true"
+
+	questions, pageInfo, err := h.Repo.GetQuestions(courseID, access.userID, unanswered, params)
+	if err != nil {
+		writeListError(w, err, "Failed to fetch questions")
+		return
+	}
+
+	writePageHeaders(w, r, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(questions)
+}
+
+// @Summary      Ask a question
+// @Description  Asks a question in the Q&A area of a course, about one of its materials when material_id is set. Replies to the question are its answers.
+// @Tags         Discussions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        question body      threadRequest true "Question title and body"
+// @Success      201      {object}  model.Thread
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string "Course or material not found"
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/questions [post]
+// @Security     BearerAuth
+// CreateQuestion handles requests to ask a question
+func (h *DiscussionHandler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
+	h.createThread(w, r, model.ThreadKindQuestion)
+}
+
+// @Summary      Get unanswered questions (Instructor only)
+// @Description  Retrieves a page of the questions nobody has answered yet across every course the logged-in instructor is on the staff of. The total is returned in X-Total-Count and the next page in the Link header.
+// @Tags         Instructor
+// @Produce      json
+// @Param        limit        query     int     false  "Page size (1-100, default 50)"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, last_activity_at, votes), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Asked on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Asked before (RFC3339) or on (YYYY-MM-DD)"
+// @Success      200  {array}   model.CourseQuestion
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/questions/unanswered [get]
+// @Security     BearerAuth
+// GetUnansweredQuestions handles requests from instructors to list the unanswered questions of their courses
+func (h *DiscussionHandler) GetUnansweredQuestions(w http.ResponseWriter, r *http.Request) {
+	instructorID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve instructor ID from context", http.StatusInternalServerError)
+		return
+	}
+
+	params, err := parseListParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	questions, pageInfo, err := h.Repo.GetUnansweredQuestions(instructorID, params)
+	if err != nil {
+		writeListError(w, err, "Failed to fetch questions")
+		return
+	}
+
+	writePageHeaders(w, r, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(questions)
+}
+
+// voteThread adds or removes the reader's upvote on the question from the URL
+func (h *DiscussionHandler) voteThread(w http.ResponseWriter, r *http.Request, up bool) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if thread.Kind != model.ThreadKindQuestion {
+		http.Error(w, "Only questions can be upvoted", http.StatusBadRequest)
+		return
+	}
+	if thread.AuthorID != nil && *thread.AuthorID == access.userID {
+		http.Error(w, "Forbidden: You cannot upvote your own question", http.StatusForbidden)
+		return
+	}
+
+	count, err := h.Repo.VoteThread(thread.ID, access.userID, up)
+	if err != nil {
+		http.Error(w, "Failed to record vote", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(voteResponse{VoteCount: count, Voted: up})
+}
+
+// @Summary      Upvote a question
+// @Description  Upvotes a question of the Q&A area. Each participant has one vote per question and cannot upvote their own.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Question ID"
+// @Success      200      {object}  voteResponse
+// @Failure      400      {object}  map[string]string "Not a question"
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/vote [post]
+// @Security     BearerAuth
+// VoteThread handles requests to upvote a question
+func (h *DiscussionHandler) VoteThread(w http.ResponseWriter, r *http.Request) {
+	h.voteThread(w, r, true)
+}
+
+// @Summary      Remove a question upvote
+// @Description  Takes back the reader's upvote on a question.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Question ID"
+// @Success      200      {object}  voteResponse
+// @Failure      400      {object}  map[string]string "Not a question"
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/vote [delete]
+// @Security     BearerAuth
+// UnvoteThread handles requests to remove an upvote from a question
+func (h *DiscussionHandler) UnvoteThread(w http.ResponseWriter, r *http.Request) {
+	h.voteThread(w, r, false)
+}
+
+// votePost adds or removes the reader's upvote on the answer from the URL
+func (h *DiscussionHandler) votePost(w http.ResponseWriter, r *http.Request, up bool) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if thread.Kind != model.ThreadKindQuestion {
+		http.Error(w, "Only answers to questions can be upvoted", http.StatusBadRequest)
+		return
+	}
+	post := h.loadPost(w, r, thread.ID)
+	if post == nil {
+		return
+	}
+	if post.AuthorID != nil && *post.AuthorID == access.userID {
+		http.Error(w, "Forbidden: You cannot upvote your own answer", http.StatusForbidden)
+		return
+	}
+
+	count, err := h.Repo.VotePost(post.ID, access.userID, up)
+	if err != nil {
+		http.Error(w, "Failed to record vote", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(voteResponse{VoteCount: count, Voted: up})
+}
+
+// @Summary      Upvote an answer
+// @Description  Upvotes a reply to a question. Each participant has one vote per answer and cannot upvote their own.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Question ID"
+// @Param        postId   path      string  true  "Answer ID"
+// @Success      200      {object}  voteResponse
+// @Failure      400      {object}  map[string]string "Not a question"
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/posts/{postId}/vote [post]
+// @Security     BearerAuth
+// VotePost handles requests to upvote an answer
+func (h *DiscussionHandler) VotePost(w http.ResponseWriter, r *http.Request) {
+	h.votePost(w, r, true)
+}
+
+// @Summary      Remove an answer upvote
+// @Description  Takes back the reader's upvote on an answer.
+// @Tags         Discussions
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Question ID"
+// @Param        postId   path      string  true  "Answer ID"
+// @Success      200      {object}  voteResponse
+// @Failure      400      {object}  map[string]string "Not a question"
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/posts/{postId}/vote [delete]
+// @Security     BearerAuth
+// UnvotePost handles requests to remove an upvote from an answer
+func (h *DiscussionHandler) UnvotePost(w http.ResponseWriter, r *http.Request) {
+	h.votePost(w, r, false)
+}
+
+// @Summary      Accept an answer
+// @Description  Marks a direct reply as the accepted answer to a question, or clears it when post_id is null. Comments on replies cannot be accepted. Only the asker and the course staff can accept answers.
+// @Tags         Discussions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        threadId path      string  true  "Question ID"
+// @Param        answer   body      acceptAnswerRequest true "Accepted answer"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string "Not a question, or the post is a comment on a reply"
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string "Question or answer not found"
+// @Failure      500      {object}  map[string]string
+// @Router       /courses/{id}/threads/{threadId}/accepted-answer [put]
+// @Security     BearerAuth
+// AcceptAnswer handles requests to accept an answer to a question
+func (h *DiscussionHandler) AcceptAnswer(w http.ResponseWriter, r *http.Request) {
+	thread, access := h.loadThread(w, r)
+	if thread == nil {
+		return
+	}
+	if thread.Kind != model.ThreadKindQuestion {
+		http.Error(w, "Only questions have accepted answers", http.StatusBadRequest)
+		return
+	}
+	isAsker := thread.AuthorID != nil && *thread.AuthorID == access.userID
+	if !isAsker && !access.moderator {
+		http.Error(w, "Forbidden: Only the asker and the course staff can accept answers", http.StatusForbidden)
+		return
+	}
+
+	var req acceptAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.PostID != nil && uuid.Validate(*req.PostID) != nil {
+		http.Error(w, "Answer not found in this question", http.StatusNotFound)
+		return
+	}
+
+	if err := h.Repo.SetAcceptedAnswer(thread.ID, req.PostID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Answer not found in this question", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrNestedAnswer) {
+			http.Error(w, "Only a direct reply to the question can be accepted as its answer", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to accept answer", http.StatusInternalServerError)
+		return
+	}
+
+	message := "Accepted answer cleared"
+	if req.PostID != nil {
+		message = "Answer accepted successfully"
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
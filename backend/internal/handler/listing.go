@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 100
+)
+
+// parseListParams reads the shared list query parameters:
+// limit, cursor, sort (prefix with '-' for descending), role, status, created_from and created_to
+func parseListParams(r *http.Request) (model.ListParams, error) {
+	query := r.URL.Query()
+	params := model.ListParams{
+		Limit:  defaultPageLimit,
+		Cursor: query.Get("cursor"),
+		Role:   query.Get("role"),
+		Status: query.Get("status"),
+	}
+
+	if params.Role != "" && params.Role != "admin" && params.Role != "instructor" && params.Role != "student" {
+		return params, errors.New("role must be one of admin, instructor, student")
+	}
+	if params.Status != "" && params.Status != "pending" && params.Status != "active" && params.Status != "rejected" {
+		return params, errors.New("status must be one of pending, active, rejected")
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return params, fmt.Errorf("limit must be a number between 1 and %d", maxPageLimit)
+		}
+		params.Limit = n
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		params.SortDesc = strings.HasPrefix(sort, "-")
+		params.SortBy = strings.TrimPrefix(sort, "-")
+	}
+
+	if from := query.Get("created_from"); from != "" {
+		t, _, err := parseListTime(from)
+		if err != nil {
+			return params, errors.New("created_from must be a date (YYYY-MM-DD) or RFC3339 timestamp")
+		}
+		params.CreatedFrom = t
+	}
+
+	if to := query.Get("created_to"); to != "" {
+		t, dateOnly, err := parseListTime(to)
+		if err != nil {
+			return params, errors.New("created_to must be a date (YYYY-MM-DD) or RFC3339 timestamp")
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		params.CreatedTo = t
+	}
+
+	return params, nil
+}
+
+// parseWholeListParams is parseListParams for the lists that were returned whole before they were paginated.
+// Without limit and cursor every item is returned, so callers that read the response as the complete list keep working.
+func parseWholeListParams(r *http.Request) (model.ListParams, error) {
+	params, err := parseListParams(r)
+	if err == nil && r.URL.Query().Get("limit") == "" && params.Cursor == "" {
+		params.Limit = 0
+	}
+	return params, err
+}
+
+// parseListTime accepts either a plain date or an RFC3339 timestamp
+func parseListTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// writeListError responds to errors returned by paginated repository methods
+func writeListError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		http.Error(w, "Invalid cursor", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, repository.ErrInvalidSort) {
+		http.Error(w, "Invalid sort field", http.StatusBadRequest)
+		return
+	}
+	http.Error(w, message, http.StatusInternalServerError)
+}
+
+// writePageHeaders sets X-Total-Count and, when another page exists, a Link header with rel="next"
+func writePageHeaders(w http.ResponseWriter, r *http.Request, info *model.PageInfo) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(info.Total))
+	if info.NextCursor == "" {
+		return
+	}
+
+	next := url.URL{Path: r.URL.Path}
+	query := r.URL.Query()
+	query.Set("cursor", info.NextCursor)
+	next.RawQuery = query.Encode()
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+}
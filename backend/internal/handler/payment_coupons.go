@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+type couponRequest struct {
+	Code          string     `json:"code" example:"SPRING25"`
+	DiscountType  string     `json:"discount_type" enums:"percent,fixed"`
+	DiscountValue int        `json:"discount_value" example:"25"`
+	Currency      string     `json:"currency,omitempty" example:"USD"`
+	CourseID      string     `json:"course_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+	MaxUses       *int       `json:"max_uses,omitempty" example:"100"`
+	PerUserLimit  *int       `json:"per_user_limit,omitempty" example:"1"`
+	StartsAt      *time.Time `json:"starts_at,omitempty" example:"2026-03-01T00:00:00Z"`
+	EndsAt        *time.Time `json:"ends_at,omitempty" example:"2026-04-01T00:00:00Z"`
+}
+
+// coupon validates the request and converts it into a coupon. Codes are stored upper case.
+func (req couponRequest) coupon() (*model.Coupon, error) {
+	coupon := &model.Coupon{
+		Code:          strings.ToUpper(strings.TrimSpace(req.Code)),
+		DiscountType:  req.DiscountType,
+		DiscountValue: req.DiscountValue,
+		MaxUses:       req.MaxUses,
+		PerUserLimit:  req.PerUserLimit,
+		StartsAt:      req.StartsAt,
+		EndsAt:        req.EndsAt,
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+
+	switch {
+	case !couponCodePattern.MatchString(coupon.Code):
+		return nil, errors.New("Code must be 3 to 32 letters, digits, '-' or '_'")
+	case req.DiscountType != model.CouponPercent && req.DiscountType != model.CouponFixed:
+		return nil, errors.New("discount_type must be 'percent' or 'fixed'")
+	case req.DiscountType == model.CouponPercent && (req.DiscountValue < 1 || req.DiscountValue > 100):
+		return nil, errors.New("A percent discount_value must be between 1 and 100")
+	case req.DiscountType == model.CouponFixed && (req.DiscountValue < 1 || req.DiscountValue > maxPriceCents):
+		return nil, errors.New("A fixed discount_value must be between 1 and 100000000")
+	case req.DiscountType == model.CouponFixed && !currencyPattern.MatchString(currency):
+		return nil, errors.New("A fixed discount needs a three-letter ISO 4217 currency")
+	case req.MaxUses != nil && *req.MaxUses < 1:
+		return nil, errors.New("max_uses must be at least 1")
+	case req.PerUserLimit != nil && *req.PerUserLimit < 1:
+		return nil, errors.New("per_user_limit must be at least 1")
+	case req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt):
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+
+	if req.DiscountType == model.CouponFixed {
+		coupon.Currency = &currency
+	}
+	return coupon, nil
+}
+
+// couponInCourse reports whether the coupon was created for the course
+func couponInCourse(coupon *model.Coupon, courseID string) bool {
+	return coupon != nil && coupon.CourseID != nil && *coupon.CourseID == courseID
+}
+
+// createCoupon stores the coupon created by the logged-in user and writes the response
+func (h *PaymentHandler) createCoupon(w http.ResponseWriter, r *http.Request, coupon *model.Coupon) {
+	userID := r.Context().Value(middleware.UserIDKey).(string)
+	coupon.CreatedBy = &userID
+
+	if err := h.Repo.CreateCoupon(coupon); err != nil {
+		// Code '23505' is the standard PostgreSQL error code for unique constraint violation.
+		if strings.Contains(err.Error(), "23505") {
+			http.Error(w, "Coupon code already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to create coupon", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(coupon)
+}
+
+// deleteCoupon deletes the coupon, or ends it when it was already redeemed, and writes the response
+func (h *PaymentHandler) deleteCoupon(w http.ResponseWriter, couponID string) {
+	ended, err := h.Repo.DeleteCoupon(couponID)
+	if err != nil {
+		http.Error(w, "Failed to delete coupon", http.StatusInternalServerError)
+		return
+	}
+
+	message := "Coupon deleted successfully"
+	if ended {
+		message = "Coupon has been redeemed and was ended instead of deleted"
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// writeCouponRedemptions writes the redemption report of a coupon
+func (h *PaymentHandler) writeCouponRedemptions(w http.ResponseWriter, couponID string) {
+	redemptions, err := h.Repo.GetCouponRedemptions(couponID)
+	if err != nil {
+		http.Error(w, "Failed to fetch coupon redemptions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(redemptions)
+}
+
+// @Summary      List course coupons (Instructor only)
+// @Description  Retrieves the coupons created for a course the logged-in instructor is on the staff of, newest first.
+// @Tags         Instructor - Coupons
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {array}   model.Coupon
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/courses/{id}/coupons [get]
+// @Security     BearerAuth
+// GetCourseCoupons handles requests to list the coupons of a course
+func (h *PaymentHandler) GetCourseCoupons(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.CourseRepo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+
+	coupons, err := h.Repo.GetCoupons(courseID)
+	if err != nil {
+		http.Error(w, "Failed to fetch coupons", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(coupons)
+}
+
+// @Summary      Create a course coupon (Instructor only)
+// @Description  Creates a discount code for a course. Percent discounts take a share of the price, fixed discounts an amount in the minor unit of their currency and only apply while the course is sold in it. Usage caps count paid orders.
+// @Tags         Instructor - Coupons
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true  "Course ID"
+// @Param        coupon body      couponRequest true "Coupon code, discount, limits and validity window. course_id is ignored."
+// @Success      201    {object}  model.Coupon
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      409    {object}  map[string]string "Coupon code already exists"
+// @Failure      500    {object}  map[string]string
+// @Router       /instructor/courses/{id}/coupons [post]
+// @Security     BearerAuth
+// CreateCourseCoupon handles requests to create a coupon for a course
+func (h *PaymentHandler) CreateCourseCoupon(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	if authorizeCourse(h.CourseRepo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+
+	var req couponRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	coupon, err := req.coupon()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	coupon.CourseID = &courseID
+
+	h.createCoupon(w, r, coupon)
+}
+
+// @Summary      Delete a course coupon (Instructor only)
+// @Description  Deletes a coupon of a course. A coupon that was already redeemed is kept for the redemption report and ends immediately instead.
+// @Tags         Instructor - Coupons
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        couponId path      string  true  "Coupon ID"
+// @Success      200      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /instructor/courses/{id}/coupons/{couponId} [delete]
+// @Security     BearerAuth
+// DeleteCourseCoupon handles requests to delete a coupon of a course
+func (h *PaymentHandler) DeleteCourseCoupon(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	couponID := chi.URLParam(r, "couponId")
+
+	if authorizeCourse(h.CourseRepo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+	if uuid.Validate(couponID) != nil {
+		http.Error(w, "Coupon not found in this course", http.StatusNotFound)
+		return
+	}
+
+	coupon, err := h.Repo.GetCouponByID(couponID)
+	if err != nil {
+		http.Error(w, "Failed to fetch coupon", http.StatusInternalServerError)
+		return
+	}
+	if !couponInCourse(coupon, courseID) {
+		http.Error(w, "Coupon not found in this course", http.StatusNotFound)
+		return
+	}
+
+	h.deleteCoupon(w, couponID)
+}
+
+// @Summary      Coupon redemption report (Instructor only)
+// @Description  Retrieves the paid orders that used a coupon of the course, newest first, with the discount given and the amount paid.
+// @Tags         Instructor - Coupons
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        couponId path      string  true  "Coupon ID"
+// @Success      200      {array}   model.CouponRedemption
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /instructor/courses/{id}/coupons/{couponId}/redemptions [get]
+// @Security     BearerAuth
+// GetCourseCouponRedemptions handles requests for the redemption report of a course coupon
+func (h *PaymentHandler) GetCourseCouponRedemptions(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	couponID := chi.URLParam(r, "couponId")
+
+	if authorizeCourse(h.CourseRepo, w, r, courseID, permViewCourse) == nil {
+		return
+	}
+	if uuid.Validate(couponID) != nil {
+		http.Error(w, "Coupon not found in this course", http.StatusNotFound)
+		return
+	}
+
+	coupon, err := h.Repo.GetCouponByID(couponID)
+	if err != nil {
+		http.Error(w, "Failed to fetch coupon", http.StatusInternalServerError)
+		return
+	}
+	if !couponInCourse(coupon, courseID) {
+		http.Error(w, "Coupon not found in this course", http.StatusNotFound)
+		return
+	}
+
+	h.writeCouponRedemptions(w, couponID)
+}
+
+// @Summary      List all coupons (Admin only)
+// @Description  Retrieves every coupon, site-wide and course coupons, newest first.
+// @Tags         Admin
+// @Produce      json
+// @Success      200  {array}   model.Coupon
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/coupons [get]
+// @Security     BearerAuth
+// GetAllCoupons handles requests to list every coupon
+func (h *PaymentHandler) GetAllCoupons(w http.ResponseWriter, r *http.Request) {
+	coupons, err := h.Repo.GetCoupons("")
+	if err != nil {
+		http.Error(w, "Failed to fetch coupons", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(coupons)
+}
+
+// @Summary      Create a coupon (Admin only)
+// @Description  Creates a discount code for one course, or for every paid course when course_id is left out.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        coupon body      couponRequest true "Coupon code, discount, course, limits and validity window"
+// @Success      201    {object}  model.Coupon
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string "Course not found"
+// @Failure      409    {object}  map[string]string "Coupon code already exists"
+// @Failure      500    {object}  map[string]string
+// @Router       /admin/coupons [post]
+// @Security     BearerAuth
+// AdminCreateCoupon handles requests from admins to create a coupon
+func (h *PaymentHandler) AdminCreateCoupon(w http.ResponseWriter, r *http.Request) {
+	var req couponRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	coupon, err := req.coupon()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.CourseID != "" {
+		course, err := h.CourseRepo.GetCourseByID(req.CourseID)
+		if err != nil || course == nil {
+			http.Error(w, "Course not found", http.StatusNotFound)
+			return
+		}
+		coupon.CourseID = &req.CourseID
+	}
+
+	h.createCoupon(w, r, coupon)
+}
+
+// @Summary      Delete a coupon (Admin only)
+// @Description  Deletes any coupon. A coupon that was already redeemed is kept for the redemption report and ends immediately instead.
+// @Tags         Admin
+// @Produce      json
+// @Param        couponId path      string  true  "Coupon ID"
+// @Success      200      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /admin/coupons/{couponId} [delete]
+// @Security     BearerAuth
+// AdminDeleteCoupon handles requests from admins to delete a coupon
+func (h *PaymentHandler) AdminDeleteCoupon(w http.ResponseWriter, r *http.Request) {
+	couponID := chi.URLParam(r, "couponId")
+
+	if uuid.Validate(couponID) != nil {
+		http.Error(w, "Coupon not found", http.StatusNotFound)
+		return
+	}
+	coupon, err := h.Repo.GetCouponByID(couponID)
+	if err != nil {
+		http.Error(w, "Failed to fetch coupon", http.StatusInternalServerError)
+		return
+	}
+	if coupon == nil {
+		http.Error(w, "Coupon not found", http.StatusNotFound)
+		return
+	}
+
+	h.deleteCoupon(w, couponID)
+}
+
+// @Summary      Coupon redemption report (Admin only)
+// @Description  Retrieves the paid orders that used a coupon, newest first, with the discount given and the amount paid.
+// @Tags         Admin
+// @Produce      json
+// @Param        couponId path      string  true  "Coupon ID"
+// @Success      200      {array}   model.CouponRedemption
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /admin/coupons/{couponId}/redemptions [get]
+// @Security     BearerAuth
+// AdminGetCouponRedemptions handles requests from admins for the redemption report of a coupon
+func (h *PaymentHandler) AdminGetCouponRedemptions(w http.ResponseWriter, r *http.Request) {
+	couponID := chi.URLParam(r, "couponId")
+
+	if uuid.Validate(couponID) != nil {
+		http.Error(w, "Coupon not found", http.StatusNotFound)
+		return
+	}
+	coupon, err := h.Repo.GetCouponByID(couponID)
+	if err != nil {
+		http.Error(w, "Failed to fetch coupon", http.StatusInternalServerError)
+		return
+	}
+	if coupon == nil {
+		http.Error(w, "Coupon not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeCouponRedemptions(w, couponID)
+}
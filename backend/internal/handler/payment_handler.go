@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/payment"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxWebhookSize limits the body of payment webhooks
+const maxWebhookSize = 1 << 20
+
+type checkoutRequest struct {
+	CohortID   string `json:"cohort_id,omitempty" example:"a1b2c3d4-e5f6-7890-1234-567890abcdef"`
+	CouponCode string `json:"coupon_code,omitempty" example:"SPRING25"`
+}
+
+type PaymentHandler struct {
+	Repo       *repository.OrderRepository
+	CourseRepo *repository.CourseRepository
+	Providers  map[string]payment.PaymentProvider
+	Checkouts  payment.PaymentProvider // Provider new checkouts are opened with
+}
+
+// NewPaymentHandler creates a handler that accepts webhooks of every provider
+// and opens checkouts with the first one
+func NewPaymentHandler(repo *repository.OrderRepository, courseRepo *repository.CourseRepository, providers ...payment.PaymentProvider) *PaymentHandler {
+	h := &PaymentHandler{Repo: repo, CourseRepo: courseRepo, Providers: map[string]payment.PaymentProvider{}}
+	for _, provider := range providers {
+		h.Providers[provider.Name()] = provider
+	}
+	if len(providers) > 0 {
+		h.Checkouts = providers[0]
+	}
+	return h
+}
+
+var errProviderUnavailable = errors.New("payment provider unavailable")
+
+// openCheckout opens a checkout for the order with the provider and stores it on the order.
+// The order is failed when the provider cannot open one.
+func (h *PaymentHandler) openCheckout(order *model.Order) error {
+	course, err := h.CourseRepo.GetCourseByID(order.CourseID)
+	if err != nil {
+		return err
+	}
+	if course == nil {
+		return sql.ErrNoRows
+	}
+
+	checkout, err := h.Checkouts.CreateCheckout(order, course)
+	if err != nil {
+		h.Repo.FailOrder(order.ID)
+		return errProviderUnavailable
+	}
+
+	provider := h.Checkouts.Name()
+	if err := h.Repo.SetOrderCheckout(order.ID, provider, checkout.Reference, checkout.URL); err != nil {
+		return err
+	}
+	order.Provider, order.ProviderRef, order.CheckoutURL = &provider, &checkout.Reference, &checkout.URL
+	return nil
+}
+
+// @Summary      Check out a paid course (Student only)
+// @Description  Opens a pending order for a paid course at its current price, less the discount of an optional coupon, and returns the provider's checkout URL. The student is enrolled once the provider confirms the payment through the webhook. Checking out again while an order is pending returns the same order. An order discounted to nothing is paid and enrolls the student right away, without a checkout.
+// @Tags         Student
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "Course ID"
+// @Param        checkout   body      checkoutRequest false "Cohort to enroll in, required when the course has cohorts, and a coupon code"
+// @Success      201        {object}  model.Order
+// @Failure      400        {object}  map[string]string "The course is free, runs in cohorts and no cohort was given, or the coupon is invalid"
+// @Failure      403        {object}  unmetPrerequisitesResponse "Prerequisites not met, the cohort's enrollment window is closed, or the course requires approval or an access code"
+// @Failure      404        {object}  map[string]string
+// @Failure      409        {object}  map[string]string "Already enrolled, the course is full, or the coupon has been used up"
+// @Failure      502        {object}  map[string]string "The payment provider could not open a checkout"
+// @Failure      500        {object}  map[string]string
+// @Router       /courses/{id}/checkout [post]
+// @Security     BearerAuth
+// Checkout handles requests to buy a paid course
+func (h *PaymentHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+	courseID := chi.URLParam(r, "id")
+
+	if h.Checkouts == nil {
+		http.Error(w, "Payments are not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !checkPrerequisites(h.CourseRepo, w, studentID, courseID) {
+		return
+	}
+
+	var req checkoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.CohortID != "" && uuid.Validate(req.CohortID) != nil {
+		http.Error(w, "Cohort not found in this course", http.StatusNotFound)
+		return
+	}
+
+	couponCode := strings.ToUpper(strings.TrimSpace(req.CouponCode))
+
+	order, err := h.Repo.CreateOrder(studentID, courseID, req.CohortID, couponCode)
+	if err != nil {
+		writeEnrollmentError(w, err, "Failed to create order")
+		return
+	}
+
+	// A pending order keeps its checkout unless it was opened with another provider.
+	// An order discounted to nothing comes back paid and has no checkout at all.
+	if order.Status == model.OrderStatusPending &&
+		(order.CheckoutURL == nil || order.Provider == nil || *order.Provider != h.Checkouts.Name()) {
+		if err := h.openCheckout(order); err != nil {
+			if errors.Is(err, errProviderUnavailable) {
+				http.Error(w, "Payment provider is unavailable, please try again later", http.StatusBadGateway)
+				return
+			}
+			http.Error(w, "Failed to create order", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(order)
+}
+
+// @Summary      Get my orders (Student only)
+// @Description  Retrieves the orders of the logged-in student, newest first.
+// @Tags         Student
+// @Produce      json
+// @Success      200  {array}   model.Order
+// @Failure      500  {object}  map[string]string
+// @Router       /student/orders [get]
+// @Security     BearerAuth
+// GetMyOrders handles requests to list the orders of a student
+func (h *PaymentHandler) GetMyOrders(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+
+	orders, err := h.Repo.GetOrdersByUserID(studentID)
+	if err != nil {
+		http.Error(w, "Failed to fetch orders", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(orders)
+}
+
+// @Summary      Payment webhook
+// @Description  Receives payment events signed by a payment provider. A successful payment marks the order paid and enrolls the student. Retried deliveries of an event are acknowledged without being applied again.
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        provider path      string  true  "Payment provider, e.g. fake"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string "Invalid signature"
+// @Failure      404      {object}  map[string]string "Unknown provider or order"
+// @Failure      500      {object}  map[string]string
+// @Router       /payments/webhook/{provider} [post]
+// PaymentWebhook handles payment events sent by payment providers
+func (h *PaymentHandler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.Providers[chi.URLParam(r, "provider")]
+	if !ok {
+		http.Error(w, "Unknown payment provider", http.StatusNotFound)
+		return
+	}
+
+	// The signature covers the raw body, so it is read before decoding
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookSize))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := provider.ParseWebhook(payload, r.Header)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Invalid event: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paid := event.Type == payment.EventPaymentSucceeded
+	if err := h.Repo.ProcessPaymentEvent(provider.Name(), event.ID, event.Reference, paid); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Order not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to process payment event", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Event processed"})
+}
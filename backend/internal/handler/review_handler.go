@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxReviewLength limits the text of reviews and replies
+const maxReviewLength = 5000
+
+type ReviewHandler struct {
+	Repo       *repository.ReviewRepository
+	CourseRepo *repository.CourseRepository
+}
+
+func NewReviewHandler(repo *repository.ReviewRepository, courseRepo *repository.CourseRepository) *ReviewHandler {
+	return &ReviewHandler{Repo: repo, CourseRepo: courseRepo}
+}
+
+type reviewRequest struct {
+	Rating int    `json:"rating" example:"5"`
+	Body   string `json:"body" example:"Clear explanations and useful exercises."`
+}
+
+type reviewReplyRequest struct {
+	Reply string `json:"reply" example:"Thank you, glad the exercises helped!"`
+}
+
+type hideReviewRequest struct {
+	Hidden bool `json:"hidden" example:"true"`
+}
+
+// writeReviews writes a page of the reviews of a course
+func (h *ReviewHandler) writeReviews(w http.ResponseWriter, r *http.Request, courseID string, includeHidden bool) {
+	params, err := parseListParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reviews, pageInfo, err := h.Repo.GetReviews(courseID, includeHidden, params)
+	if err != nil {
+		writeListError(w, err, "Failed to fetch reviews")
+		return
+	}
+
+	writePageHeaders(w, r, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reviews)
+}
+
+// @Summary      Get course reviews
+// @Description  Retrieves a page of the reviews of a published course with the staff's replies. Hidden reviews are left out. The total is returned in X-Total-Count and the next page in the Link header.
+// @Tags         Public
+// @Produce      json
+// @Param        id           path      string  true   "Course ID"
+// @Param        limit        query     int     false  "Page size (1-100, default 50)"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, rating), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Written on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Written before (RFC3339) or on (YYYY-MM-DD)"
+// @Success      200  {array}   model.Review
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /courses/{id}/reviews [get]
+// GetCourseReviews handles requests to list the reviews of a course
+func (h *ReviewHandler) GetCourseReviews(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	course, err := h.CourseRepo.GetCourseByID(courseID)
+	if err != nil || course == nil || course.IsDraft {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeReviews(w, r, courseID, false)
+}
+
+// @Summary      Review a course (Student only)
+// @Description  Rates an enrolled course from 1 to 5 with an optional text review. Each student has one review per course, sending it again edits it.
+// @Tags         Student
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true  "Course ID"
+// @Param        review body      reviewRequest true "Rating and review"
+// @Success      200    {object}  model.Review
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string "Not enrolled in this course"
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /courses/{id}/review [put]
+// @Security     BearerAuth
+// SaveReview handles requests from students to review a course or edit their review
+func (h *ReviewHandler) SaveReview(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+	courseID := chi.URLParam(r, "id")
+
+	var req reviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Rating < 1 || req.Rating > 5 {
+		http.Error(w, "Rating must be between 1 and 5", http.StatusBadRequest)
+		return
+	}
+	if len(req.Body) > maxReviewLength {
+		http.Error(w, "Review must be at most 5000 characters", http.StatusBadRequest)
+		return
+	}
+
+	// Only students enrolled in the course may review it
+	isEnrolled, err := h.CourseRepo.IsStudentEnrolled(studentID, courseID)
+	if err != nil {
+		http.Error(w, "Failed to verify enrollment", http.StatusInternalServerError)
+		return
+	}
+	if !isEnrolled {
+		http.Error(w, "Forbidden: You are not enrolled in this course", http.StatusForbidden)
+		return
+	}
+
+	review := model.Review{CourseID: courseID, UserID: studentID, Rating: req.Rating, Body: req.Body}
+	if err := h.Repo.SaveReview(&review); err != nil {
+		http.Error(w, "Failed to save review", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(review)
+}
+
+// @Summary      Delete my review (Student only)
+// @Description  Deletes the logged-in student's review of a course.
+// @Tags         Student
+// @Produce      json
+// @Param        id   path      string  true  "Course ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /courses/{id}/review [delete]
+// @Security     BearerAuth
+// DeleteReview handles requests from students to delete their review
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve student ID from context", http.StatusInternalServerError)
+		return
+	}
+	courseID := chi.URLParam(r, "id")
+
+	if err := h.Repo.DeleteReview(courseID, studentID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "You have not reviewed this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete review", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Review deleted successfully"})
+}
+
+// @Summary      Reply to a review (Instructor only)
+// @Description  Posts the public reply of the course staff to a review. A review has one reply, replying again replaces it.
+// @Tags         Instructor
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Course ID"
+// @Param        reviewId path      string  true  "Review ID"
+// @Param        reply    body      reviewReplyRequest true "Reply text"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /instructor/courses/{id}/reviews/{reviewId}/reply [put]
+// @Security     BearerAuth
+// ReplyToReview handles requests from course staff to reply to a review
+func (h *ReviewHandler) ReplyToReview(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+	reviewID := chi.URLParam(r, "reviewId")
+
+	if authorizeCourse(h.CourseRepo, w, r, courseID, permEditCourse) == nil {
+		return
+	}
+	actorID := r.Context().Value(middleware.UserIDKey).(string)
+	if uuid.Validate(reviewID) != nil {
+		http.Error(w, "Review not found in this course", http.StatusNotFound)
+		return
+	}
+
+	var req reviewReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Reply = strings.TrimSpace(req.Reply)
+	if req.Reply == "" || len(req.Reply) > maxReviewLength {
+		http.Error(w, "Reply must be between 1 and 5000 characters", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.ReplyToReview(courseID, reviewID, actorID, req.Reply); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Review not found in this course", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to reply to review", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Reply posted successfully"})
+}
+
+// @Summary      Get course reviews (Admin only)
+// @Description  Retrieves a page of the reviews of any course, hidden ones included. The total is returned in X-Total-Count and the next page in the Link header.
+// @Tags         Admin
+// @Produce      json
+// @Param        id           path      string  true   "Course ID"
+// @Param        limit        query     int     false  "Page size (1-100, default 50)"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, rating), prefix with '-' for descending"
+// @Param        created_from query     string  false  "Written on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Written before (RFC3339) or on (YYYY-MM-DD)"
+// @Success      200  {array}   model.Review
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/courses/{id}/reviews [get]
+// @Security     BearerAuth
+// AdminGetCourseReviews handles requests from admins to list every review of a course
+func (h *ReviewHandler) AdminGetCourseReviews(w http.ResponseWriter, r *http.Request) {
+	courseID := chi.URLParam(r, "id")
+
+	course, err := h.CourseRepo.GetCourseByID(courseID)
+	if err != nil || course == nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeReviews(w, r, courseID, true)
+}
+
+// @Summary      Hide a review (Admin only)
+// @Description  Hides an abusive review from listings and the course rating, or shows it again. The author can still edit a hidden review but it stays hidden.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        reviewId path      string  true  "Review ID"
+// @Param        hidden   body      hideReviewRequest true "Whether the review is hidden"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /admin/reviews/{reviewId}/hidden [put]
+// @Security     BearerAuth
+// SetReviewHidden handles requests from admins to hide or show a review
+func (h *ReviewHandler) SetReviewHidden(w http.ResponseWriter, r *http.Request) {
+	reviewID := chi.URLParam(r, "reviewId")
+	adminID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve user ID from context", http.StatusInternalServerError)
+		return
+	}
+	if uuid.Validate(reviewID) != nil {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return
+	}
+
+	var req hideReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.SetReviewHidden(reviewID, adminID, req.Hidden); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Review not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to update review", http.StatusInternalServerError)
+		return
+	}
+
+	message := "Review is visible again"
+	if req.Hidden {
+		message = "Review hidden successfully"
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
@@ -0,0 +1,540 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/filecheck"
+	"github.com/dimasrizkyfebrian/coursify/internal/handler/middleware"
+	"github.com/dimasrizkyfebrian/coursify/internal/media"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/dimasrizkyfebrian/coursify/internal/repository"
+	"github.com/dimasrizkyfebrian/coursify/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// The tus protocol version and extensions the upload endpoints implement, see https://tus.io/protocols/resumable-upload
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,expiration,termination"
+)
+
+// uploadExpiry is how long an upload may go without receiving a chunk before it is removed
+const uploadExpiry = 24 * time.Hour
+
+// maxUploadSizes caps the size of each kind of upload
+var maxUploadSizes = map[string]int64{
+	model.UploadKindCover: filecheck.MaxCoverSize,
+	model.UploadKindPDF:   filecheck.MaxPDFSize,
+	model.UploadKindVideo: maxVideoSize,
+}
+
+type UploadHandler struct {
+	Repo       *repository.UploadRepository
+	CourseRepo *repository.CourseRepository
+	locks      sync.Map // Upload ID to *sync.Mutex, so one chunk is written at a time
+}
+
+func NewUploadHandler(repo *repository.UploadRepository, courseRepo *repository.CourseRepository) *UploadHandler {
+	return &UploadHandler{Repo: repo, CourseRepo: courseRepo}
+}
+
+// partialPath is where the received bytes of an incomplete upload are kept
+func partialPath(uploadID string) string {
+	return filepath.Join(storage.PartialDir, uploadID)
+}
+
+// parseUploadMetadata decodes an Upload-Metadata header, a comma separated list of keys and base64 values
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("Upload-Metadata value of %q is not base64", key)
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
+
+// writeUploadHeaders sets the headers describing the state of an upload
+func writeUploadHeaders(w http.ResponseWriter, upload *model.Upload) {
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	if upload.CompletedAt == nil {
+		w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// requireTus rejects requests made for another version of the protocol
+func requireTus(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		http.Error(w, "Unsupported tus version, expected Tus-Resumable: "+tusVersion, http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// loadUpload retrieves an upload of the logged-in user, writing the error response when it is missing or expired
+func (h *UploadHandler) loadUpload(w http.ResponseWriter, r *http.Request) *model.Upload {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve user ID from context", http.StatusInternalServerError)
+		return nil
+	}
+
+	uploadID := chi.URLParam(r, "uploadId")
+	if uuid.Validate(uploadID) != nil {
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return nil
+	}
+	upload, err := h.Repo.GetUpload(uploadID, userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch upload", http.StatusInternalServerError)
+		return nil
+	}
+	if upload == nil {
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return nil
+	}
+	if upload.CompletedAt == nil && time.Now().After(upload.ExpiresAt) {
+		http.Error(w, "Upload expired", http.StatusGone)
+		return nil
+	}
+	return upload
+}
+
+// @Summary      Discover resumable upload support (Instructor only)
+// @Description  Answers with the tus protocol version, extensions and maximum upload size the server supports.
+// @Tags         Instructor - Uploads
+// @Success      204
+// @Router       /instructor/uploads [options]
+// @Security     BearerAuth
+// TusOptions handles tus discovery requests
+func (h *UploadHandler) TusOptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(maxUploadSizes[model.UploadKindVideo], 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary      Start a resumable upload (Instructor only)
+// @Description  Creates a tus 1.0 upload of Upload-Length bytes. Upload-Metadata must name the course_id, the kind ('cover', 'pdf' or 'video') and the filename, plus the title of a new pdf or video material and the filetype of a video. Send the file in chunks with PATCH to the returned Location. The file's type is recognised from its content: a cover must be a JPEG or PNG image, a pdf a PDF document and a video an MP4, MOV or WebM file, and a file of another type is rejected with its first chunk. Once complete, the file is checked as by the upload-cover, upload-pdf and upload-video endpoints, then a cover replaces the course cover image and a pdf or video becomes a new material. Uploads that receive no chunk for 24 hours expire.
+// @Tags         Instructor - Uploads
+// @Param        Tus-Resumable   header    string  true  "Protocol version, 1.0.0"
+// @Param        Upload-Length   header    int     true  "Size of the file in bytes"
+// @Param        Upload-Metadata header    string  true  "Comma separated keys and base64 values: course_id, kind, filename, title, filetype"
+// @Success      201
+// @Header       201 {string} Location "URL of the new upload"
+// @Header       201 {string} Upload-Expires "When the upload expires unless a chunk arrives"
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string "Unsupported tus version"
+// @Failure      413  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/uploads [post]
+// @Security     BearerAuth
+// CreateUpload handles requests to start a resumable upload
+func (h *UploadHandler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	if !requireTus(w, r) {
+		return
+	}
+	userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Could not retrieve user ID from context", http.StatusInternalServerError)
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		http.Error(w, "Upload-Length must be a positive number of bytes", http.StatusBadRequest)
+		return
+	}
+	metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	upload := &model.Upload{
+		UserID:    userID,
+		CourseID:  metadata["course_id"],
+		Kind:      metadata["kind"],
+		Title:     strings.TrimSpace(metadata["title"]),
+		Filename:  filepath.Base(metadata["filename"]),
+		Filetype:  metadata["filetype"],
+		Length:    length,
+		ExpiresAt: time.Now().Add(uploadExpiry),
+	}
+
+	maxSize, ok := maxUploadSizes[upload.Kind]
+	if !ok {
+		http.Error(w, "Upload-Metadata kind must be 'cover', 'pdf' or 'video'", http.StatusBadRequest)
+		return
+	}
+	if length > maxSize {
+		http.Error(w, fmt.Sprintf("File is too large. Max size for a %s is %dMB.", upload.Kind, maxSize>>20), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if metadata["filename"] == "" || len(upload.Filename) > 255 {
+		http.Error(w, "Upload-Metadata must name the filename", http.StatusBadRequest)
+		return
+	}
+	if upload.Kind != model.UploadKindCover && (upload.Title == "" || len(upload.Title) > 255) {
+		http.Error(w, "Upload-Metadata must give a material title of up to 255 characters", http.StatusBadRequest)
+		return
+	}
+	if uuid.Validate(upload.CourseID) != nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		return
+	}
+
+	// Covers need the right to edit the course, materials the right to manage its materials
+	perm := permManageMaterials
+	if upload.Kind == model.UploadKindCover {
+		perm = permEditCourse
+	}
+	if authorizeCourse(h.CourseRepo, w, r, upload.CourseID, perm) == nil {
+		return
+	}
+
+	if err := h.Repo.CreateUpload(upload); err != nil {
+		http.Error(w, "Failed to create upload", http.StatusInternalServerError)
+		return
+	}
+	if err := os.MkdirAll(storage.PartialDir, os.ModePerm); err != nil {
+		h.Repo.DeleteUpload(upload.ID)
+		http.Error(w, "Could not create uploads directory", http.StatusInternalServerError)
+		return
+	}
+	file, err := os.Create(partialPath(upload.ID))
+	if err != nil {
+		h.Repo.DeleteUpload(upload.ID)
+		http.Error(w, "Could not create the upload file", http.StatusInternalServerError)
+		return
+	}
+	file.Close()
+
+	w.Header().Set("Location", "/api/instructor/uploads/"+upload.ID)
+	w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary      Get the offset of a resumable upload (Instructor only)
+// @Description  Answers with how many bytes of the upload were received in Upload-Offset, so an interrupted upload can resume from there.
+// @Tags         Instructor - Uploads
+// @Param        uploadId        path      string  true  "Upload ID"
+// @Param        Tus-Resumable   header    string  true  "Protocol version, 1.0.0"
+// @Success      200
+// @Header       200 {int} Upload-Offset "Bytes received so far"
+// @Header       200 {int} Upload-Length "Size of the file in bytes"
+// @Failure      404
+// @Failure      410  "Upload expired"
+// @Router       /instructor/uploads/{uploadId} [head]
+// @Security     BearerAuth
+// GetUploadOffset handles tus requests for the offset of an upload
+func (h *UploadHandler) GetUploadOffset(w http.ResponseWriter, r *http.Request) {
+	if !requireTus(w, r) {
+		return
+	}
+	upload := h.loadUpload(w, r)
+	if upload == nil {
+		return
+	}
+
+	writeUploadHeaders(w, upload)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary      Get a resumable upload (Instructor only)
+// @Description  Retrieves the state of an upload, with the file URL and the new material once it is complete.
+// @Tags         Instructor - Uploads
+// @Produce      json
+// @Param        uploadId path      string  true  "Upload ID"
+// @Success      200      {object}  model.Upload
+// @Failure      404      {object}  map[string]string
+// @Failure      410      {object}  map[string]string "Upload expired"
+// @Failure      500      {object}  map[string]string
+// @Router       /instructor/uploads/{uploadId} [get]
+// @Security     BearerAuth
+// GetUpload handles requests to retrieve the state of an upload
+func (h *UploadHandler) GetUpload(w http.ResponseWriter, r *http.Request) {
+	upload := h.loadUpload(w, r)
+	if upload == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(upload)
+}
+
+// @Summary      Send a chunk of a resumable upload (Instructor only)
+// @Description  Appends the request body to the upload at Upload-Offset, which must equal the bytes received so far. The first chunk is checked for the file type of the upload's kind, and the chunk that completes the upload attaches the file to its course; a file that turns out not to be valid is discarded with the upload.
+// @Tags         Instructor - Uploads
+// @Accept       application/offset+octet-stream
+// @Param        uploadId        path      string  true  "Upload ID"
+// @Param        Tus-Resumable   header    string  true  "Protocol version, 1.0.0"
+// @Param        Upload-Offset   header    int     true  "Offset the chunk starts at"
+// @Success      204
+// @Header       204 {int} Upload-Offset "Bytes received so far"
+// @Failure      400  {object}  map[string]string "The file's type or content is not valid for its kind"
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string "Upload-Offset does not match the bytes received"
+// @Failure      410  {object}  map[string]string "Upload expired"
+// @Failure      415  {object}  map[string]string
+// @Failure      423  {object}  map[string]string "Another chunk is being written"
+// @Failure      500  {object}  map[string]string
+// @Router       /instructor/uploads/{uploadId} [patch]
+// @Security     BearerAuth
+// PatchUpload handles tus requests carrying a chunk of an upload
+func (h *UploadHandler) PatchUpload(w http.ResponseWriter, r *http.Request) {
+	if !requireTus(w, r) {
+		return
+	}
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		http.Error(w, "Content-Type must be application/offset+octet-stream", http.StatusUnsupportedMediaType)
+		return
+	}
+	upload := h.loadUpload(w, r)
+	if upload == nil {
+		return
+	}
+
+	lock, _ := h.locks.LoadOrStore(upload.ID, &sync.Mutex{})
+	if !lock.(*sync.Mutex).TryLock() {
+		http.Error(w, "Another chunk of this upload is being written", http.StatusLocked)
+		return
+	}
+	defer lock.(*sync.Mutex).Unlock()
+
+	// Read the offset again now that no other chunk can move it
+	upload = h.loadUpload(w, r)
+	if upload == nil {
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset != upload.Offset || upload.CompletedAt != nil {
+		writeUploadHeaders(w, upload)
+		http.Error(w, "Upload-Offset does not match the bytes received", http.StatusConflict)
+		return
+	}
+
+	file, err := os.OpenFile(partialPath(upload.ID), os.O_WRONLY, 0)
+	if err != nil {
+		http.Error(w, "Could not open the upload file", http.StatusInternalServerError)
+		return
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		http.Error(w, "Could not open the upload file", http.StatusInternalServerError)
+		return
+	}
+
+	body := io.Reader(io.LimitReader(r.Body, upload.Length-offset))
+	// Reject a file of the wrong type with its first chunk rather than once it is complete
+	if offset == 0 {
+		buffered := bufio.NewReaderSize(body, filecheck.SniffLen)
+		header, _ := buffered.Peek(filecheck.SniffLen)
+		if int64(len(header)) == min(filecheck.SniffLen, upload.Length) {
+			if _, err := filecheck.Sniff(upload.Kind, header); err != nil {
+				file.Close()
+				h.discardUpload(upload.ID)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		body = buffered
+	}
+
+	// Keep whatever arrived even when the connection drops, the client resumes from there
+	written, copyErr := io.Copy(file, body)
+	file.Close()
+	upload.Offset = offset + written
+	upload.ExpiresAt = time.Now().Add(uploadExpiry)
+	if err := h.Repo.SetUploadOffset(upload.ID, upload.Offset, upload.ExpiresAt); err != nil {
+		http.Error(w, "Failed to save upload progress", http.StatusInternalServerError)
+		return
+	}
+	if copyErr != nil {
+		http.Error(w, "Upload interrupted, resume from Upload-Offset", http.StatusBadRequest)
+		return
+	}
+
+	if upload.Offset == upload.Length {
+		if status, err := h.attachUpload(upload); err != nil {
+			h.discardUpload(upload.ID)
+			http.Error(w, err.Error(), status)
+			return
+		}
+	}
+
+	writeUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// attachUpload moves a completed upload into the uploads folder and attaches it to its course
+func (h *UploadHandler) attachUpload(upload *model.Upload) (int, error) {
+	path := partialPath(upload.ID)
+	baseName := fmt.Sprintf("%s-%d", upload.CourseID, time.Now().Unix())
+
+	var material *model.LearningMaterial
+	var fileURL string
+	var err error
+	switch upload.Kind {
+	case model.UploadKindCover, model.UploadKindPDF:
+		// Covers and PDFs are small enough to check in memory, and are saved with the extension of their detected type
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return http.StatusInternalServerError, errors.New("Could not open the upload file")
+		}
+		mimeType, checkErr := filecheck.Check(upload.Kind, data)
+		if checkErr != nil {
+			return http.StatusBadRequest, checkErr
+		}
+		fileName := baseName + filecheck.Extension(mimeType)
+
+		if upload.Kind == model.UploadKindCover {
+			if fileURL, err = storage.Move(path, "", fileName); err != nil {
+				return http.StatusInternalServerError, errors.New("Could not save the file")
+			}
+			err = h.CourseRepo.UpdateCourseCoverImage(upload.CourseID, fileURL)
+			break
+		}
+		if fileURL, err = storage.Move(path, "materials", fileName); err != nil {
+			return http.StatusInternalServerError, errors.New("Could not save the file")
+		}
+		material = &model.LearningMaterial{CourseID: upload.CourseID, Title: upload.Title, FileURL: fileURL}
+		err = h.CourseRepo.AddFileMaterialToCourse(material)
+
+	case model.UploadKindVideo:
+		header := make([]byte, media.SniffLen)
+		file, openErr := os.Open(path)
+		if openErr != nil {
+			return http.StatusInternalServerError, errors.New("Could not open the upload file")
+		}
+		n, _ := io.ReadFull(file, header)
+		file.Close()
+		container, checkErr := checkVideo(header[:n], upload.Filename, upload.Filetype)
+		if checkErr != nil {
+			return http.StatusBadRequest, checkErr
+		}
+		size, duration, probeErr := probeVideo(path, container)
+		if probeErr != nil {
+			return http.StatusBadRequest, probeErr
+		}
+		fileName := baseName + strings.ToLower(filepath.Ext(upload.Filename))
+		if fileURL, err = storage.Move(path, "videos", fileName); err != nil {
+			return http.StatusInternalServerError, errors.New("Could not save the file")
+		}
+		material = videoMaterial(upload.CourseID, upload.Title, fileURL, size, duration)
+		err = h.CourseRepo.AddVideoMaterialToCourse(material)
+	}
+	if err != nil {
+		storage.Remove(fileURL)
+		return http.StatusInternalServerError, errors.New("Could not attach the file to the course")
+	}
+
+	var materialID *string
+	if material != nil {
+		materialID = &material.ID
+	}
+	if err := h.Repo.CompleteUpload(upload.ID, fileURL, materialID); err != nil {
+		return http.StatusInternalServerError, errors.New("Failed to complete upload")
+	}
+
+	now := time.Now()
+	upload.FileURL, upload.MaterialID, upload.CompletedAt = &fileURL, materialID, &now
+	return 0, nil
+}
+
+// discardUpload removes an upload and its partial file
+func (h *UploadHandler) discardUpload(uploadID string) {
+	os.Remove(partialPath(uploadID))
+	h.Repo.DeleteUpload(uploadID)
+	h.locks.Delete(uploadID)
+}
+
+// @Summary      Cancel a resumable upload (Instructor only)
+// @Description  Discards an upload and the bytes received so far. A completed upload keeps its attached file and material, only the upload record is removed.
+// @Tags         Instructor - Uploads
+// @Param        uploadId        path      string  true  "Upload ID"
+// @Param        Tus-Resumable   header    string  true  "Protocol version, 1.0.0"
+// @Success      204
+// @Failure      404  {object}  map[string]string
+// @Failure      410  {object}  map[string]string "Upload expired"
+// @Failure      423  {object}  map[string]string "A chunk is being written"
+// @Router       /instructor/uploads/{uploadId} [delete]
+// @Security     BearerAuth
+// DeleteUpload handles tus termination requests
+func (h *UploadHandler) DeleteUpload(w http.ResponseWriter, r *http.Request) {
+	if !requireTus(w, r) {
+		return
+	}
+	upload := h.loadUpload(w, r)
+	if upload == nil {
+		return
+	}
+
+	lock, _ := h.locks.LoadOrStore(upload.ID, &sync.Mutex{})
+	if !lock.(*sync.Mutex).TryLock() {
+		http.Error(w, "A chunk of this upload is being written", http.StatusLocked)
+		return
+	}
+	defer lock.(*sync.Mutex).Unlock()
+
+	h.discardUpload(upload.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeExpiredUploads removes expired uploads every interval, along with partial files
+// left behind by uploads whose course was deleted. It runs until the process exits.
+func (h *UploadHandler) PurgeExpiredUploads(interval time.Duration) {
+	for ; ; time.Sleep(interval) {
+		expired, err := h.Repo.DeleteExpiredUploads()
+		if err != nil {
+			continue
+		}
+		for _, uploadID := range expired {
+			os.Remove(partialPath(uploadID))
+			h.locks.Delete(uploadID)
+		}
+
+		entries, err := os.ReadDir(storage.PartialDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err == nil && time.Since(info.ModTime()) > 2*uploadExpiry {
+				os.Remove(filepath.Join(storage.PartialDir, entry.Name()))
+			}
+		}
+		if len(expired) > 0 {
+			log.Printf("Removed %d expired uploads", len(expired))
+		}
+	}
+}
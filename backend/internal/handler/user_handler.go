@@ -145,20 +145,34 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary      Get pending users (Admin only)
-// @Description  Retrieves a list of users with 'pending' status.
+// @Description  Retrieves a page of users with 'pending' status. The total is returned in X-Total-Count and the next page in the Link header.
 // @Tags         Admin
 // @Produce      json
+// @Param        limit        query     int     false  "Page size (1-100), every item is returned when neither limit nor cursor is given"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, updated_at, full_name, email), prefix with '-' for descending"
+// @Param        role         query     string  false  "Filter by role" Enums(admin, instructor, student)
+// @Param        created_from query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Created before (RFC3339) or on (YYYY-MM-DD)"
 // @Success      200  {array}  model.User
+// @Failure      400  {object}  map[string]string
 // @Failure      403  {object}  map[string]string
 // @Router       /admin/users/pending [get]
 // @Security     BearerAuth
 func (h *UserHandler) GetPendingUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.Repo.GetUsersByStatus("pending")
+	params, err := parseWholeListParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	users, pageInfo, err := h.Repo.GetUsersByStatus("pending", params)
 	if err != nil {
-		http.Error(w, "Could not fetch users", http.StatusInternalServerError)
+		writeListError(w, err, "Could not fetch users")
 		return
 	}
 
+	writePageHeaders(w, r, pageInfo)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(users)
@@ -271,22 +285,37 @@ func (h *UserHandler) GetPendingUserCount(w http.ResponseWriter, r *http.Request
 }
 
 // @Summary      Get all users (Admin only)
-// @Description  Retrieves a list of all users regardless of their status.
+// @Description  Retrieves a page of users, optionally filtered by role, status and created range. The total is returned in X-Total-Count and the next page in the Link header.
 // @Tags         Admin
 // @Produce      json
+// @Param        limit        query     int     false  "Page size (1-100), every item is returned when neither limit nor cursor is given"
+// @Param        cursor       query     string  false  "Cursor from the previous page's Link header"
+// @Param        sort         query     string  false  "Sort field (created_at, updated_at, full_name, email), prefix with '-' for descending"
+// @Param        role         query     string  false  "Filter by role" Enums(admin, instructor, student)
+// @Param        created_from query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        created_to   query     string  false  "Created before (RFC3339) or on (YYYY-MM-DD)"
+// @Param        status       query     string  false  "Filter by status" Enums(pending, active, rejected)
 // @Success      200  {array}   model.User
+// @Failure      400  {object}  map[string]string
 // @Failure      403  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/users/all [get]
 // @Security     BearerAuth
 func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.Repo.GetAllUsers()
+	params, err := parseWholeListParams(r)
 	if err != nil {
-		http.Error(w, "Could not fetch users", http.StatusInternalServerError)
-	return
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	users, pageInfo, err := h.Repo.GetAllUsers(params)
+	if err != nil {
+		writeListError(w, err, "Could not fetch users")
+		return
 	}
 
-	w.Header().Set("Content_Type", "application/json")
+	writePageHeaders(w, r, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(users)
 }
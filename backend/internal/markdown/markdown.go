@@ -0,0 +1,48 @@
+// Package markdown renders the Markdown of text materials to HTML that is safe to show in a browser.
+//
+// Materials are written in CommonMark with tables. Raw HTML in the source is kept so content imported
+// from other platforms survives, and everything is sanitized after rendering.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// MaxSourceLen caps the size of the Markdown of a material
+const MaxSourceLen = 1 << 20
+
+var converter = goldmark.New(
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+	),
+	// Raw HTML is passed through to the sanitizer instead of being dropped
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var policy = newPolicy()
+
+// newPolicy allows the HTML of user generated content, plus the language class of fenced code blocks
+// so clients can highlight them
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	return p
+}
+
+// Render converts Markdown to sanitized HTML. Empty source renders to an empty string.
+func Render(source string) (string, error) {
+	if source == "" {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "commonmark",
+			source:   "# Intro\n\nSome *emphasis* and a [link](https://example.com).",
+			contains: []string{"<h1>Intro</h1>", "<em>emphasis</em>", `<a href="https://example.com" rel="nofollow">link</a>`},
+		},
+		{
+			name:     "table",
+			source:   "| Name | Score |\n| --- | ---: |\n| Ana | 90 |",
+			contains: []string{"<table>", "<th>Name</th>", `<td align="right">90</td>`},
+		},
+		{
+			name:     "fenced code keeps its language",
+			source:   "```go\nfmt.Println(\"<hi>\")\n```",
+			contains: []string{`<pre><code class="language-go">`, "&lt;hi&gt;"},
+		},
+		{
+			name:     "scripts and handlers are removed",
+			source:   "<script>alert(1)</script>\n\n<p onclick=\"alert(1)\">Hello</p>\n\n<img src=x onerror=alert(1)>",
+			contains: []string{"<p>Hello</p>"},
+			excludes: []string{"<script", "alert", "onclick", "onerror"},
+		},
+		{
+			name:     "javascript links are removed",
+			source:   "[click](javascript:alert(1))",
+			excludes: []string{"javascript:"},
+		},
+		{
+			name:     "only code blocks may carry a class",
+			source:   `<div class="evil">Hi</div>`,
+			excludes: []string{"class"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in %q", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("did not expect %q in %q", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	if got, err := Render(""); err != nil || got != "" {
+		t.Errorf("expected empty output, got %q (%v)", got, err)
+	}
+}
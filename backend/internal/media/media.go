@@ -0,0 +1,305 @@
+// Package media recognises uploaded video files and reads their duration.
+//
+// Only the container headers are read, so even large files are probed
+// without loading them into memory.
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// Video containers accepted for upload
+const (
+	ContainerMP4       = "mp4"
+	ContainerQuickTime = "mov"
+	ContainerWebM      = "webm"
+)
+
+// SniffLen is how many leading bytes Sniff needs to recognise a container
+const SniffLen = 64
+
+// ErrUnsupported is returned for files that are not in one of the accepted containers
+var ErrUnsupported = errors.New("unsupported video container")
+
+// ErrMalformed is returned when the container headers cannot be read
+var ErrMalformed = errors.New("malformed video file")
+
+// MIMEType returns the MIME type of a container
+func MIMEType(container string) string {
+	switch container {
+	case ContainerMP4:
+		return "video/mp4"
+	case ContainerQuickTime:
+		return "video/quicktime"
+	case ContainerWebM:
+		return "video/webm"
+	}
+	return ""
+}
+
+// Extensions returns the file extensions expected for a container
+func Extensions(container string) []string {
+	switch container {
+	case ContainerMP4:
+		return []string{".mp4", ".m4v"}
+	case ContainerQuickTime:
+		return []string{".mov"}
+	case ContainerWebM:
+		return []string{".webm"}
+	}
+	return nil
+}
+
+// Sniff recognises the container from the first bytes of a file
+func Sniff(header []byte) (string, error) {
+	// ISO base media files start with an 'ftyp' box naming the major brand
+	if len(header) >= 12 && string(header[4:8]) == "ftyp" {
+		if string(header[8:12]) == "qt  " {
+			return ContainerQuickTime, nil
+		}
+		return ContainerMP4, nil
+	}
+	// Matroska files start with an EBML header, WebM declares 'webm' as its DocType
+	if len(header) >= 4 && bytes.Equal(header[:4], []byte{0x1a, 0x45, 0xdf, 0xa3}) {
+		if bytes.Contains(header, []byte("webm")) {
+			return ContainerWebM, nil
+		}
+	}
+	return "", ErrUnsupported
+}
+
+// Duration reads the duration of a video in the given container.
+// WebM files recorded live may not state their duration, which returns 0 without an error.
+func Duration(r io.ReadSeeker, container string) (time.Duration, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	switch container {
+	case ContainerMP4, ContainerQuickTime:
+		return mp4Duration(r, size)
+	case ContainerWebM:
+		return webmDuration(r, size)
+	}
+	return 0, ErrUnsupported
+}
+
+// findBox seeks to the payload of the first box of the given type between the current offset and end,
+// returning the offset where the payload ends
+func findBox(r io.ReadSeeker, offset, end int64, boxType string) (int64, error) {
+	var header [16]byte
+	for offset+8 <= end {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return 0, err
+		}
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return 0, ErrMalformed
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		headerSize := int64(8)
+		switch boxSize {
+		case 0: // The box runs to the end of its parent
+			boxSize = end - offset
+		case 1: // A 64-bit size follows the type
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return 0, ErrMalformed
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if boxSize < headerSize || offset+boxSize > end {
+			return 0, ErrMalformed
+		}
+		if string(header[4:8]) == boxType {
+			return offset + boxSize, nil
+		}
+		offset += boxSize
+	}
+	return 0, ErrMalformed
+}
+
+// mp4Duration reads the duration from the movie header box, moov/mvhd
+func mp4Duration(r io.ReadSeeker, size int64) (time.Duration, error) {
+	moovEnd, err := findBox(r, 0, size, "moov")
+	if err != nil {
+		return 0, err
+	}
+	moovStart, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := findBox(r, moovStart, moovEnd, "mvhd"); err != nil {
+		return 0, err
+	}
+
+	var version [4]byte // Version and flags
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return 0, ErrMalformed
+	}
+	var timescale, units uint64
+	if version[0] == 1 {
+		var fields [28]byte // 64-bit creation and modification times, timescale, 64-bit duration
+		if _, err := io.ReadFull(r, fields[:]); err != nil {
+			return 0, ErrMalformed
+		}
+		timescale = uint64(binary.BigEndian.Uint32(fields[16:20]))
+		units = binary.BigEndian.Uint64(fields[20:28])
+	} else {
+		var fields [16]byte // 32-bit creation and modification times, timescale, 32-bit duration
+		if _, err := io.ReadFull(r, fields[:]); err != nil {
+			return 0, ErrMalformed
+		}
+		timescale = uint64(binary.BigEndian.Uint32(fields[8:12]))
+		units = uint64(binary.BigEndian.Uint32(fields[12:16]))
+	}
+	if timescale == 0 {
+		return 0, ErrMalformed
+	}
+	return time.Duration(float64(units) / float64(timescale) * float64(time.Second)), nil
+}
+
+// EBML element IDs, kept with their length marker bits as they appear in the file
+const (
+	ebmlSegment       = 0x18538067
+	ebmlInfo          = 0x1549a966
+	ebmlTimecodeScale = 0x2ad7b1
+	ebmlDuration      = 0x4489
+)
+
+// readVint reads an EBML variable size integer. keepMarker keeps the length marker bit, as element IDs do.
+// unknown reports a size with every value bit set, which means the element runs to the end of its parent.
+func readVint(r io.Reader, keepMarker bool) (value uint64, unknown bool, err error) {
+	var first [1]byte
+	if _, err := io.ReadFull(r, first[:]); err != nil {
+		return 0, false, ErrMalformed
+	}
+	length := 1
+	for mask := byte(0x80); length <= 8 && first[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if length > 8 {
+		return 0, false, ErrMalformed
+	}
+
+	value = uint64(first[0])
+	if !keepMarker {
+		value &= uint64(0xff) >> length
+	}
+	allOnes := value == uint64(0xff)>>length
+	rest := make([]byte, length-1)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return 0, false, ErrMalformed
+	}
+	for _, b := range rest {
+		value = value<<8 | uint64(b)
+		allOnes = allOnes && b == 0xff
+	}
+	return value, !keepMarker && allOnes, nil
+}
+
+// webmDuration reads Segment/Info/Duration, scaled by Segment/Info/TimecodeScale
+func webmDuration(r io.ReadSeeker, size int64) (time.Duration, error) {
+	offset := int64(0)
+	end := size
+	timecodeScale := uint64(1000000) // Nanoseconds per timecode unit unless the file says otherwise
+	var units float64
+	inInfo := false
+
+	for offset < end {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return 0, err
+		}
+		id, _, err := readVint(r, true)
+		if err != nil {
+			return 0, err
+		}
+		dataSize, unknown, err := readVint(r, false)
+		if err != nil {
+			return 0, err
+		}
+		dataStart, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		dataEnd := dataStart + int64(dataSize)
+		if unknown || dataEnd > end {
+			dataEnd = end
+		}
+
+		switch {
+		case id == ebmlSegment && !inInfo:
+			offset, end = dataStart, dataEnd // Descend into the segment
+			continue
+		case id == ebmlInfo && !inInfo:
+			offset, end, inInfo = dataStart, dataEnd, true // Descend into the segment info
+			continue
+		case inInfo && id == ebmlTimecodeScale:
+			value, err := readUint(r, dataEnd-dataStart)
+			if err != nil {
+				return 0, err
+			}
+			if value > 0 {
+				timecodeScale = value
+			}
+		case inInfo && id == ebmlDuration:
+			value, err := readFloat(r, dataEnd-dataStart)
+			if err != nil {
+				return 0, err
+			}
+			units = value
+		case unknown:
+			// An element of unknown size other than the segment cannot be skipped, and the info comes before it
+			return 0, nil
+		}
+		offset = dataEnd
+	}
+
+	if units <= 0 || math.IsInf(units, 0) || math.IsNaN(units) {
+		return 0, nil
+	}
+	return time.Duration(units * float64(timecodeScale)), nil
+}
+
+// readUint reads a big-endian unsigned integer element of up to 8 bytes
+func readUint(r io.Reader, length int64) (uint64, error) {
+	if length < 1 || length > 8 {
+		return 0, ErrMalformed
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, ErrMalformed
+	}
+	var value uint64
+	for _, b := range buf {
+		value = value<<8 | uint64(b)
+	}
+	return value, nil
+}
+
+// readFloat reads a big-endian 4 or 8 byte float element
+func readFloat(r io.Reader, length int64) (float64, error) {
+	switch length {
+	case 4:
+		var bits uint32
+		if err := binary.Read(r, binary.BigEndian, &bits); err != nil {
+			return 0, ErrMalformed
+		}
+		return float64(math.Float32frombits(bits)), nil
+	case 8:
+		var bits uint64
+		if err := binary.Read(r, binary.BigEndian, &bits); err != nil {
+			return 0, ErrMalformed
+		}
+		return math.Float64frombits(bits), nil
+	}
+	return 0, ErrMalformed
+}
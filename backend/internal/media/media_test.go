@@ -0,0 +1,72 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// box builds an ISO base media box
+func box(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(out, uint32(8+len(body)))
+	copy(out[4:], boxType)
+	return append(out, body...)
+}
+
+func TestMP4(t *testing.T) {
+	mvhd := make([]byte, 20) // Version 0: flags, creation, modification, timescale, duration
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 90500)
+	file := bytes.Join([][]byte{
+		box("ftyp", []byte("isom"), make([]byte, 4)),
+		box("mdat", make([]byte, 100)),
+		box("moov", box("trak"), box("mvhd", mvhd)),
+	}, nil)
+
+	container, err := Sniff(file[:SniffLen])
+	if err != nil || container != ContainerMP4 {
+		t.Fatalf("expected mp4, got %q (%v)", container, err)
+	}
+	duration, err := Duration(bytes.NewReader(file), container)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if duration != 90500*time.Millisecond {
+		t.Errorf("expected 1m30.5s, got %s", duration)
+	}
+}
+
+func TestWebM(t *testing.T) {
+	duration := make([]byte, 8)
+	binary.BigEndian.PutUint64(duration, math.Float64bits(2500)) // Timecode units of 1ms by default
+	file := bytes.Join([][]byte{
+		{0x1a, 0x45, 0xdf, 0xa3, 0x84}, []byte("webm"), // EBML header with its DocType
+		{0x18, 0x53, 0x80, 0x67, 0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, // Segment of unknown size
+		{0x15, 0x49, 0xa9, 0x66, 0x8b}, // Info
+		{0x44, 0x89, 0x88}, duration,
+	}, nil)
+
+	container, err := Sniff(file)
+	if err != nil || container != ContainerWebM {
+		t.Fatalf("expected webm, got %q (%v)", container, err)
+	}
+	got, err := Duration(bytes.NewReader(file), container)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 2500*time.Millisecond {
+		t.Errorf("expected 2.5s, got %s", got)
+	}
+}
+
+func TestSniffRejectsOtherFiles(t *testing.T) {
+	for _, header := range [][]byte{[]byte("%PDF-1.4\n"), {0x1a, 0x45, 0xdf, 0xa3, 0x88}, nil} {
+		if _, err := Sniff(header); err != ErrUnsupported {
+			t.Errorf("expected ErrUnsupported for %q, got %v", header, err)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Announcement is a message from the course staff to the students of a course
+type Announcement struct {
+    ID          string     `json:"id"`
+    CourseID    string     `json:"course_id"`
+    AuthorID    *string    `json:"author_id"`
+    AuthorName  string     `json:"author_name"`
+    Title       string     `json:"title"`
+    Body        string     `json:"body"`
+    IsPinned    bool       `json:"is_pinned"`
+    IsRead      bool       `json:"is_read"` // Whether the student has read it, always false for staff
+    EmailedAt   *time.Time `json:"emailed_at"`
+    CreatedAt   time.Time  `json:"created_at"`
+    UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Certificate is proof that a student completed a course. The student name and course title are
+// copied when it is issued so the certificate keeps reading the same afterwards.
+type Certificate struct {
+    ID            string     `json:"id"`
+    Serial        string     `json:"serial"`
+    UserID        *string    `json:"user_id"`
+    CourseID      *string    `json:"course_id"`
+    StudentName   string     `json:"student_name"`
+    CourseTitle   string     `json:"course_title"`
+    CompletedAt   time.Time  `json:"completed_at"`
+    IssuedAt      time.Time  `json:"issued_at"`
+    RevokedAt     *time.Time `json:"revoked_at"`
+    RevokedBy     *string    `json:"revoked_by,omitempty"`
+    RevokeReason  *string    `json:"revoke_reason"`
+}
+
+// CertificateVerification is what the public verification endpoint tells about a certificate
+type CertificateVerification struct {
+    Serial        string     `json:"serial"`
+    Valid         bool       `json:"valid"` // False once an admin revoked the certificate
+    StudentName   string     `json:"student_name"`
+    CourseTitle   string     `json:"course_title"`
+    CompletedAt   time.Time  `json:"completed_at"`
+    IssuedAt      time.Time  `json:"issued_at"`
+    RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+    RevokeReason  *string    `json:"revoke_reason,omitempty"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Cohort is a term-based run of a course with its own enrollment and access windows
+type Cohort struct {
+    ID                  string     `json:"id"`
+    CourseID            string     `json:"course_id"`
+    Name                string     `json:"name"`
+    EnrollmentOpensAt   time.Time  `json:"enrollment_opens_at"`
+    EnrollmentClosesAt  time.Time  `json:"enrollment_closes_at"`
+    AccessStartsAt      time.Time  `json:"access_starts_at"`
+    AccessEndsAt        *time.Time `json:"access_ends_at"` // nil keeps access open after the term
+    CreatedAt           time.Time  `json:"created_at"`
+}
+
+// EnrollmentAccess is a student's enrollment in a course and the cohort it belongs to
+type EnrollmentAccess struct {
+    CohortID    string     // Empty for self-paced enrollments, which have no access window
+    StartsAt    time.Time
+    EndsAt      *time.Time
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Discount types of a coupon
+const (
+    CouponPercent = "percent" // DiscountValue is a percentage of the price
+    CouponFixed   = "fixed"   // DiscountValue is an amount in the minor unit of Currency
+)
+
+// Coupon is a discount code applied at checkout
+type Coupon struct {
+    ID             string     `json:"id"`
+    Code           string     `json:"code"`
+    DiscountType   string     `json:"discount_type"` // 'percent', 'fixed'
+    DiscountValue  int        `json:"discount_value"`
+    Currency       *string    `json:"currency"` // Set for fixed discounts
+    CourseID       *string    `json:"course_id"` // nil applies to every paid course
+    MaxUses        *int       `json:"max_uses"` // nil means unlimited
+    PerUserLimit   *int       `json:"per_user_limit"` // nil means unlimited
+    Uses           int        `json:"uses"` // Paid orders that used the coupon
+    StartsAt       *time.Time `json:"starts_at"`
+    EndsAt         *time.Time `json:"ends_at"`
+    CreatedBy      *string    `json:"created_by"`
+    CreatedAt      time.Time  `json:"created_at"`
+}
+
+// CouponRedemption is a paid order that used a coupon
+type CouponRedemption struct {
+    ID            string    `json:"id"`
+    CouponID      string    `json:"coupon_id"`
+    OrderID       string    `json:"order_id"`
+    UserID        string    `json:"user_id"`
+    FullName      string    `json:"full_name"`
+    Email         string    `json:"email"`
+    CourseID      string    `json:"course_id"`
+    CourseTitle   string    `json:"course_title"`
+    DiscountCents int       `json:"discount_cents"`
+    AmountCents   int       `json:"amount_cents"` // Amount paid after the discount
+    Currency      string    `json:"currency"`
+    RedeemedAt    time.Time `json:"redeemed_at"`
+}
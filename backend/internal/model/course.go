@@ -11,6 +11,31 @@ type Course struct {
     Title           string            `json:"title"`
     Description     string            `json:"description"`
     CoverImageURL   sql.NullString    `json:"cover_image_url,omitzero"`
+    IsDraft         bool              `json:"is_draft"`
+    IsTemplate      bool              `json:"is_template"`
+    Capacity        *int              `json:"capacity"` // Maximum enrolled students, nil means unlimited
+    EnrollmentMode  string            `json:"enrollment_mode"` // 'open', 'approval', 'code'
+    PriceCents      int               `json:"price_cents"` // Price in the currency's minor unit, 0 for free courses
+    Currency        string            `json:"currency"` // ISO 4217 code
+    RatingAverage   float64           `json:"rating_average"` // Average of the visible reviews, 0 without reviews
+    RatingCount     int               `json:"rating_count"`
     CreatedAt       time.Time         `json:"created_at"`
     UpdatedAt       time.Time         `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// CourseDetail is the public view of a course with its instructor and syllabus
+type CourseDetail struct {
+    Course
+    InstructorName  string            `json:"instructor_name"`
+    SeatsLeft       *int              `json:"seats_left"` // nil when the course has no capacity
+    Prerequisites   []Prerequisite    `json:"prerequisites"`
+    Outline         []MaterialOutline `json:"outline"`
+}
+// WaitlistEntry is a student waiting for a seat in a full course
+type WaitlistEntry struct {
+    UserID      string            `json:"user_id"`
+    FullName    string            `json:"full_name"`
+    Email       string            `json:"email"`
+    Position    int               `json:"position"`
+    CreatedAt   time.Time         `json:"created_at"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// Thread kinds, questions belong to the Q&A area and can be upvoted and get an accepted answer
+const (
+    ThreadKindDiscussion = "discussion"
+    ThreadKindQuestion   = "question"
+)
+
+// Thread is a discussion about a course, or about one of its materials when MaterialID is set
+type Thread struct {
+    ID              string     `json:"id"`
+    CourseID        string     `json:"course_id"`
+    MaterialID      *string    `json:"material_id"`
+    Kind            string     `json:"kind" enums:"discussion,question"`
+    AuthorID        *string    `json:"author_id"`
+    AuthorName      string     `json:"author_name"`
+    Title           string     `json:"title"`
+    Body            string     `json:"body"`
+    IsPinned        bool       `json:"is_pinned"`
+    IsLocked        bool       `json:"is_locked"` // Locked threads take no new replies from students
+    ReplyCount      int        `json:"reply_count"`
+    VoteCount       int        `json:"vote_count"`
+    Voted           bool       `json:"voted"` // Whether the reader has upvoted the question
+    AcceptedPostID  *string    `json:"accepted_post_id"`
+    EditedAt        *time.Time `json:"edited_at"`
+    LastActivityAt  time.Time  `json:"last_activity_at"`
+    CreatedAt       time.Time  `json:"created_at"`
+}
+
+// CourseQuestion is a question listed together with the title of its course
+type CourseQuestion struct {
+    Thread
+    CourseTitle string `json:"course_title"`
+}
+
+// Post is a reply in a thread, nested under another reply when ParentID is set
+type Post struct {
+    ID          string     `json:"id"`
+    ThreadID    string     `json:"thread_id"`
+    ParentID    *string    `json:"parent_id"`
+    AuthorID    *string    `json:"author_id"`
+    AuthorName  string     `json:"author_name"`
+    Body        string     `json:"body"`
+    VoteCount   int        `json:"vote_count"`
+    Voted       bool       `json:"voted"` // Whether the reader has upvoted the answer
+    EditedAt    *time.Time `json:"edited_at"`
+    CreatedAt   time.Time  `json:"created_at"`
+    Replies     []Post     `json:"replies"`
+}
+
+// ThreadDetail is a thread with its replies nested as a tree
+type ThreadDetail struct {
+    Thread
+    Posts []Post `json:"posts"`
+}
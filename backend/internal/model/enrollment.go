@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// Actions recorded in the enrollment history
+const (
+    EnrollmentActionEnrolled   = "enrolled"   // The student enrolled themselves
+    EnrollmentActionWaitlisted = "waitlisted" // The student joined the waitlist of a full course
+    EnrollmentActionPromoted   = "promoted"   // A freed seat moved the student from the waitlist
+    EnrollmentActionAdded      = "added"      // Course staff enrolled the student
+    EnrollmentActionLeft       = "left"       // The student left the course or its waitlist
+    EnrollmentActionRemoved    = "removed"    // Course staff removed the student
+)
+
+// RosterEntry is a student enrolled in a course
+type RosterEntry struct {
+    UserID          string     `json:"user_id"`
+    FullName        string     `json:"full_name"`
+    Email           string     `json:"email"`
+    CohortID        *string    `json:"cohort_id"`
+    EnrollmentDate  time.Time  `json:"enrollment_date"`
+    CompletedAt     *time.Time `json:"completed_at"`
+}
+
+// EnrollmentEvent is one entry of a course's enrollment history
+type EnrollmentEvent struct {
+    ID          string     `json:"id"`
+    UserID      string     `json:"user_id"`
+    FullName    string     `json:"full_name"`
+    CohortID    *string    `json:"cohort_id"`
+    Action      string     `json:"action"` // 'enrolled', 'waitlisted', 'promoted', 'added', 'left', 'removed'
+    ActorID     *string    `json:"actor_id"` // nil for automatic waitlist promotions
+    CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Enrollment modes of a course
+const (
+    EnrollmentModeOpen     = "open"     // Any student can enroll
+    EnrollmentModeApproval = "approval" // Students request enrollment and staff approve it
+    EnrollmentModeCode     = "code"     // Students redeem an access code
+)
+
+// Statuses of an enrollment request
+const (
+    RequestStatusPending  = "pending"
+    RequestStatusApproved = "approved"
+    RequestStatusRejected = "rejected"
+)
+
+// EnrollmentRequest is a student's request to join a course that requires approval
+type EnrollmentRequest struct {
+    ID          string     `json:"id"`
+    CourseID    string     `json:"course_id"`
+    UserID      string     `json:"user_id"`
+    FullName    string     `json:"full_name"`
+    Email       string     `json:"email"`
+    CohortID    *string    `json:"cohort_id"`
+    Message     string     `json:"message"`
+    Status      string     `json:"status"` // 'pending', 'approved', 'rejected'
+    DecidedBy   *string    `json:"decided_by"`
+    DecidedAt   *time.Time `json:"decided_at"`
+    CreatedAt   time.Time  `json:"created_at"`
+}
+
+// AccessCode lets students enroll in a course that requires a code
+type AccessCode struct {
+    ID          string     `json:"id"`
+    CourseID    string     `json:"course_id"`
+    Code        string     `json:"code"`
+    CohortID    *string    `json:"cohort_id"` // Students redeeming the code join this cohort
+    MaxUses     *int       `json:"max_uses"` // nil means unlimited
+    Uses        int        `json:"uses"`
+    ExpiresAt   *time.Time `json:"expires_at"` // nil never expires
+    CreatedBy   string     `json:"created_by"`
+    CreatedAt   time.Time  `json:"created_at"`
+}
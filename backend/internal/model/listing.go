@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ListParams holds the shared pagination, sorting and filtering options of list endpoints
+type ListParams struct {
+	Limit       int       // Maximum number of items in one page, 0 returns every item
+	Cursor      string    // Opaque cursor returned as NextCursor by the previous page
+	SortBy      string    // Field name to sort by, empty for the endpoint's default
+	SortDesc    bool      // Sort in descending order
+	Role        string    // Filter by user role
+	Status      string    // Filter by user status
+	CreatedFrom time.Time // Inclusive lower bound of the created range, zero to ignore
+	CreatedTo   time.Time // Exclusive upper bound of the created range, zero to ignore
+}
+
+// PageInfo describes where a page sits in the full result set
+type PageInfo struct {
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -5,12 +5,34 @@ import "time"
 type LearningMaterial struct {
     ID           string    `json:"id"`
     CourseID     string    `json:"course_id"`
+    SectionID    *string   `json:"section_id"` // nil for materials outside any section
     Title        string    `json:"title"`
     ContentType  string    `json:"content_type"` // 'text', 'video', 'pdf'
-    TextContent  string    `json:"text_content,omitempty"`
+    TextContent  string    `json:"text_content,omitempty"` // Markdown
+    TextHTML     string    `json:"text_html,omitempty"`    // Sanitized HTML rendered from TextContent
     VideoURL     string    `json:"video_url,omitempty"`
     FileURL      string    `json:"file_url,omitempty"`
+    // Set for uploaded videos, which are served from FileURL
+    DurationSeconds *int   `json:"duration_seconds,omitempty"`
+    FileSizeBytes   *int64 `json:"file_size_bytes,omitempty"`
     Position     int       `json:"position"`
+    IsPreview    bool      `json:"is_preview"`
+    // Drip release, at most one rule is set. Without a rule the material is available right after enrolling.
+    ReleaseAt              *time.Time `json:"release_at"`                // Released at this date
+    ReleaseAfterDays       *int       `json:"release_after_days"`        // Released this many days after the student enrolled
+    ReleaseAfterMaterialID *string    `json:"release_after_material_id"` // Released once the student completed this material
+    // Locked is only set in a student's view of a course, whose locked materials come without content
+    Locked       bool       `json:"locked,omitempty"`
+    UnlocksAt    *time.Time `json:"unlocks_at,omitempty"` // When a locked material opens, nil while it waits for a completion
     CreatedAt    time.Time `json:"created_at"`
     UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// MaterialOutline is the content-free view of a material shown in a course syllabus
+type MaterialOutline struct {
+    ID          string `json:"id"`
+    Title       string `json:"title"`
+    ContentType string `json:"content_type"`
+    Position    int    `json:"position"`
+    IsPreview   bool   `json:"is_preview"`
 }
\ No newline at end of file
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Statuses of an order
+const (
+    OrderStatusPending = "pending" // Waiting for the payment provider to confirm the payment
+    OrderStatusPaid    = "paid"    // Payment confirmed, the student is enrolled
+    OrderStatusFailed  = "failed"  // Payment failed or was cancelled
+)
+
+// Order is a student's purchase of a paid course
+type Order struct {
+    ID            string     `json:"id"`
+    UserID        string     `json:"user_id"`
+    CourseID      string     `json:"course_id"`
+    CourseTitle   string     `json:"course_title"`
+    CohortID      *string    `json:"cohort_id"`
+    AmountCents   int        `json:"amount_cents"` // Amount charged, after the discount
+    DiscountCents int        `json:"discount_cents"`
+    CouponID      *string    `json:"coupon_id"`
+    Currency      string     `json:"currency"`
+    Status        string     `json:"status"` // 'pending', 'paid', 'failed'
+    Provider      *string    `json:"provider"`
+    ProviderRef   *string    `json:"-"`
+    CheckoutURL   *string    `json:"checkout_url"`
+    PaidAt        *time.Time `json:"paid_at"`
+    CreatedAt     time.Time  `json:"created_at"`
+    UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,13 @@
+package model
+
+const (
+    RequirementEnrolled  = "enrolled"
+    RequirementCompleted = "completed"
+)
+
+// Prerequisite is a course that must be enrolled in or completed before enrolling in another
+type Prerequisite struct {
+    CourseID    string `json:"course_id"`
+    Title       string `json:"title"`
+    Requirement string `json:"requirement"` // 'enrolled', 'completed'
+}
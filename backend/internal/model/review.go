@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Review is an enrolled student's rating and review of a course
+type Review struct {
+    ID          string     `json:"id"`
+    CourseID    string     `json:"course_id"`
+    UserID      string     `json:"user_id"`
+    FullName    string     `json:"full_name"`
+    Rating      int        `json:"rating"` // 1 to 5
+    Body        string     `json:"body"`
+    Reply       *string    `json:"reply"` // The course staff's public reply
+    RepliedBy   *string    `json:"replied_by"`
+    RepliedAt   *time.Time `json:"replied_at"`
+    IsHidden    bool       `json:"is_hidden"` // Hidden by an admin
+    CreatedAt   time.Time  `json:"created_at"`
+    UpdatedAt   time.Time  `json:"updated_at"`
+}
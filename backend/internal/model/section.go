@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Section is a titled, ordered group of materials, nested under another section when ParentID is set.
+// Sections nest one level, so a subsection never has subsections of its own.
+type Section struct {
+    ID          string             `json:"id"`
+    CourseID    string             `json:"course_id"`
+    ParentID    *string            `json:"parent_id"`
+    Title       string             `json:"title"`
+    Position    int                `json:"position"` // Order among the sections sharing the same parent
+    Sections    []Section          `json:"sections"` // Subsections
+    Materials   []LearningMaterial `json:"materials"`
+    CreatedAt   time.Time          `json:"created_at"`
+    UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// CourseContent is the materials of a course arranged in its sections
+type CourseContent struct {
+    Sections    []Section          `json:"sections"`
+    Materials   []LearningMaterial `json:"materials"` // Materials outside any section
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+const (
+    StaffRoleOwner        = "owner"
+    StaffRoleCoInstructor = "co_instructor"
+    StaffRoleTA           = "ta"
+)
+
+type CourseStaff struct {
+    CourseID    string    `json:"course_id"`
+    UserID      string    `json:"user_id"`
+    FullName    string    `json:"full_name"`
+    Email       string    `json:"email"`
+    Role        string    `json:"role"` // 'owner', 'co_instructor', 'ta'
+    CreatedAt   time.Time `json:"created_at"`
+}
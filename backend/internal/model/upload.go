@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Kinds of resumable uploads, each attached to its course in a different way once complete
+const (
+    UploadKindCover = "cover" // Replaces the course cover image
+    UploadKindPDF   = "pdf"   // Becomes a new PDF material
+    UploadKindVideo = "video" // Becomes a new video material
+)
+
+// Upload is a resumable upload of a file in chunks
+type Upload struct {
+    ID           string     `json:"id"`
+    UserID       string     `json:"user_id"`
+    CourseID     string     `json:"course_id"`
+    Kind         string     `json:"kind"`
+    Title        string     `json:"title,omitempty"`
+    Filename     string     `json:"filename"`
+    Filetype     string     `json:"filetype,omitempty"`
+    Length       int64      `json:"upload_length"`
+    Offset       int64      `json:"upload_offset"` // Bytes received so far
+    FileURL      *string    `json:"file_url"`      // Set once the upload is complete and attached
+    MaterialID   *string    `json:"material_id"`   // The material created from a pdf or video upload
+    ExpiresAt    time.Time  `json:"expires_at"`
+    CompletedAt  *time.Time `json:"completed_at"`
+    CreatedAt    time.Time  `json:"created_at"`
+}
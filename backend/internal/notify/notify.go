@@ -0,0 +1,85 @@
+// Package notify delivers notifications to users by email.
+package notify
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// Message is a plain text notification sent to each recipient separately
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to users
+type Notifier interface {
+	Send(msg Message) error
+}
+
+// LogNotifier writes messages to the server log instead of sending them, for servers without a mail server
+type LogNotifier struct{}
+
+// Send implements Notifier
+func (LogNotifier) Send(msg Message) error {
+	log.Printf("Notification %q for %d recipients (no SMTP server configured, not sent)", msg.Subject, len(msg.To))
+	return nil
+}
+
+// SMTPNotifier sends messages through an SMTP server
+type SMTPNotifier struct {
+	Addr string // host:port of the server
+	From string
+	Auth smtp.Auth // nil for servers without authentication
+}
+
+// Send implements Notifier. Recipients get separate emails so they do not see each other's addresses.
+func (n *SMTPNotifier) Send(msg Message) error {
+	var failed int
+	for _, to := range msg.To {
+		if err := smtp.SendMail(n.Addr, n.Auth, n.From, []string{to}, buildMessage(n.From, to, msg.Subject, msg.Body)); err != nil {
+			log.Printf("Error sending notification to %s: %v", to, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d notifications could not be sent", failed, len(msg.To))
+	}
+	return nil
+}
+
+// buildMessage formats an email. Line breaks are removed from header values so they cannot add headers.
+func buildMessage(from, to, subject, body string) []byte {
+	header := strings.NewReplacer("\r", "", "\n", " ")
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(to))
+	fmt.Fprintf(&b, "Subject: %s\r\n", header.Replace(subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// FromEnv returns an SMTPNotifier configured by SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD
+// and SMTP_FROM, or a LogNotifier when SMTP_HOST is not set
+func FromEnv() Notifier {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return LogNotifier{}
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	n := &SMTPNotifier{Addr: host + ":" + port, From: os.Getenv("SMTP_FROM")}
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		n.Auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	return n
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMessage(t *testing.T) {
+	msg := string(buildMessage("courses@example.com", "student@example.com", "Deadline moved\r\nBcc: attacker@example.com", "Line one\nLine two"))
+
+	// A line break in the subject must not start a new header
+	if strings.Contains(msg, "\r\nBcc:") {
+		t.Errorf("expected the subject to stay on one line, but got %q", msg)
+	}
+	if !strings.Contains(msg, "Subject: Deadline moved Bcc: attacker@example.com\r\n") {
+		t.Errorf("expected the flattened subject header, but got %q", msg)
+	}
+
+	// The body uses CRLF line endings after the blank line that ends the headers
+	if !strings.HasSuffix(msg, "\r\n\r\nLine one\r\nLine two") {
+		t.Errorf("expected the body with CRLF line endings, but got %q", msg)
+	}
+}
@@ -0,0 +1,36 @@
+package payment
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFakeProviderParseWebhook(t *testing.T) {
+	provider := NewFakeProvider("test-secret")
+	payload := []byte(`{"id":"evt_1","type":"payment.succeeded","reference":"fake_order-1"}`)
+
+	// A correctly signed event is decoded
+	header := http.Header{}
+	header.Set(SignatureHeader, provider.Sign(payload))
+	event, err := provider.ParseWebhook(payload, header)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ID != "evt_1" || event.Type != EventPaymentSucceeded || event.Reference != "fake_order-1" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	// A tampered payload no longer matches its signature
+	tampered := []byte(`{"id":"evt_1","type":"payment.succeeded","reference":"fake_order-2"}`)
+	if _, err := provider.ParseWebhook(tampered, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for a tampered payload, but got %v", err)
+	}
+
+	// Without a secret nothing is accepted, not even an empty signature
+	unconfigured := NewFakeProvider("")
+	header.Set(SignatureHeader, unconfigured.Sign(payload))
+	if _, err := unconfigured.ParseWebhook(payload, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature without a secret, but got %v", err)
+	}
+}
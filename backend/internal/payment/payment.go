@@ -0,0 +1,42 @@
+// Package payment defines how paid courses are charged through an external payment provider.
+package payment
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+// Types of webhook events
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+)
+
+// ErrInvalidSignature is returned for webhook requests that were not signed by the provider
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Checkout is a payment session opened with a provider for an order
+type Checkout struct {
+	Reference string // The provider's ID of the session, sent back in webhook events
+	URL       string // Where the student completes the payment
+}
+
+// Event is a verified webhook notification about a checkout
+type Event struct {
+	ID        string `json:"id"`   // Unique per event, retried deliveries repeat it
+	Type      string `json:"type"` // EventPaymentSucceeded or EventPaymentFailed
+	Reference string `json:"reference"`
+}
+
+// PaymentProvider charges students for orders and notifies the server through signed webhooks
+type PaymentProvider interface {
+	// Name identifies the provider in webhook URLs and on orders
+	Name() string
+	// CreateCheckout opens a payment session for the order of the course
+	CreateCheckout(order *model.Order, course *model.Course) (*Checkout, error)
+	// ParseWebhook verifies the signature of a webhook request and decodes its event.
+	// It returns ErrInvalidSignature when the request was not signed by the provider.
+	ParseWebhook(payload []byte, header http.Header) (*Event, error)
+}
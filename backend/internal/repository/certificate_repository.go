@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/certificate"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+type CertificateRepository struct {
+	DB *sql.DB
+}
+
+func NewCertificateRepository(db *sql.DB) *CertificateRepository {
+	return &CertificateRepository{DB: db}
+}
+
+const certificateColumns = `id, serial, user_id, course_id, student_name, course_title, completed_at, issued_at,
+	revoked_at, revoked_by, revoke_reason`
+
+func scanCertificate(row rowScanner) (model.Certificate, error) {
+	var cert model.Certificate
+	err := row.Scan(
+		&cert.ID, &cert.Serial, &cert.UserID, &cert.CourseID, &cert.StudentName, &cert.CourseTitle, &cert.CompletedAt,
+		&cert.IssuedAt, &cert.RevokedAt, &cert.RevokedBy, &cert.RevokeReason,
+	)
+	return cert, err
+}
+
+// execer runs statements on the database or inside a transaction
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// issueCertificate issues the certificate of a completed course, copying the student's name and the course title.
+// It does nothing when the course is not completed or the certificate was already issued.
+func issueCertificate(exec execer, userID, courseID string) error {
+	serial, err := certificate.NewSerial()
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO certificates (serial, user_id, course_id, student_name, course_title, completed_at)
+		SELECT $1, e.user_id, e.course_id, u.full_name, c.title, e.completed_at
+		FROM enrollments e
+		JOIN users u ON u.id = e.user_id
+		JOIN courses c ON c.id = e.course_id
+		WHERE e.user_id = $2 AND e.course_id = $3 AND e.completed_at IS NOT NULL
+		ON CONFLICT (user_id, course_id) DO NOTHING`
+	if _, err := exec.Exec(query, serial, userID, courseID); err != nil {
+		log.Printf("Error issuing certificate: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetCertificate retrieves the certificate of a student for a course, issuing it first when the student
+// completed the course before certificates existed. It returns nil when the course is not completed.
+func (r *CertificateRepository) GetCertificate(userID, courseID string) (*model.Certificate, error) {
+	if err := issueCertificate(r.DB, userID, courseID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + certificateColumns + ` FROM certificates WHERE user_id = $1 AND course_id = $2`
+	cert, err := scanCertificate(r.DB.QueryRow(query, userID, courseID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// GetCertificateBySerial retrieves a certificate by its serial, or nil when no certificate has it
+func (r *CertificateRepository) GetCertificateBySerial(serial string) (*model.Certificate, error) {
+	query := `SELECT ` + certificateColumns + ` FROM certificates WHERE serial = $1`
+	cert, err := scanCertificate(r.DB.QueryRow(query, serial))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// GetCertificatesByUserID retrieves the certificates of a student, newest first
+func (r *CertificateRepository) GetCertificatesByUserID(userID string) ([]model.Certificate, error) {
+	query := `SELECT ` + certificateColumns + ` FROM certificates WHERE user_id = $1 ORDER BY completed_at DESC`
+	rows, err := r.DB.Query(query, userID)
+	if err != nil {
+		log.Printf("Error fetching certificates: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	certs := []model.Certificate{}
+	for rows.Next() {
+		cert, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, rows.Err()
+}
+
+// RevokeCertificate revokes a certificate. Revoking it again only updates the reason.
+func (r *CertificateRepository) RevokeCertificate(serial, adminID, reason string) error {
+	query := `UPDATE certificates SET revoked_at = COALESCE(revoked_at, NOW()), revoked_by = $1, revoke_reason = $2 WHERE serial = $3`
+	result, err := r.DB.Exec(query, adminID, reason, serial)
+	if err != nil {
+		log.Printf("Error revoking certificate: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetCertificateIssuesOnCompletion(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCertificateRepository(db)
+	completedAt := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO certificates (serial, user_id, course_id, student_name, course_title, completed_at)`)).
+		WithArgs(sqlmock.AnyArg(), "student-1", "course-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM certificates WHERE user_id = $1 AND course_id = $2`)).
+		WithArgs("student-1", "course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "serial", "user_id", "course_id", "student_name", "course_title",
+			"completed_at", "issued_at", "revoked_at", "revoked_by", "revoke_reason"}).
+			AddRow("cert-1", "CERT-AAAA-BBBB-CCCC-DDDD", "student-1", "course-1", "Student One", "Go for Beginners",
+				completedAt, completedAt, nil, nil, nil))
+
+	// Run the function that will be tested
+	cert, err := repo.GetCertificate("student-1", "course-1")
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("error was not expected while fetching certificate: %s", err)
+	}
+	if cert == nil || cert.StudentName != "Student One" || !cert.CompletedAt.Equal(completedAt) {
+		t.Errorf("expected the issued certificate, but got %+v", cert)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+// GetAnnouncements retrieves the announcements of a course, pinned ones first and then newest first.
+// IsRead is set for userID, pass an empty userID for staff views.
+func (r *CourseRepository) GetAnnouncements(courseID, userID string) ([]model.Announcement, error) {
+	query := `
+		SELECT a.id, a.course_id, a.author_id, COALESCE(u.full_name, ''), a.title, a.body, a.is_pinned,
+		       EXISTS(SELECT 1 FROM announcement_reads ar WHERE ar.announcement_id = a.id AND ar.user_id::text = $2),
+		       a.emailed_at, a.created_at, a.updated_at
+		FROM course_announcements a
+		LEFT JOIN users u ON u.id = a.author_id
+		WHERE a.course_id = $1
+		ORDER BY a.is_pinned DESC, a.created_at DESC`
+	rows, err := r.DB.Query(query, courseID, userID)
+	if err != nil {
+		log.Printf("Error fetching announcements: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	announcements := []model.Announcement{}
+	for rows.Next() {
+		var a model.Announcement
+		if err := rows.Scan(&a.ID, &a.CourseID, &a.AuthorID, &a.AuthorName, &a.Title, &a.Body, &a.IsPinned,
+			&a.IsRead, &a.EmailedAt, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, a)
+	}
+	return announcements, rows.Err()
+}
+
+// CreateAnnouncement stores a new announcement of a course
+func (r *CourseRepository) CreateAnnouncement(a *model.Announcement) error {
+	query := `
+		WITH a AS (
+			INSERT INTO course_announcements (course_id, author_id, title, body, is_pinned)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, author_id, created_at, updated_at
+		)
+		SELECT a.id, COALESCE(u.full_name, ''), a.created_at, a.updated_at FROM a LEFT JOIN users u ON u.id = a.author_id`
+	err := r.DB.QueryRow(query, a.CourseID, a.AuthorID, a.Title, a.Body, a.IsPinned).Scan(&a.ID, &a.AuthorName, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		log.Printf("Error creating announcement: %v", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateAnnouncement changes the title and body of an announcement and fills in its other fields.
+// It returns sql.ErrNoRows when the announcement does not belong to the course.
+func (r *CourseRepository) UpdateAnnouncement(a *model.Announcement) error {
+	query := `
+		WITH a AS (
+			UPDATE course_announcements SET title = $1, body = $2, updated_at = NOW()
+			WHERE id = $3 AND course_id = $4
+			RETURNING author_id, is_pinned, emailed_at, created_at, updated_at
+		)
+		SELECT a.author_id, COALESCE(u.full_name, ''), a.is_pinned, a.emailed_at, a.created_at, a.updated_at
+		FROM a LEFT JOIN users u ON u.id = a.author_id`
+	err := r.DB.QueryRow(query, a.Title, a.Body, a.ID, a.CourseID).Scan(
+		&a.AuthorID, &a.AuthorName, &a.IsPinned, &a.EmailedAt, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error updating announcement: %v", err)
+	}
+	return err
+}
+
+// SetAnnouncementPinned pins an announcement to the top of the list or unpins it.
+// It returns sql.ErrNoRows when the announcement does not belong to the course.
+func (r *CourseRepository) SetAnnouncementPinned(courseID, announcementID string, pinned bool) error {
+	query := `UPDATE course_announcements SET is_pinned = $1 WHERE id = $2 AND course_id = $3`
+	result, err := r.DB.Exec(query, pinned, announcementID, courseID)
+	if err != nil {
+		log.Printf("Error pinning announcement: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteAnnouncement deletes an announcement and its read receipts.
+// It returns sql.ErrNoRows when the announcement does not belong to the course.
+func (r *CourseRepository) DeleteAnnouncement(courseID, announcementID string) error {
+	query := `DELETE FROM course_announcements WHERE id = $1 AND course_id = $2`
+	result, err := r.DB.Exec(query, announcementID, courseID)
+	if err != nil {
+		log.Printf("Error deleting announcement: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MarkAnnouncementEmailed records that the announcement was sent to the enrolled students by email
+func (r *CourseRepository) MarkAnnouncementEmailed(a *model.Announcement) error {
+	return r.DB.QueryRow(`UPDATE course_announcements SET emailed_at = NOW() WHERE id = $1 RETURNING emailed_at`, a.ID).Scan(&a.EmailedAt)
+}
+
+// MarkAnnouncementRead records that the student read an announcement, marking it again changes nothing.
+// It returns sql.ErrNoRows when the announcement does not belong to the course.
+func (r *CourseRepository) MarkAnnouncementRead(courseID, announcementID, userID string) error {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM course_announcements WHERE id = $1 AND course_id = $2)`
+	if err := r.DB.QueryRow(query, announcementID, courseID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	readQuery := `INSERT INTO announcement_reads (announcement_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if _, err := r.DB.Exec(readQuery, announcementID, userID); err != nil {
+		log.Printf("Error marking announcement read: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetEnrolledStudentEmails retrieves the email addresses of the active students enrolled in a course
+func (r *CourseRepository) GetEnrolledStudentEmails(courseID string) ([]string, error) {
+	query := `SELECT u.email FROM enrollments e JOIN users u ON u.id = e.user_id
+	           WHERE e.course_id = $1 AND u.status = 'active' ORDER BY u.email`
+	rows, err := r.DB.Query(query, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMarkAnnouncementRead(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+	existsQuery := regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM course_announcements WHERE id = $1 AND course_id = $2)`)
+
+	// An announcement of the course is marked read, repeating it is harmless
+	mock.ExpectQuery(existsQuery).WithArgs("announcement-1", "course-1").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO announcement_reads (announcement_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
+		WithArgs("announcement-1", "student-1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// An announcement of another course is not found
+	mock.ExpectQuery(existsQuery).WithArgs("announcement-2", "course-1").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	// Run the function that will be tested
+	if err := repo.MarkAnnouncementRead("course-1", "announcement-1", "student-1"); err != nil {
+		t.Errorf("error was not expected while marking announcement read: %s", err)
+	}
+	if err := repo.MarkAnnouncementRead("course-1", "announcement-2", "student-1"); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows for an announcement of another course, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+)
+
+// nextMaterialPosition locks the course and returns the position after its last material.
+// The lock is held until tx ends, so concurrent inserts into the same course get consecutive positions.
+func nextMaterialPosition(tx *sql.Tx, courseID string) (int, error) {
+	if err := lockCourseContent(tx, courseID); err != nil {
+		return 0, err
+	}
+
+	var lastPosition int
+	query := `SELECT COALESCE(MAX(position), 0) FROM learning_materials WHERE course_id = $1`
+	if err := tx.QueryRow(query, courseID).Scan(&lastPosition); err != nil {
+		log.Printf("Error getting last material position: %v", err)
+		return 0, err
+	}
+	return lastPosition + 1, nil
+}
+
+// materialOrder returns the material IDs of a course in position order, with the section of every material
+func materialOrder(tx *sql.Tx, courseID string) ([]string, map[string]*string, error) {
+	rows, err := tx.Query(`SELECT id, section_id FROM learning_materials WHERE course_id = $1 ORDER BY position ASC`, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	sections := make(map[string]*string)
+	for rows.Next() {
+		var id string
+		var sectionID *string
+		if err := rows.Scan(&id, &sectionID); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		sections[id] = sectionID
+	}
+	return ids, sections, rows.Err()
+}
+
+// writeMaterialOrder numbers the materials 1..n in the order of ordered, updating only those whose position changed.
+// The unique position constraint is deferred until commit, so positions can be swapped freely.
+func writeMaterialOrder(tx *sql.Tx, current, ordered []string) error {
+	query := `UPDATE learning_materials SET position = $1, updated_at = NOW() WHERE id = $2`
+	for i, id := range ordered {
+		if current[i] == id {
+			continue
+		}
+		if _, err := tx.Exec(query, i+1, id); err != nil {
+			log.Printf("Error reordering materials: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// ReorderMaterials sets the material order of a course to materialIDs, which must list every material exactly once
+func (r *CourseRepository) ReorderMaterials(courseID string, materialIDs []string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCourseContent(tx, courseID); err != nil {
+		return err
+	}
+
+	current, sections, err := materialOrder(tx, courseID)
+	if err != nil {
+		return err
+	}
+
+	if len(materialIDs) != len(current) {
+		return ErrMaterialOrderMismatch
+	}
+	for _, id := range materialIDs {
+		if _, ok := sections[id]; !ok {
+			return ErrMaterialOrderMismatch
+		}
+		delete(sections, id) // A repeated material ID will not be found again
+	}
+
+	if err := writeMaterialOrder(tx, current, materialIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MoveMaterial moves a material right before or after anchorID, into the anchor's section when that differs.
+// It returns sql.ErrNoRows when either material is not in the course.
+func (r *CourseRepository) MoveMaterial(courseID, materialID, anchorID string, after bool) error {
+	if materialID == anchorID {
+		return ErrMaterialMoveSelf
+	}
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCourseContent(tx, courseID); err != nil {
+		return err
+	}
+
+	current, sections, err := materialOrder(tx, courseID)
+	if err != nil {
+		return err
+	}
+	sectionID, materialFound := sections[materialID]
+	anchorSectionID, anchorFound := sections[anchorID]
+	if !materialFound || !anchorFound {
+		return sql.ErrNoRows
+	}
+
+	ordered := make([]string, 0, len(current))
+	for _, id := range current {
+		switch id {
+		case materialID:
+			continue
+		case anchorID:
+			if after {
+				ordered = append(ordered, anchorID, materialID)
+			} else {
+				ordered = append(ordered, materialID, anchorID)
+			}
+		default:
+			ordered = append(ordered, id)
+		}
+	}
+
+	if err := writeMaterialOrder(tx, current, ordered); err != nil {
+		return err
+	}
+
+	if !sameSection(sectionID, anchorSectionID) {
+		query := `UPDATE learning_materials SET section_id = $1, updated_at = NOW() WHERE id = $2`
+		if _, err := tx.Exec(query, anchorSectionID, materialID); err != nil {
+			log.Printf("Error moving material to section: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// sameSection reports whether two optional section IDs name the same section, or both no section
+func sameSection(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
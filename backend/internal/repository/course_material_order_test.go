@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMoveMaterialAfterAnchorInOtherSection(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM courses WHERE id = $1 FOR UPDATE`)).
+		WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("course-1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, section_id FROM learning_materials WHERE course_id = $1 ORDER BY position ASC`)).
+		WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "section_id"}).
+			AddRow("m-1", nil).
+			AddRow("m-2", nil).
+			AddRow("m-3", "section-1").
+			AddRow("m-4", "section-1"))
+	// Moving m-1 after m-3 renumbers m-2, m-3 and m-1 while m-4 keeps its position
+	update := regexp.QuoteMeta(`UPDATE learning_materials SET position = $1, updated_at = NOW() WHERE id = $2`)
+	mock.ExpectExec(update).WithArgs(1, "m-2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(2, "m-3").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(3, "m-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE learning_materials SET section_id = $1, updated_at = NOW() WHERE id = $2`)).
+		WithArgs("section-1", "m-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.MoveMaterial("course-1", "m-1", "m-3", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReorderMaterialsRejectsRepeatedMaterial(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM courses WHERE id = $1 FOR UPDATE`)).
+		WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("course-1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, section_id FROM learning_materials WHERE course_id = $1`)).
+		WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "section_id"}).AddRow("m-1", nil).AddRow("m-2", nil))
+	mock.ExpectRollback()
+
+	if err := repo.ReorderMaterials("course-1", []string{"m-1", "m-1"}); err != ErrMaterialOrderMismatch {
+		t.Fatalf("expected ErrMaterialOrderMismatch, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+// releaseLock works out whether a material is still locked for a student who enrolled at enrolledAt.
+// unlocksAt is nil while the material waits for another material to be completed.
+func releaseLock(material model.LearningMaterial, enrolledAt, now time.Time, completed map[string]bool) (locked bool, unlocksAt *time.Time) {
+	switch {
+	case material.ReleaseAt != nil:
+		if now.Before(*material.ReleaseAt) {
+			return true, material.ReleaseAt
+		}
+	case material.ReleaseAfterDays != nil:
+		releaseAt := enrolledAt.AddDate(0, 0, *material.ReleaseAfterDays)
+		if now.Before(releaseAt) {
+			return true, &releaseAt
+		}
+	case material.ReleaseAfterMaterialID != nil:
+		if !completed[*material.ReleaseAfterMaterialID] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lockMaterials marks the materials a student cannot open yet and strips their content
+func lockMaterials(materials []model.LearningMaterial, enrolledAt, now time.Time, completed map[string]bool) {
+	for i := range materials {
+		locked, unlocksAt := releaseLock(materials[i], enrolledAt, now, completed)
+		if !locked {
+			continue
+		}
+		materials[i].Locked = true
+		materials[i].UnlocksAt = unlocksAt
+		materials[i].TextContent = ""
+		materials[i].TextHTML = ""
+		materials[i].VideoURL = ""
+		materials[i].FileURL = ""
+	}
+}
+
+// GetStudentCourseContent retrieves the sections and materials of a course as a student sees them,
+// with the materials not released to the student yet locked and without content
+func (r *CourseRepository) GetStudentCourseContent(studentID, courseID string) (*model.CourseContent, error) {
+	var enrolledAt time.Time
+	query := `SELECT enrollment_date FROM enrollments WHERE user_id = $1 AND course_id = $2`
+	if err := r.DB.QueryRow(query, studentID, courseID).Scan(&enrolledAt); err != nil {
+		return nil, err
+	}
+
+	completedQuery := `
+		SELECT mc.material_id FROM material_completions mc
+		JOIN learning_materials m ON m.id = mc.material_id
+		WHERE mc.user_id = $1 AND m.course_id = $2`
+	rows, err := r.DB.Query(completedQuery, studentID, courseID)
+	if err != nil {
+		log.Printf("Error fetching completed materials: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	completed := make(map[string]bool)
+	for rows.Next() {
+		var materialID string
+		if err := rows.Scan(&materialID); err != nil {
+			return nil, err
+		}
+		completed[materialID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sections, err := r.GetSections(courseID)
+	if err != nil {
+		return nil, err
+	}
+	materials, err := r.GetMaterialsByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	lockMaterials(materials, enrolledAt, time.Now(), completed)
+	return buildCourseContent(sections, materials), nil
+}
+
+// checkMaterialReleased returns ErrMaterialLocked when the material is not released to the student yet,
+// and sql.ErrNoRows when it is not a material of the course
+func checkMaterialReleased(tx *sql.Tx, studentID, courseID, materialID string) error {
+	var material model.LearningMaterial
+	var enrolledAt time.Time
+	var requiredCompleted bool
+	query := `
+		SELECT m.release_at, m.release_after_days, m.release_after_material_id, e.enrollment_date,
+		       EXISTS(SELECT 1 FROM material_completions mc WHERE mc.user_id = $1 AND mc.material_id = m.release_after_material_id)
+		FROM learning_materials m
+		JOIN enrollments e ON e.course_id = m.course_id AND e.user_id = $1
+		WHERE m.id = $2 AND m.course_id = $3`
+	err := tx.QueryRow(query, studentID, materialID, courseID).
+		Scan(&material.ReleaseAt, &material.ReleaseAfterDays, &material.ReleaseAfterMaterialID, &enrolledAt, &requiredCompleted)
+	if err != nil {
+		return err
+	}
+
+	completed := map[string]bool{}
+	if material.ReleaseAfterMaterialID != nil {
+		completed[*material.ReleaseAfterMaterialID] = requiredCompleted
+	}
+	if locked, _ := releaseLock(material, enrolledAt, time.Now(), completed); locked {
+		return ErrMaterialLocked
+	}
+	return nil
+}
+
+// SetMaterialRelease sets the release rule of a material, clearing it when every rule field is nil.
+// A material can wait for another material of the same course, as long as that one does not wait for it
+// in turn, directly or through other materials. The chain of rules is followed rather than positions,
+// which reordering can change later. It returns sql.ErrNoRows when the material is not in the course and
+// ErrReleaseMaterial when the required material is not in the course or would close a cycle.
+func (r *CourseRepository) SetMaterialRelease(material *model.LearningMaterial) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Rules must not change between the cycle check and the update
+	if err := lockCourseContent(tx, material.CourseID); err != nil {
+		return err
+	}
+
+	if material.ReleaseAfterMaterialID != nil {
+		var found, cycle bool
+		query := `
+			WITH RECURSIVE chain AS (
+				SELECT id, release_after_material_id FROM learning_materials WHERE id = $3 AND course_id = $2
+				UNION
+				SELECT m.id, m.release_after_material_id FROM learning_materials m JOIN chain c ON m.id = c.release_after_material_id
+			)
+			SELECT EXISTS(SELECT 1 FROM chain), EXISTS(SELECT 1 FROM chain WHERE id = $1)`
+		if err := tx.QueryRow(query, material.ID, material.CourseID, *material.ReleaseAfterMaterialID).Scan(&found, &cycle); err != nil {
+			return err
+		}
+		if !found || cycle {
+			return ErrReleaseMaterial
+		}
+	}
+
+	query := `
+		UPDATE learning_materials
+		SET release_at = $1, release_after_days = $2, release_after_material_id = $3, updated_at = NOW()
+		WHERE id = $4 AND course_id = $5`
+	result, err := tx.Exec(query, material.ReleaseAt, material.ReleaseAfterDays, material.ReleaseAfterMaterialID,
+		material.ID, material.CourseID)
+	if err != nil {
+		log.Printf("Error setting material release: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+func TestLockMaterials(t *testing.T) {
+	enrolledAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(24*time.Hour)
+	three, seven := 3, 7
+	intro, quiz := "m-1", "m-2"
+
+	materials := []model.LearningMaterial{
+		{ID: intro, TextContent: "Welcome"},
+		{ID: quiz, TextContent: "Quiz", ReleaseAt: &past},
+		{ID: "m-3", VideoURL: "https://example.com/v", ReleaseAt: &future},
+		{ID: "m-4", TextContent: "Week 1", ReleaseAfterDays: &three},
+		{ID: "m-5", FileURL: "/uploads/materials/week2.pdf", ReleaseAfterDays: &seven},
+		{ID: "m-6", TextContent: "After intro", ReleaseAfterMaterialID: &intro},
+		{ID: "m-7", TextContent: "After quiz", ReleaseAfterMaterialID: &quiz},
+	}
+	lockMaterials(materials, enrolledAt, now, map[string]bool{intro: true})
+
+	open := map[string]bool{"m-1": true, "m-2": true, "m-4": true, "m-6": true}
+	for _, m := range materials {
+		if m.Locked == open[m.ID] {
+			t.Errorf("material %s: expected locked=%v", m.ID, !open[m.ID])
+		}
+		if m.Locked && (m.TextContent != "" || m.VideoURL != "" || m.FileURL != "") {
+			t.Errorf("material %s: expected a locked material to come without content", m.ID)
+		}
+	}
+
+	if materials[2].UnlocksAt == nil || !materials[2].UnlocksAt.Equal(future) {
+		t.Errorf("expected m-3 to unlock at its release date, got %v", materials[2].UnlocksAt)
+	}
+	if want := enrolledAt.AddDate(0, 0, 7); materials[4].UnlocksAt == nil || !materials[4].UnlocksAt.Equal(want) {
+		t.Errorf("expected m-5 to unlock 7 days after enrolling, got %v", materials[4].UnlocksAt)
+	}
+	if materials[6].UnlocksAt != nil {
+		t.Errorf("expected m-7 to wait for a completion without an unlock time, got %v", materials[6].UnlocksAt)
+	}
+}
+
+func TestSetMaterialReleaseRejectsCycle(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	// m-1 would wait for m-3, which already waits for m-1 through m-2
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM courses WHERE id = $1 FOR UPDATE`)).
+		WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("course-1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`WITH RECURSIVE chain AS`)).
+		WithArgs("m-1", "course-1", "m-3").
+		WillReturnRows(sqlmock.NewRows([]string{"found", "cycle"}).AddRow(true, true))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	required := "m-3"
+	err = repo.SetMaterialRelease(&model.LearningMaterial{ID: "m-1", CourseID: "course-1", ReleaseAfterMaterialID: &required})
+
+	// Check the result (Assert)
+	if err != ErrReleaseMaterial {
+		t.Fatalf("expected ErrReleaseMaterial, got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -2,11 +2,48 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 
+	"github.com/dimasrizkyfebrian/coursify/internal/markdown"
 	"github.com/dimasrizkyfebrian/coursify/internal/model"
 )
 
+var (
+    // ErrPrerequisiteCycle is returned when a prerequisite would make a course depend on itself
+    ErrPrerequisiteCycle = errors.New("prerequisite would create a cycle")
+    ErrAlreadyEnrolled   = errors.New("student is already enrolled in this course")
+    ErrAlreadyWaitlisted = errors.New("student is already on the waitlist of this course")
+    // ErrWaitlistMismatch is returned when a reorder does not list every waiting student exactly once
+    ErrWaitlistMismatch = errors.New("waitlist order must list every waiting student exactly once")
+    ErrCohortRequired   = errors.New("this course runs in cohorts, choose a cohort to enroll in")
+    ErrCohortNotFound   = errors.New("cohort not found in this course")
+    ErrEnrollmentClosed = errors.New("enrollment in this cohort is not open")
+    // ErrInvalidAccessCode is returned for unknown, expired or used up access codes
+    ErrInvalidAccessCode = errors.New("invalid or expired access code")
+    // ErrSectionNesting is returned when a section would be nested more than one level deep
+    ErrSectionNesting = errors.New("sections can only be nested one level deep")
+    // ErrSectionOrderMismatch is returned when a reorder does not list every section of the level exactly once
+    ErrSectionOrderMismatch = errors.New("section order must list every section of the level exactly once")
+    // ErrMaterialOrderMismatch is returned when a reorder does not list every material of the course exactly once
+    ErrMaterialOrderMismatch = errors.New("material order must list every material of the course exactly once")
+    ErrMaterialMoveSelf      = errors.New("a material cannot be moved next to itself")
+    // ErrMaterialLocked is returned when a student acts on a material that is not released to them yet
+    ErrMaterialLocked = errors.New("this material is not available yet")
+    // ErrReleaseMaterial is returned when a material would wait for a material outside its course, or for one that waits for it
+    ErrReleaseMaterial = errors.New("a material can only wait for the completion of another material of the same course that does not wait for it")
+)
+
+// EnrollmentModeError is returned when a course does not accept the attempted way of enrolling
+type EnrollmentModeError struct {
+    Mode string // The course's enrollment mode
+}
+
+func (e *EnrollmentModeError) Error() string {
+    return fmt.Sprintf("course enrollment mode is %q", e.Mode)
+}
+
 type CourseRepository struct {
     DB *sql.DB
 }
@@ -17,58 +54,90 @@ func NewCourseRepository(db *sql.DB) *CourseRepository {
 
 // CreateCourse method
 func (r *CourseRepository) CreateCourse(course *model.Course) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
     query := `INSERT INTO courses (title, description, instructor_id) 
                VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`
 
-    err := r.DB.QueryRow(query, course.Title, course.Description, course.InstructorID).Scan(&course.ID, &course.CreatedAt, &course.UpdatedAt)
+    err = tx.QueryRow(query, course.Title, course.Description, course.InstructorID).Scan(&course.ID, &course.CreatedAt, &course.UpdatedAt)
     if err != nil {
         log.Printf("Error creating course: %v", err)
         return err
     }
 
-    return nil
+    // The creating instructor becomes the owner in course_staff
+    staffQuery := `INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, 'owner')`
+    if _, err := tx.Exec(staffQuery, course.ID, course.InstructorID); err != nil {
+        log.Printf("Error adding course owner: %v", err)
+        return err
+    }
+
+    return tx.Commit()
 }
 
-// GetCourseByInstructorId method
-func (r *CourseRepository) GetCoursesByInstructorID(instructorID string) ([]model.Course, error) {
-    query := `SELECT id, instructor_id, title, description, cover_image_url, created_at, updated_at
-               FROM courses WHERE instructor_id = $1 ORDER BY created_at DESC`
+// courseListSpec describes how course lists are paginated, sorted and filtered
+var courseListSpec = listSpec{
+    selectClause:  `SELECT id, instructor_id, title, description, cover_image_url, is_draft, is_template, capacity, enrollment_mode, price_cents, currency, rating_average, rating_count, created_at, updated_at`,
+    fromClause:    `FROM courses`,
+    idColumn:      "id",
+    createdColumn: "created_at",
+    sortColumns: map[string]sortColumn{
+        "created_at": {expr: "created_at", cast: "timestamptz"},
+        "updated_at": {expr: "updated_at", cast: "timestamptz"},
+        "title":      {expr: "title", cast: "text"},
+        "rating":     {expr: "rating_average", cast: "numeric"},
+    },
+    defaultSort: "created_at",
+    defaultDesc: true,
+}
 
-    rows, err := r.DB.Query(query, instructorID)
-    if err != nil {
-        return nil, err
-    }
-    defer rows.Close()
+// scanCourseRow scans a course list row followed by its cursor columns
+func scanCourseRow(rows *sql.Rows, cursor *pageCursor) (model.Course, error) {
+    var course model.Course
+    err := rows.Scan(
+        &course.ID,
+        &course.InstructorID,
+        &course.Title,
+        &course.Description,
+        &course.CoverImageURL,
+        &course.IsDraft,
+        &course.IsTemplate,
+        &course.Capacity,
+        &course.EnrollmentMode,
+        &course.PriceCents,
+        &course.Currency,
+        &course.RatingAverage,
+        &course.RatingCount,
+        &course.CreatedAt,
+        &course.UpdatedAt,
+        &cursor.Value,
+        &cursor.ID,
+    )
+    return course, err
+}
 
-    var courses []model.Course
-    for rows.Next() {
-        var course model.Course
-        if err := rows.Scan(
-            &course.ID,
-            &course.InstructorID,
-            &course.Title,
-            &course.Description,
-            &course.CoverImageURL,
-            &course.CreatedAt,
-            &course.UpdatedAt,
-        ); err != nil {
-            return nil, err
-        }
-        courses = append(courses, course)
-    }
+// GetCourseByInstructorId method
+func (r *CourseRepository) GetCoursesByInstructorID(instructorID string, params model.ListParams) ([]model.Course, *model.PageInfo, error) {
+    var q listQuery
+    q.where("id IN (SELECT course_id FROM course_staff WHERE user_id = $%d)", instructorID)
+    q.applyCommonFilters(courseListSpec, params)
 
-    return courses, nil
+    return queryPage(r.DB, courseListSpec, params, q, scanCourseRow)
 }
 
 // GetCourseByID method
 func (r *CourseRepository) GetCourseByID(courseID string) (*model.Course, error) {
     var course model.Course
-    query := `SELECT id, instructor_id, title, description, cover_image_url, created_at, updated_at
+    query := `SELECT id, instructor_id, title, description, cover_image_url, is_draft, is_template, capacity, enrollment_mode, price_cents, currency, rating_average, rating_count, created_at, updated_at
                FROM courses WHERE id = $1`
 
     err := r.DB.QueryRow(query, courseID).Scan(
         &course.ID, &course.InstructorID, &course.Title, &course.Description,
-        &course.CoverImageURL, &course.CreatedAt, &course.UpdatedAt,
+        &course.CoverImageURL, &course.IsDraft, &course.IsTemplate, &course.Capacity, &course.EnrollmentMode, &course.PriceCents, &course.Currency, &course.RatingAverage, &course.RatingCount, &course.CreatedAt, &course.UpdatedAt,
     )
     if err != nil {
         if err == sql.ErrNoRows {
@@ -93,28 +162,36 @@ func (r *CourseRepository) UpdateCourse(course *model.Course) error {
 
 // AddMaterialToCourse method
 func (r *CourseRepository) AddMaterialToCourse(material *model.LearningMaterial) error {
-    // Get the last position to determine the position of new material
-    var lastPosition int
-    posQuery := `SELECT COALESCE(MAX(position), 0) FROM learning_materials WHERE course_id = $1`
-    err := r.DB.QueryRow(posQuery, material.CourseID).Scan(&lastPosition)
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if material.TextHTML, err = markdown.Render(material.TextContent); err != nil {
+        return err
+    }
+
+    // New material goes after the last one of the course
+    material.Position, err = nextMaterialPosition(tx, material.CourseID)
     if err != nil {
-        log.Printf("Error getting last material position: %v", err)
         return err
     }
-    material.Position = lastPosition + 1
 
     // Insert new material
     insertQuery := `
-        INSERT INTO learning_materials (course_id, title, content_type, text_content, position)
-        VALUES ($1, $2, $3, $4, $5)
+        INSERT INTO learning_materials (course_id, title, content_type, text_content, text_html, video_url, position)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7)
         RETURNING id, created_at, updated_at
     `
-    err = r.DB.QueryRow(
+    err = tx.QueryRow(
         insertQuery,
         material.CourseID,
         material.Title,
         material.ContentType,
         material.TextContent,
+        material.TextHTML,
+        material.VideoURL,
         material.Position,
     ).Scan(&material.ID, &material.CreatedAt, &material.UpdatedAt)
 
@@ -123,13 +200,60 @@ func (r *CourseRepository) AddMaterialToCourse(material *model.LearningMaterial)
         return err
     }
 
-    return nil
+    return tx.Commit()
+}
+
+// materialColumns lists the learning_materials columns read by scanMaterial
+const materialColumns = `id, course_id, section_id, title, content_type, text_content, text_html, video_url, file_url, duration_seconds, file_size_bytes,
+    position, is_preview, release_at, release_after_days, release_after_material_id, created_at, updated_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+    Scan(dest ...any) error
+}
+
+// scanMaterial scans one learning_materials row selected with materialColumns
+func scanMaterial(row rowScanner) (model.LearningMaterial, error) {
+    var material model.LearningMaterial
+    // Use sql.NullString for fields that can be NULL
+    var textContent, textHTML, videoURL, fileURL sql.NullString
+
+    if err := row.Scan(
+        &material.ID,
+        &material.CourseID,
+        &material.SectionID,
+        &material.Title,
+        &material.ContentType,
+        &textContent,
+        &textHTML,
+        &videoURL,
+        &fileURL,
+        &material.DurationSeconds,
+        &material.FileSizeBytes,
+        &material.Position,
+        &material.IsPreview,
+        &material.ReleaseAt,
+        &material.ReleaseAfterDays,
+        &material.ReleaseAfterMaterialID,
+        &material.CreatedAt,
+        &material.UpdatedAt,
+    ); err != nil {
+        return material, err
+    }
+
+    // Conversion from sql.NullString to a regular string
+    material.TextContent = textContent.String
+    material.TextHTML = textHTML.String
+    material.VideoURL = videoURL.String
+    material.FileURL = fileURL.String
+
+    return material, nil
 }
 
 // GetMaterialsByCourseID method
 func (r *CourseRepository) GetMaterialsByCourseID(courseID string) ([]model.LearningMaterial, error) {
     query := `
-        SELECT id, course_id, title, content_type, text_content, video_url, file_url, position, created_at, updated_at
+        SELECT ` + materialColumns + `
         FROM learning_materials 
         WHERE course_id = $1 
         ORDER BY position ASC
@@ -142,51 +266,142 @@ func (r *CourseRepository) GetMaterialsByCourseID(courseID string) ([]model.Lear
 
     var materials []model.LearningMaterial
     for rows.Next() {
-        var material model.LearningMaterial
-        // Use sql.NullString for fields that can be NULL
-        var textContent, videoURL, fileURL sql.NullString
-
-        if err := rows.Scan(
-            &material.ID,
-            &material.CourseID,
-            &material.Title,
-            &material.ContentType,
-            &textContent,
-            &videoURL,
-            &fileURL,
-            &material.Position,
-            &material.CreatedAt,
-            &material.UpdatedAt,
-        ); err != nil {
+        material, err := scanMaterial(rows)
+        if err != nil {
             return nil, err
         }
+        materials = append(materials, material)
+    }
+    return materials, nil
+}
+
+// GetCourseDetailByID method
+func (r *CourseRepository) GetCourseDetailByID(courseID string) (*model.CourseDetail, error) {
+    var detail model.CourseDetail
+    query := `SELECT c.id, c.instructor_id, c.title, c.description, c.cover_image_url, c.is_draft, c.is_template, c.capacity, c.enrollment_mode, c.price_cents, c.currency, c.rating_average, c.rating_count, c.created_at, c.updated_at, u.full_name,
+                      (SELECT COUNT(*) FROM enrollments e WHERE e.course_id = c.id)
+               FROM courses c JOIN users u ON u.id = c.instructor_id
+               WHERE c.id = $1 AND c.is_draft = FALSE`
 
-        // Conversion from sql.NullString to a regular string
-        if textContent.Valid {
-            material.TextContent = textContent.String
+    var enrolled int
+    err := r.DB.QueryRow(query, courseID).Scan(
+        &detail.ID, &detail.InstructorID, &detail.Title, &detail.Description,
+        &detail.CoverImageURL, &detail.IsDraft, &detail.IsTemplate, &detail.Capacity, &detail.EnrollmentMode, &detail.PriceCents, &detail.Currency, &detail.RatingAverage, &detail.RatingCount, &detail.CreatedAt, &detail.UpdatedAt, &detail.InstructorName,
+        &enrolled,
+    )
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, nil
         }
-        if videoURL.Valid {
-            material.VideoURL = videoURL.String
+        return nil, err
+    }
+
+    // Courses without a capacity have no seat count
+    if detail.Capacity != nil {
+        seatsLeft := max(*detail.Capacity-enrolled, 0)
+        detail.SeatsLeft = &seatsLeft
+    }
+
+    prerequisites, err := r.GetPrerequisites(courseID)
+    if err != nil {
+        return nil, err
+    }
+    detail.Prerequisites = prerequisites
+
+    // Attach the syllabus without any material content
+    outline, err := r.GetMaterialOutlineByCourseID(courseID)
+    if err != nil {
+        return nil, err
+    }
+    detail.Outline = outline
+
+    return &detail, nil
+}
+
+// publicPreview is the condition for a material to be open as a free preview. A material with a release
+// rule is only previewed once it is released to everyone, so anonymous visitors never see more than students.
+const publicPreview = `is_preview = TRUE AND release_after_days IS NULL AND release_after_material_id IS NULL
+                 AND (release_at IS NULL OR release_at <= NOW())`
+
+// GetMaterialOutlineByCourseID method
+func (r *CourseRepository) GetMaterialOutlineByCourseID(courseID string) ([]model.MaterialOutline, error) {
+    query := `SELECT id, title, content_type, position, (` + publicPreview + `)
+               FROM learning_materials WHERE course_id = $1 ORDER BY position ASC`
+
+    rows, err := r.DB.Query(query, courseID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    outline := []model.MaterialOutline{}
+    for rows.Next() {
+        var item model.MaterialOutline
+        if err := rows.Scan(&item.ID, &item.Title, &item.ContentType, &item.Position, &item.IsPreview); err != nil {
+            return nil, err
         }
-        if fileURL.Valid {
-            material.FileURL = fileURL.String
+        outline = append(outline, item)
+    }
+    return outline, nil
+}
+
+// GetPreviewMaterial method
+func (r *CourseRepository) GetPreviewMaterial(courseID, materialID string) (*model.LearningMaterial, error) {
+    // Like the course details, previews of draft courses are not public
+    query := `SELECT ` + materialColumns + ` FROM learning_materials
+               WHERE id = $1 AND course_id = $2 AND ` + publicPreview + `
+                 AND course_id IN (SELECT id FROM courses WHERE is_draft = FALSE)`
+
+    material, err := scanMaterial(r.DB.QueryRow(query, materialID, courseID))
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, nil
         }
+        return nil, err
+    }
+    return &material, nil
+}
 
-        materials = append(materials, material)
+// SetMaterialPreview method
+func (r *CourseRepository) SetMaterialPreview(courseID, materialID string, isPreview bool) error {
+    query := `UPDATE learning_materials SET is_preview = $1, updated_at = NOW() WHERE id = $2 AND course_id = $3`
+
+    // Execute the update query
+    result, err := r.DB.Exec(query, isPreview, materialID, courseID)
+    if err != nil {
+        log.Printf("Error updating material preview flag: %v", err)
+        return err
     }
-    return materials, nil
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return sql.ErrNoRows // Indicates that the material was not found or does not match
+    }
+
+    return nil
 }
 
 // UpdateMaterial method
 func (r *CourseRepository) UpdateMaterial(material *model.LearningMaterial) error {
+	textHTML, err := markdown.Render(material.TextContent)
+	if err != nil {
+		return err
+	}
+	material.TextHTML = textHTML
+
 	query := `
 		UPDATE learning_materials 
-		SET title = $1, text_content = $2, video_url = $3, updated_at = NOW()
-		WHERE id = $4 AND course_id = $5
+		SET title = $1, text_content = $2, text_html = NULLIF($3, ''), video_url = $4, updated_at = NOW()
+		WHERE id = $5 AND course_id = $6
 	`
 
     // Execute the update query
-	result, err := r.DB.Exec(query, material.Title, material.TextContent, material.VideoURL, material.ID, material.CourseID)
+	result, err := r.DB.Exec(query, material.Title, material.TextContent, material.TextHTML, material.VideoURL, material.ID, material.CourseID)
 	if err != nil {
 		log.Printf("Error updating material: %v", err)
 		return err
@@ -205,165 +420,1491 @@ func (r *CourseRepository) UpdateMaterial(material *model.LearningMaterial) erro
 	return nil
 }
 
+// RenderMissingTextHTML renders the HTML of text materials written before it was stored, returning how many were rendered
+func (r *CourseRepository) RenderMissingTextHTML() (int, error) {
+	rows, err := r.DB.Query(`SELECT id, text_content FROM learning_materials WHERE text_content <> '' AND text_html IS NULL`)
+	if err != nil {
+		return 0, err
+	}
+	pending := map[string]string{}
+	for rows.Next() {
+		var id, textContent string
+		if err := rows.Scan(&id, &textContent); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending[id] = textContent
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for id, textContent := range pending {
+		textHTML, err := markdown.Render(textContent)
+		if err != nil {
+			return 0, err
+		}
+		// Skip materials edited in the meantime, which were rendered by their update
+		query := `UPDATE learning_materials SET text_html = $1 WHERE id = $2 AND text_content = $3 AND text_html IS NULL`
+		if _, err := r.DB.Exec(query, textHTML, id, textContent); err != nil {
+			log.Printf("Error rendering material text: %v", err)
+			return 0, err
+		}
+	}
+	return len(pending), nil
+}
+
 // DeleteMaterial method
 func (r *CourseRepository) DeleteMaterial(courseID, materialID string) error {
-	query := `DELETE FROM learning_materials WHERE id = $1 AND course_id = $2`
-
-    // Execute the delete query
-	result, err := r.DB.Exec(query, materialID, courseID)
+	tx, err := r.DB.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-    // Check if any rows were affected
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
+	if err := lockCourseContent(tx, courseID); err != nil {
 		return err
 	}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows // Indicates that the material was not found or does not match
+	var position int
+	query := `DELETE FROM learning_materials WHERE id = $1 AND course_id = $2 RETURNING position`
+	if err := tx.QueryRow(query, materialID, courseID).Scan(&position); err != nil {
+		return err // sql.ErrNoRows indicates that the material was not found or does not match
 	}
 
-	return nil
+    // Close the gap so positions stay dense
+	shiftQuery := `UPDATE learning_materials SET position = position - 1 WHERE course_id = $1 AND position > $2`
+	if _, err := tx.Exec(shiftQuery, courseID, position); err != nil {
+		log.Printf("Error closing material position gap: %v", err)
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetAllCourses method
-func (r *CourseRepository) GetAllCourses() ([]model.Course, error) {
-    query := `SELECT id, instructor_id, title, description, cover_image_url, created_at, updated_at
-               FROM courses ORDER BY created_at DESC`
+func (r *CourseRepository) GetAllCourses(params model.ListParams) ([]model.Course, *model.PageInfo, error) {
+    var q listQuery
+    q.where("is_draft = FALSE")
+    q.applyCommonFilters(courseListSpec, params)
+
+    return queryPage(r.DB, courseListSpec, params, q, scanCourseRow)
+}
+
+// courseSeats is the enrollment state of a course row locked by lockCourseSeats
+type courseSeats struct {
+    capacity   sql.NullInt64
+    isDraft    bool
+    mode       string
+    priceCents int
+    currency   string
+}
+
+// lockCourseSeats locks the course row so seat counts cannot change until the transaction ends
+func lockCourseSeats(tx *sql.Tx, courseID string) (courseSeats, error) {
+    var seats courseSeats
+    query := `SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`
+    err := tx.QueryRow(query, courseID).Scan(&seats.capacity, &seats.isDraft, &seats.mode, &seats.priceCents, &seats.currency)
+    return seats, err
+}
 
-    rows, err := r.DB.Query(query)
+// lockEnrollableCourse locks a published course and checks that it uses the given enrollment mode
+func lockEnrollableCourse(tx *sql.Tx, courseID, mode string) (courseSeats, error) {
+    seats, err := lockCourseSeats(tx, courseID)
     if err != nil {
-        return nil, err
+        return seats, err
     }
-    defer rows.Close()
+    if seats.isDraft {
+        return seats, sql.ErrNoRows // Draft courses cannot be enrolled in
+    }
+    if seats.mode != mode {
+        return seats, &EnrollmentModeError{Mode: seats.mode}
+    }
+    return seats, nil
+}
 
-    var courses []model.Course
-    for rows.Next() {
-        var course model.Course
-        if err := rows.Scan(
-            &course.ID, &course.InstructorID, &course.Title, &course.Description,
-            &course.CoverImageURL, &course.CreatedAt, &course.UpdatedAt,
-        ); err != nil {
-            return nil, err
+// fillOpenSeats promotes waitlisted students in position order until the course is full.
+// The course row must already be locked with lockCourseSeats. Nobody is promoted into a paid course,
+// students still waiting there buy a seat through checkout.
+func fillOpenSeats(tx *sql.Tx, courseID string, seats courseSeats) error {
+    if seats.priceCents > 0 {
+        return nil
+    }
+    capacity := seats.capacity
+
+    // A NULL limit promotes everyone when the course has no capacity
+    var free sql.NullInt64
+    if capacity.Valid {
+        var taken int64
+        if err := tx.QueryRow(`SELECT COUNT(*) FROM enrollments WHERE course_id = $1`, courseID).Scan(&taken); err != nil {
+            return err
+        }
+        if taken >= capacity.Int64 {
+            return nil
         }
-        courses = append(courses, course)
+        free = sql.NullInt64{Int64: capacity.Int64 - taken, Valid: true}
     }
 
-    return courses, nil
+    query := `
+        WITH promoted AS (
+            DELETE FROM course_waitlist
+            WHERE course_id = $1 AND user_id IN (
+                SELECT user_id FROM course_waitlist WHERE course_id = $1 ORDER BY position ASC LIMIT $2
+            )
+            RETURNING user_id, course_id, cohort_id
+        ), enrolled AS (
+            INSERT INTO enrollments (user_id, course_id, cohort_id) SELECT user_id, course_id, cohort_id FROM promoted
+            RETURNING user_id, course_id, cohort_id
+        )
+        INSERT INTO enrollment_history (course_id, user_id, cohort_id, action)
+        SELECT course_id, user_id, cohort_id, 'promoted' FROM enrolled
+    `
+    if _, err := tx.Exec(query, courseID, free); err != nil {
+        log.Printf("Error promoting waitlisted students: %v", err)
+        return err
+    }
+    return nil
 }
 
-// EnrollStudent method
-func (r *CourseRepository) EnrollStudent(studentID, courseID string) error {
-    query := `INSERT INTO enrollments (user_id, course_id) VALUES ($1, $2)`
-
-    // Execute the insert query
-    _, err := r.DB.Exec(query, studentID, courseID)
-    if err != nil {
-        log.Printf("Error enrolling student: %v", err)
+// recordEnrollment adds an entry to the enrollment history, actorID is the user who made the change
+func recordEnrollment(tx *sql.Tx, courseID, userID, cohortID, action, actorID string) error {
+    query := `INSERT INTO enrollment_history (course_id, user_id, cohort_id, action, actor_id)
+               VALUES ($1, $2, NULLIF($3, '')::uuid, $4, NULLIF($5, '')::uuid)`
+    if _, err := tx.Exec(query, courseID, userID, cohortID, action, actorID); err != nil {
+        log.Printf("Error recording enrollment history: %v", err)
         return err
     }
+    return nil
+}
+
+// checkCohortEnrollment requires a cohort of the course when it has cohorts,
+// and with requireOpen also that the cohort's enrollment window is open
+func checkCohortEnrollment(tx *sql.Tx, courseID, cohortID string, requireOpen bool) error {
+    if cohortID == "" {
+        var hasCohorts bool
+        if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM course_cohorts WHERE course_id = $1)`, courseID).Scan(&hasCohorts); err != nil {
+            return err
+        }
+        if hasCohorts {
+            return ErrCohortRequired
+        }
+        return nil
+    }
 
+    var open bool
+    query := `SELECT NOW() >= enrollment_opens_at AND NOW() < enrollment_closes_at FROM course_cohorts WHERE id = $1 AND course_id = $2`
+    if err := tx.QueryRow(query, cohortID, courseID).Scan(&open); err != nil {
+        if err == sql.ErrNoRows {
+            return ErrCohortNotFound
+        }
+        return err
+    }
+    if requireOpen && !open {
+        return ErrEnrollmentClosed
+    }
     return nil
 }
 
-// GetEnrolledCoursesByStudentID method
-func (r *CourseRepository) GetEnrolledCoursesByStudentID(studentID string) ([]model.Course, error) {
-    query := `
-        SELECT c.id, c.instructor_id, c.title, c.description, c.cover_image_url, c.created_at, c.updated_at
-        FROM courses c
-        JOIN enrollments e ON c.id = e.course_id
-        WHERE e.user_id = $1
-        ORDER BY e.enrollment_date DESC
-    `
+// enrollInTx enrolls the student into a course locked with lockCourseSeats, adding them to the end
+// of the waitlist when it is full, and records action (or 'waitlisted') by actorID in the history.
+// With requireOpen the cohort's enrollment window must be open. Paid courses fail with ErrPaymentRequired.
+// It returns the waitlist position, which is 0 when the student was enrolled.
+func enrollInTx(tx *sql.Tx, seats courseSeats, studentID, courseID, cohortID string, requireOpen bool, action, actorID string) (int, error) {
+    // Only a confirmed payment enrolls into a paid course, whichever way the student asked to join
+    if seats.priceCents > 0 {
+        return 0, ErrPaymentRequired
+    }
 
-    rows, err := r.DB.Query(query, studentID)
-    if err != nil {
-        return nil, err
+    var enrolled, waitlisted bool
+    statusQuery := `SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2),
+                           EXISTS(SELECT 1 FROM course_waitlist WHERE user_id = $1 AND course_id = $2)`
+    if err := tx.QueryRow(statusQuery, studentID, courseID).Scan(&enrolled, &waitlisted); err != nil {
+        return 0, err
+    }
+    if enrolled {
+        return 0, ErrAlreadyEnrolled
+    }
+    if waitlisted {
+        return 0, ErrAlreadyWaitlisted
     }
-    defer rows.Close()
 
-    var courses []model.Course
-    for rows.Next() {
-        var course model.Course
-        if err := rows.Scan(
-            &course.ID, &course.InstructorID, &course.Title, &course.Description,
-            &course.CoverImageURL, &course.CreatedAt, &course.UpdatedAt,
-        ); err != nil {
-            return nil, err
+    if err := checkCohortEnrollment(tx, courseID, cohortID, requireOpen); err != nil {
+        return 0, err
+    }
+
+    // Students already waiting get any free seat before a newcomer
+    if err := fillOpenSeats(tx, courseID, seats); err != nil {
+        return 0, err
+    }
+
+    var taken int64
+    if seats.capacity.Valid {
+        if err := tx.QueryRow(`SELECT COUNT(*) FROM enrollments WHERE course_id = $1`, courseID).Scan(&taken); err != nil {
+            return 0, err
+        }
+    }
+
+    position := 0
+    if !seats.capacity.Valid || taken < seats.capacity.Int64 {
+        query := `INSERT INTO enrollments (user_id, course_id, cohort_id) VALUES ($1, $2, NULLIF($3, '')::uuid)`
+        if _, err := tx.Exec(query, studentID, courseID, cohortID); err != nil {
+            log.Printf("Error enrolling student: %v", err)
+            return 0, err
+        }
+    } else {
+        action = model.EnrollmentActionWaitlisted
+        query := `INSERT INTO course_waitlist (course_id, user_id, cohort_id, position)
+                   SELECT $1, $2, NULLIF($3, '')::uuid, COALESCE(MAX(position), 0) + 1 FROM course_waitlist WHERE course_id = $1
+                   RETURNING position`
+        if err := tx.QueryRow(query, courseID, studentID, cohortID).Scan(&position); err != nil {
+            log.Printf("Error adding student to waitlist: %v", err)
+            return 0, err
         }
-        courses = append(courses, course)
     }
 
-    return courses, nil
+    if err := recordEnrollment(tx, courseID, studentID, cohortID, action, actorID); err != nil {
+        return 0, err
+    }
+
+    return position, nil
 }
 
-// IsStudentEnrolled method
-func (r *CourseRepository) IsStudentEnrolled(studentID, courseID string) (bool, error) {
-    var exists bool
-    query := `SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2)`
+// EnrollStudent enrolls the student into an open, free course, and into cohortID when the course runs in cohorts.
+// A full course adds the student to the end of the waitlist instead.
+// It returns the waitlist position, which is 0 when the student was enrolled.
+func (r *CourseRepository) EnrollStudent(studentID, courseID, cohortID string) (int, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return 0, err
+    }
+    defer tx.Rollback()
 
-    err := r.DB.QueryRow(query, studentID, courseID).Scan(&exists)
+    // Concurrent enrollments queue on the course row, so seats are counted one at a time
+    seats, err := lockEnrollableCourse(tx, courseID, model.EnrollmentModeOpen)
     if err != nil {
-        return false, err
+        return 0, err
+    }
+    position, err := enrollInTx(tx, seats, studentID, courseID, cohortID, true, model.EnrollmentActionEnrolled, studentID)
+    if err != nil {
+        return 0, err
     }
-    return exists, nil
-}
 
-// UpdateCourseCoverImage method
-func (r *CourseRepository) UpdateCourseCoverImage(courseID, imageURL string) error {
-    query := `UPDATE courses SET cover_image_url = $1, updated_at = NOW() WHERE id = $2`
+    return position, tx.Commit()
+}
 
-    // Execute the update query
-    result, err := r.DB.Exec(query, imageURL, courseID)
+// AddStudent enrolls a student on behalf of course staff. Staff may fill a course past its capacity
+// and enroll into a cohort whose enrollment window is closed; a waitlisted student leaves the waitlist.
+func (r *CourseRepository) AddStudent(actorID, studentID, courseID, cohortID string) error {
+    tx, err := r.DB.Begin()
     if err != nil {
         return err
     }
+    defer tx.Rollback()
 
-    // Check if any rows were affected
-    rowsAffected, err := result.RowsAffected()
+    if _, err := lockCourseSeats(tx, courseID); err != nil {
+        return err
+    }
+
+    enrolled, err := isEnrolledTx(tx, studentID, courseID)
     if err != nil {
         return err
     }
+    if enrolled {
+        return ErrAlreadyEnrolled
+    }
 
-    if rowsAffected == 0 {
-        return sql.ErrNoRows // Indicates that the course was not found or does not match
+    if err := checkCohortEnrollment(tx, courseID, cohortID, false); err != nil {
+        return err
     }
 
-    return nil
+    if _, err := tx.Exec(`DELETE FROM course_waitlist WHERE course_id = $1 AND user_id = $2`, courseID, studentID); err != nil {
+        return err
+    }
+
+    query := `INSERT INTO enrollments (user_id, course_id, cohort_id) VALUES ($1, $2, NULLIF($3, '')::uuid)`
+    if _, err := tx.Exec(query, studentID, courseID, cohortID); err != nil {
+        log.Printf("Error adding student: %v", err)
+        return err
+    }
+
+    if err := recordEnrollment(tx, courseID, studentID, cohortID, model.EnrollmentActionAdded, actorID); err != nil {
+        return err
+    }
+
+    return tx.Commit()
 }
 
-// AddFileMaterialToCourse method
-func (r *CourseRepository) AddFileMaterialToCourse(material *model.LearningMaterial) error {
-    // Get the latest position for new material
-    var lastPosition int
-    posQuery := `SELECT COALESCE(MAX(position), 0) FROM learning_materials WHERE course_id = $1`
-    err := r.DB.QueryRow(posQuery, material.CourseID).Scan(&lastPosition)
+func isEnrolledTx(tx *sql.Tx, studentID, courseID string) (bool, error) {
+    var exists bool
+    query := `SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2)`
+    err := tx.QueryRow(query, studentID, courseID).Scan(&exists)
+    return exists, err
+}
+
+// Unenroll removes the student from the course, or from its waitlist when not enrolled, and
+// records action in the history. The freed seat goes to the next waitlisted student.
+// It returns sql.ErrNoRows when the student is neither enrolled nor waitlisted.
+func (r *CourseRepository) Unenroll(actorID, studentID, courseID, action string) error {
+    tx, err := r.DB.Begin()
     if err != nil {
-        log.Printf("Error getting last material position: %v", err)
         return err
     }
-    material.Position = lastPosition + 1
+    defer tx.Rollback()
 
-    // Add new material with the type 'pdf' and file_url
-    insertQuery := `
-        INSERT INTO learning_materials (course_id, title, content_type, file_url, position)
-        VALUES ($1, $2, 'pdf', $3, $4)
-        RETURNING id, created_at, updated_at
-    `
-    err = r.DB.QueryRow(
-        insertQuery,
-        material.CourseID,
-        material.Title,
-        material.FileURL,
-        material.Position,
-    ).Scan(&material.ID, &material.CreatedAt, &material.UpdatedAt)
+    seats, err := lockCourseSeats(tx, courseID)
+    if err != nil {
+        return err
+    }
 
+    // Material completions are kept, so progress comes back if the student enrolls again
+    var cohortID string
+    query := `DELETE FROM enrollments WHERE user_id = $1 AND course_id = $2 RETURNING COALESCE(cohort_id::text, '')`
+    err = tx.QueryRow(query, studentID, courseID).Scan(&cohortID)
+    if err == sql.ErrNoRows {
+        waitlistQuery := `DELETE FROM course_waitlist WHERE user_id = $1 AND course_id = $2 RETURNING COALESCE(cohort_id::text, '')`
+        err = tx.QueryRow(waitlistQuery, studentID, courseID).Scan(&cohortID)
+    }
     if err != nil {
-        log.Printf("Error adding file material to course: %v", err)
+        return err // sql.ErrNoRows when the student was neither enrolled nor waitlisted
+    }
+
+    if err := recordEnrollment(tx, courseID, studentID, cohortID, action, actorID); err != nil {
         return err
     }
 
-    return nil
-}
\ No newline at end of file
+    if err := fillOpenSeats(tx, courseID, seats); err != nil {
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// rosterListSpec describes how a course roster is paginated, sorted and filtered
+var rosterListSpec = listSpec{
+    selectClause:  `SELECT e.user_id, u.full_name, u.email, e.cohort_id, e.enrollment_date, e.completed_at`,
+    fromClause:    `FROM enrollments e JOIN users u ON u.id = e.user_id`,
+    idColumn:      "e.user_id",
+    createdColumn: "e.enrollment_date",
+    sortColumns: map[string]sortColumn{
+        "enrollment_date": {expr: "e.enrollment_date", cast: "timestamptz"},
+        "full_name":       {expr: "u.full_name", cast: "text"},
+    },
+    defaultSort: "enrollment_date",
+    defaultDesc: false,
+}
+
+// GetCourseRoster returns a page of enrolled students, optionally only those of one cohort
+func (r *CourseRepository) GetCourseRoster(courseID, cohortID string, params model.ListParams) ([]model.RosterEntry, *model.PageInfo, error) {
+    var q listQuery
+    q.where("e.course_id = $%d", courseID)
+    if cohortID != "" {
+        q.where("e.cohort_id = $%d", cohortID)
+    }
+    q.applyCommonFilters(rosterListSpec, params)
+
+    return queryPage(r.DB, rosterListSpec, params, q, func(rows *sql.Rows, cursor *pageCursor) (model.RosterEntry, error) {
+        var entry model.RosterEntry
+        err := rows.Scan(&entry.UserID, &entry.FullName, &entry.Email, &entry.CohortID, &entry.EnrollmentDate, &entry.CompletedAt,
+            &cursor.Value, &cursor.ID)
+        return entry, err
+    })
+}
+
+// enrollmentHistoryListSpec describes how the enrollment history is paginated, sorted and filtered
+var enrollmentHistoryListSpec = listSpec{
+    selectClause:  `SELECT h.id, h.user_id, u.full_name, h.cohort_id, h.action, h.actor_id, h.created_at`,
+    fromClause:    `FROM enrollment_history h JOIN users u ON u.id = h.user_id`,
+    idColumn:      "h.id",
+    createdColumn: "h.created_at",
+    sortColumns: map[string]sortColumn{
+        "created_at": {expr: "h.created_at", cast: "timestamptz"},
+    },
+    defaultSort: "created_at",
+    defaultDesc: true,
+}
+
+// GetEnrollmentHistory returns a page of the course's enrollment history, optionally only for one student
+func (r *CourseRepository) GetEnrollmentHistory(courseID, userID string, params model.ListParams) ([]model.EnrollmentEvent, *model.PageInfo, error) {
+    var q listQuery
+    q.where("h.course_id = $%d", courseID)
+    if userID != "" {
+        q.where("h.user_id = $%d", userID)
+    }
+    q.applyCommonFilters(enrollmentHistoryListSpec, params)
+
+    return queryPage(r.DB, enrollmentHistoryListSpec, params, q, func(rows *sql.Rows, cursor *pageCursor) (model.EnrollmentEvent, error) {
+        var event model.EnrollmentEvent
+        err := rows.Scan(&event.ID, &event.UserID, &event.FullName, &event.CohortID, &event.Action, &event.ActorID, &event.CreatedAt,
+            &cursor.Value, &cursor.ID)
+        return event, err
+    })
+}
+
+// SetCourseCapacity method
+func (r *CourseRepository) SetCourseCapacity(courseID string, capacity *int) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    seats, err := lockCourseSeats(tx, courseID)
+    if err != nil {
+        return err // sql.ErrNoRows when the course was not found
+    }
+
+    var newCapacity sql.NullInt64
+    if capacity != nil {
+        newCapacity = sql.NullInt64{Int64: int64(*capacity), Valid: true}
+    }
+
+    query := `UPDATE courses SET capacity = $1, updated_at = NOW() WHERE id = $2`
+    if _, err := tx.Exec(query, newCapacity, courseID); err != nil {
+        log.Printf("Error updating course capacity: %v", err)
+        return err
+    }
+
+    // Raising or removing the capacity frees seats for the waitlist.
+    // Lowering it below the enrollment count keeps everyone who is already enrolled.
+    seats.capacity = newCapacity
+    if err := fillOpenSeats(tx, courseID, seats); err != nil {
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// GetWaitlist method
+func (r *CourseRepository) GetWaitlist(courseID string) ([]model.WaitlistEntry, error) {
+    query := `
+        SELECT w.user_id, u.full_name, u.email, w.position, w.created_at
+        FROM course_waitlist w
+        JOIN users u ON u.id = w.user_id
+        WHERE w.course_id = $1
+        ORDER BY w.position ASC
+    `
+    rows, err := r.DB.Query(query, courseID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    waitlist := []model.WaitlistEntry{}
+    for rows.Next() {
+        var entry model.WaitlistEntry
+        if err := rows.Scan(&entry.UserID, &entry.FullName, &entry.Email, &entry.Position, &entry.CreatedAt); err != nil {
+            return nil, err
+        }
+        waitlist = append(waitlist, entry)
+    }
+    return waitlist, rows.Err()
+}
+
+// ReorderWaitlist sets the waitlist order to userIDs, which must list every waiting student exactly once
+func (r *CourseRepository) ReorderWaitlist(courseID string, userIDs []string) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    // Lock the course so no student joins or is promoted while the order changes
+    if _, err := lockCourseSeats(tx, courseID); err != nil {
+        return err
+    }
+
+    rows, err := tx.Query(`SELECT user_id FROM course_waitlist WHERE course_id = $1`, courseID)
+    if err != nil {
+        return err
+    }
+    waiting := make(map[string]bool)
+    for rows.Next() {
+        var userID string
+        if err := rows.Scan(&userID); err != nil {
+            rows.Close()
+            return err
+        }
+        waiting[userID] = true
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return err
+    }
+
+    if len(userIDs) != len(waiting) {
+        return ErrWaitlistMismatch
+    }
+    for _, userID := range userIDs {
+        if !waiting[userID] {
+            return ErrWaitlistMismatch
+        }
+        delete(waiting, userID) // A repeated user ID will not be found again
+    }
+
+    // The unique position constraint is deferred until commit, so positions can be swapped freely
+    query := `UPDATE course_waitlist SET position = $1 WHERE course_id = $2 AND user_id = $3`
+    for i, userID := range userIDs {
+        if _, err := tx.Exec(query, i+1, courseID, userID); err != nil {
+            log.Printf("Error reordering waitlist: %v", err)
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// enrolledCourseListSpec describes how a student's enrolled courses are paginated, sorted and filtered
+var enrolledCourseListSpec = listSpec{
+    selectClause:  `SELECT c.id, c.instructor_id, c.title, c.description, c.cover_image_url, c.is_draft, c.is_template, c.capacity, c.enrollment_mode, c.price_cents, c.currency, c.rating_average, c.rating_count, c.created_at, c.updated_at`,
+    fromClause:    `FROM courses c JOIN enrollments e ON c.id = e.course_id`,
+    idColumn:      "c.id",
+    createdColumn: "e.enrollment_date",
+    sortColumns: map[string]sortColumn{
+        "enrollment_date": {expr: "e.enrollment_date", cast: "timestamptz"},
+        "created_at":      {expr: "c.created_at", cast: "timestamptz"},
+        "title":           {expr: "c.title", cast: "text"},
+    },
+    defaultSort: "enrollment_date",
+    defaultDesc: true,
+}
+
+// GetEnrolledCoursesByStudentID method
+func (r *CourseRepository) GetEnrolledCoursesByStudentID(studentID string, params model.ListParams) ([]model.Course, *model.PageInfo, error) {
+    var q listQuery
+    q.where("e.user_id = $%d", studentID)
+    q.applyCommonFilters(enrolledCourseListSpec, params)
+
+    return queryPage(r.DB, enrolledCourseListSpec, params, q, scanCourseRow)
+}
+
+// IsStudentEnrolled method
+func (r *CourseRepository) IsStudentEnrolled(studentID, courseID string) (bool, error) {
+    var exists bool
+    query := `SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2)`
+
+    err := r.DB.QueryRow(query, studentID, courseID).Scan(&exists)
+    if err != nil {
+        return false, err
+    }
+    return exists, nil
+}
+
+// UpdateCourseCoverImage method
+func (r *CourseRepository) UpdateCourseCoverImage(courseID, imageURL string) error {
+    query := `UPDATE courses SET cover_image_url = $1, updated_at = NOW() WHERE id = $2`
+
+    // Execute the update query
+    result, err := r.DB.Exec(query, imageURL, courseID)
+    if err != nil {
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return sql.ErrNoRows // Indicates that the course was not found or does not match
+    }
+
+    return nil
+}
+
+// AddFileMaterialToCourse method
+func (r *CourseRepository) AddFileMaterialToCourse(material *model.LearningMaterial) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    // New material goes after the last one of the course
+    material.Position, err = nextMaterialPosition(tx, material.CourseID)
+    if err != nil {
+        return err
+    }
+
+    // Add new material with the type 'pdf' and file_url
+    insertQuery := `
+        INSERT INTO learning_materials (course_id, title, content_type, file_url, position)
+        VALUES ($1, $2, 'pdf', $3, $4)
+        RETURNING id, created_at, updated_at
+    `
+    err = tx.QueryRow(
+        insertQuery,
+        material.CourseID,
+        material.Title,
+        material.FileURL,
+        material.Position,
+    ).Scan(&material.ID, &material.CreatedAt, &material.UpdatedAt)
+
+    if err != nil {
+        log.Printf("Error adding file material to course: %v", err)
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// AddVideoMaterialToCourse adds an uploaded video, served from FileURL, with its duration and file size
+func (r *CourseRepository) AddVideoMaterialToCourse(material *model.LearningMaterial) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    // New material goes after the last one of the course
+    material.Position, err = nextMaterialPosition(tx, material.CourseID)
+    if err != nil {
+        return err
+    }
+
+    insertQuery := `
+        INSERT INTO learning_materials (course_id, title, content_type, file_url, duration_seconds, file_size_bytes, position)
+        VALUES ($1, $2, 'video', $3, $4, $5, $6)
+        RETURNING id, created_at, updated_at
+    `
+    err = tx.QueryRow(insertQuery, material.CourseID, material.Title, material.FileURL,
+        material.DurationSeconds, material.FileSizeBytes, material.Position,
+    ).Scan(&material.ID, &material.CreatedAt, &material.UpdatedAt)
+    if err != nil {
+        log.Printf("Error adding video material to course: %v", err)
+        return err
+    }
+
+    material.ContentType = "video"
+    return tx.Commit()
+}
+
+// GetCourseStaffRole method
+func (r *CourseRepository) GetCourseStaffRole(courseID, userID string) (string, error) {
+    var role string
+    query := `SELECT role FROM course_staff WHERE course_id = $1 AND user_id = $2`
+
+    err := r.DB.QueryRow(query, courseID, userID).Scan(&role)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return "", nil
+        }
+        return "", err
+    }
+    return role, nil
+}
+
+// GetCourseStaff method
+func (r *CourseRepository) GetCourseStaff(courseID string) ([]model.CourseStaff, error) {
+    query := `
+        SELECT s.course_id, s.user_id, u.full_name, u.email, s.role, s.created_at
+        FROM course_staff s
+        JOIN users u ON u.id = s.user_id
+        WHERE s.course_id = $1
+        ORDER BY s.role ASC, s.created_at ASC
+    `
+    rows, err := r.DB.Query(query, courseID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var staff []model.CourseStaff
+    for rows.Next() {
+        var member model.CourseStaff
+        if err := rows.Scan(&member.CourseID, &member.UserID, &member.FullName, &member.Email, &member.Role, &member.CreatedAt); err != nil {
+            return nil, err
+        }
+        staff = append(staff, member)
+    }
+    return staff, nil
+}
+
+// AddCourseStaff method
+func (r *CourseRepository) AddCourseStaff(courseID, userID, role string) error {
+    query := `INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, $3)`
+
+    // Execute the insert query
+    _, err := r.DB.Exec(query, courseID, userID, role)
+    if err != nil {
+        log.Printf("Error adding course staff: %v", err)
+        return err
+    }
+    return nil
+}
+
+// RemoveCourseStaff method
+func (r *CourseRepository) RemoveCourseStaff(courseID, userID string) error {
+    // The owner can never be removed
+    query := `DELETE FROM course_staff WHERE course_id = $1 AND user_id = $2 AND role <> 'owner'`
+
+    // Execute the delete query
+    result, err := r.DB.Exec(query, courseID, userID)
+    if err != nil {
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return sql.ErrNoRows // Indicates that the staff member was not found or is the owner
+    }
+
+    return nil
+}
+
+// DuplicateCourse method
+func (r *CourseRepository) DuplicateCourse(sourceID, instructorID, title string) (*model.Course, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    // Copy the course itself as a draft owned by the instructor
+    var course model.Course
+    courseQuery := `
+        INSERT INTO courses (instructor_id, title, description, cover_image_url, capacity, enrollment_mode, price_cents, currency, is_draft)
+        SELECT $1, $2, description, cover_image_url, capacity, enrollment_mode, price_cents, currency, TRUE FROM courses WHERE id = $3
+        RETURNING id, instructor_id, title, description, cover_image_url, is_draft, is_template, capacity, enrollment_mode, price_cents, currency, rating_average, rating_count, created_at, updated_at
+    `
+    err = tx.QueryRow(courseQuery, instructorID, title, sourceID).Scan(
+        &course.ID, &course.InstructorID, &course.Title, &course.Description,
+        &course.CoverImageURL, &course.IsDraft, &course.IsTemplate, &course.Capacity, &course.EnrollmentMode, &course.PriceCents, &course.Currency, &course.RatingAverage, &course.RatingCount, &course.CreatedAt, &course.UpdatedAt,
+    )
+    if err != nil {
+        log.Printf("Error duplicating course: %v", err)
+        return nil, err
+    }
+
+    staffQuery := `INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, 'owner')`
+    if _, err := tx.Exec(staffQuery, course.ID, instructorID); err != nil {
+        log.Printf("Error adding course owner: %v", err)
+        return nil, err
+    }
+
+    // Copy every section and material keeping their positions and release rules. Each source section and
+    // material gets a new ID up front so copies can be pointed at the copy of their section or required material.
+    materialsQuery := `
+        WITH section_map AS (
+            SELECT id AS old_id, gen_random_uuid() AS new_id FROM course_sections WHERE course_id = $2
+        ), material_map AS (
+            SELECT id AS old_id, gen_random_uuid() AS new_id FROM learning_materials WHERE course_id = $2
+        ), copied_sections AS (
+            INSERT INTO course_sections (id, course_id, parent_id, title, position)
+            SELECT m.new_id, $1, pm.new_id, s.title, s.position
+            FROM course_sections s
+            JOIN section_map m ON m.old_id = s.id
+            LEFT JOIN section_map pm ON pm.old_id = s.parent_id
+        )
+        INSERT INTO learning_materials (id, course_id, section_id, title, content_type, text_content, text_html, video_url, file_url, position, is_preview,
+                                        release_at, release_after_days, release_after_material_id, duration_seconds, file_size_bytes)
+        SELECT mm.new_id, $1, sm.new_id, lm.title, lm.content_type, lm.text_content, lm.text_html, lm.video_url, lm.file_url, lm.position, lm.is_preview,
+               lm.release_at, lm.release_after_days, rm.new_id, lm.duration_seconds, lm.file_size_bytes
+        FROM learning_materials lm
+        JOIN material_map mm ON mm.old_id = lm.id
+        LEFT JOIN section_map sm ON sm.old_id = lm.section_id
+        LEFT JOIN material_map rm ON rm.old_id = lm.release_after_material_id
+    `
+    if _, err := tx.Exec(materialsQuery, course.ID, sourceID); err != nil {
+        log.Printf("Error duplicating course materials: %v", err)
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return &course, nil
+}
+
+// UpdateMaterialFileURL method
+func (r *CourseRepository) UpdateMaterialFileURL(materialID, fileURL string) error {
+    query := `UPDATE learning_materials SET file_url = $1, updated_at = NOW() WHERE id = $2`
+
+    _, err := r.DB.Exec(query, fileURL, materialID)
+    if err != nil {
+        log.Printf("Error updating material file: %v", err)
+        return err
+    }
+    return nil
+}
+
+// DeleteCourse method
+func (r *CourseRepository) DeleteCourse(courseID string) error {
+    query := `DELETE FROM courses WHERE id = $1`
+
+    _, err := r.DB.Exec(query, courseID)
+    if err != nil {
+        log.Printf("Error deleting course: %v", err)
+        return err
+    }
+    return nil
+}
+
+// SetCourseDraft method
+func (r *CourseRepository) SetCourseDraft(courseID string, isDraft bool) error {
+    query := `UPDATE courses SET is_draft = $1, updated_at = NOW() WHERE id = $2`
+
+    // Execute the update query
+    result, err := r.DB.Exec(query, isDraft, courseID)
+    if err != nil {
+        log.Printf("Error updating course draft flag: %v", err)
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return sql.ErrNoRows // Indicates that the course was not found
+    }
+
+    return nil
+}
+
+// SetCourseTemplate method
+func (r *CourseRepository) SetCourseTemplate(courseID string, isTemplate bool) error {
+    query := `UPDATE courses SET is_template = $1, updated_at = NOW() WHERE id = $2`
+
+    // Execute the update query
+    result, err := r.DB.Exec(query, isTemplate, courseID)
+    if err != nil {
+        log.Printf("Error updating course template flag: %v", err)
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return sql.ErrNoRows // Indicates that the course was not found
+    }
+
+    return nil
+}
+
+// GetTemplateCourses method
+func (r *CourseRepository) GetTemplateCourses(params model.ListParams) ([]model.Course, *model.PageInfo, error) {
+    var q listQuery
+    q.where("is_template = TRUE")
+    q.applyCommonFilters(courseListSpec, params)
+
+    return queryPage(r.DB, courseListSpec, params, q, scanCourseRow)
+}
+
+// ImportCourse method
+func (r *CourseRepository) ImportCourse(course *model.Course, materials []model.LearningMaterial) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    // Imported courses start as drafts so they can be reviewed before publishing
+    courseQuery := `
+        INSERT INTO courses (id, instructor_id, title, description, cover_image_url, is_draft)
+        VALUES ($1, $2, $3, $4, $5, TRUE)
+        RETURNING is_draft, created_at, updated_at
+    `
+    err = tx.QueryRow(courseQuery, course.ID, course.InstructorID, course.Title, course.Description, course.CoverImageURL).
+        Scan(&course.IsDraft, &course.CreatedAt, &course.UpdatedAt)
+    if err != nil {
+        log.Printf("Error importing course: %v", err)
+        return err
+    }
+
+    staffQuery := `INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, 'owner')`
+    if _, err := tx.Exec(staffQuery, course.ID, course.InstructorID); err != nil {
+        log.Printf("Error adding course owner: %v", err)
+        return err
+    }
+
+    materialQuery := `
+        INSERT INTO learning_materials (course_id, title, content_type, text_content, text_html, video_url, file_url,
+                                        duration_seconds, file_size_bytes, position, is_preview)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10, $11)
+    `
+    for _, material := range materials {
+        textHTML, err := markdown.Render(material.TextContent)
+        if err != nil {
+            return err
+        }
+        _, err = tx.Exec(materialQuery, course.ID, material.Title, material.ContentType,
+            material.TextContent, textHTML, material.VideoURL, material.FileURL, material.DurationSeconds, material.FileSizeBytes,
+            material.Position, material.IsPreview)
+        if err != nil {
+            log.Printf("Error importing material: %v", err)
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// scanPrerequisites scans rows of prerequisite course id, title and requirement
+func scanPrerequisites(rows *sql.Rows) ([]model.Prerequisite, error) {
+    defer rows.Close()
+
+    prerequisites := []model.Prerequisite{}
+    for rows.Next() {
+        var p model.Prerequisite
+        if err := rows.Scan(&p.CourseID, &p.Title, &p.Requirement); err != nil {
+            return nil, err
+        }
+        prerequisites = append(prerequisites, p)
+    }
+    return prerequisites, rows.Err()
+}
+
+// GetPrerequisites method
+func (r *CourseRepository) GetPrerequisites(courseID string) ([]model.Prerequisite, error) {
+    query := `
+        SELECT c.id, c.title, p.requirement
+        FROM course_prerequisites p
+        JOIN courses c ON c.id = p.prerequisite_id
+        WHERE p.course_id = $1
+        ORDER BY c.title ASC
+    `
+    rows, err := r.DB.Query(query, courseID)
+    if err != nil {
+        return nil, err
+    }
+    return scanPrerequisites(rows)
+}
+
+// AddPrerequisite method
+func (r *CourseRepository) AddPrerequisite(courseID, prerequisiteID, requirement string) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    // Serialize prerequisite changes so two concurrent inserts cannot form a cycle together
+    if _, err := tx.Exec(`LOCK TABLE course_prerequisites IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+        return err
+    }
+
+    // Walk everything the new prerequisite depends on and make sure the course is not among it
+    var cycle bool
+    cycleQuery := `
+        WITH RECURSIVE chain AS (
+            SELECT prerequisite_id FROM course_prerequisites WHERE course_id = $1
+            UNION
+            SELECT p.prerequisite_id FROM course_prerequisites p JOIN chain ON p.course_id = chain.prerequisite_id
+        )
+        SELECT EXISTS(SELECT 1 FROM chain WHERE prerequisite_id = $2)
+    `
+    if err := tx.QueryRow(cycleQuery, prerequisiteID, courseID).Scan(&cycle); err != nil {
+        return err
+    }
+    if cycle {
+        return ErrPrerequisiteCycle
+    }
+
+    insertQuery := `INSERT INTO course_prerequisites (course_id, prerequisite_id, requirement) VALUES ($1, $2, $3)`
+    if _, err := tx.Exec(insertQuery, courseID, prerequisiteID, requirement); err != nil {
+        log.Printf("Error adding prerequisite: %v", err)
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// RemovePrerequisite method
+func (r *CourseRepository) RemovePrerequisite(courseID, prerequisiteID string) error {
+    query := `DELETE FROM course_prerequisites WHERE course_id = $1 AND prerequisite_id = $2`
+
+    // Execute the delete query
+    result, err := r.DB.Exec(query, courseID, prerequisiteID)
+    if err != nil {
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return sql.ErrNoRows // Indicates that the prerequisite was not found
+    }
+
+    return nil
+}
+
+// GetUnmetPrerequisites method
+func (r *CourseRepository) GetUnmetPrerequisites(studentID, courseID string) ([]model.Prerequisite, error) {
+    // Unenrolling deletes the enrollment, so a completed course is also found through its certificate
+    query := `
+        SELECT c.id, c.title, p.requirement
+        FROM course_prerequisites p
+        JOIN courses c ON c.id = p.prerequisite_id
+        LEFT JOIN enrollments e ON e.course_id = p.prerequisite_id AND e.user_id = $1
+        LEFT JOIN certificates cert ON cert.course_id = p.prerequisite_id AND cert.user_id = $1
+        WHERE p.course_id = $2 AND cert.id IS NULL
+          AND (e.user_id IS NULL OR (p.requirement = 'completed' AND e.completed_at IS NULL))
+        ORDER BY c.title ASC
+    `
+    rows, err := r.DB.Query(query, studentID, courseID)
+    if err != nil {
+        return nil, err
+    }
+    return scanPrerequisites(rows)
+}
+
+// CompleteMaterial method
+func (r *CourseRepository) CompleteMaterial(studentID, courseID, materialID string) (bool, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return false, err
+    }
+    defer tx.Rollback()
+
+    // The material must belong to the course and be released to the student,
+    // sql.ErrNoRows indicates that the material was not found in this course
+    if err := checkMaterialReleased(tx, studentID, courseID, materialID); err != nil {
+        return false, err
+    }
+
+    // Completing it twice is a no-op
+    completeQuery := `
+        INSERT INTO material_completions (user_id, material_id)
+        SELECT $1, id FROM learning_materials WHERE id = $2 AND course_id = $3
+        ON CONFLICT DO NOTHING
+    `
+    if _, err := tx.Exec(completeQuery, studentID, materialID, courseID); err != nil {
+        log.Printf("Error completing material: %v", err)
+        return false, err
+    }
+
+    // Mark the course completed once no material is left
+    enrollmentQuery := `
+        UPDATE enrollments SET completed_at = NOW()
+        WHERE user_id = $1 AND course_id = $2 AND completed_at IS NULL
+          AND NOT EXISTS (
+              SELECT 1 FROM learning_materials m
+              LEFT JOIN material_completions mc ON mc.material_id = m.id AND mc.user_id = $1
+              WHERE m.course_id = $2 AND mc.user_id IS NULL
+          )
+    `
+    if _, err := tx.Exec(enrollmentQuery, studentID, courseID); err != nil {
+        return false, err
+    }
+
+    var completed bool
+    completedQuery := `SELECT completed_at IS NOT NULL FROM enrollments WHERE user_id = $1 AND course_id = $2`
+    if err := tx.QueryRow(completedQuery, studentID, courseID).Scan(&completed); err != nil {
+        return false, err
+    }
+
+    // Completing the course earns its certificate
+    if completed {
+        if err := issueCertificate(tx, studentID, courseID); err != nil {
+            return false, err
+        }
+    }
+
+    return completed, tx.Commit()
+}
+
+const cohortColumns = `id, course_id, name, enrollment_opens_at, enrollment_closes_at, access_starts_at, access_ends_at, created_at`
+
+func scanCohort(row rowScanner) (model.Cohort, error) {
+    var cohort model.Cohort
+    err := row.Scan(
+        &cohort.ID, &cohort.CourseID, &cohort.Name, &cohort.EnrollmentOpensAt, &cohort.EnrollmentClosesAt,
+        &cohort.AccessStartsAt, &cohort.AccessEndsAt, &cohort.CreatedAt,
+    )
+    return cohort, err
+}
+
+// GetCohortsByCourseID method
+func (r *CourseRepository) GetCohortsByCourseID(courseID string) ([]model.Cohort, error) {
+    query := `SELECT ` + cohortColumns + ` FROM course_cohorts WHERE course_id = $1 ORDER BY access_starts_at ASC`
+    rows, err := r.DB.Query(query, courseID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    cohorts := []model.Cohort{}
+    for rows.Next() {
+        cohort, err := scanCohort(rows)
+        if err != nil {
+            return nil, err
+        }
+        cohorts = append(cohorts, cohort)
+    }
+    return cohorts, rows.Err()
+}
+
+// CreateCohort method
+func (r *CourseRepository) CreateCohort(cohort *model.Cohort) error {
+    query := `INSERT INTO course_cohorts (course_id, name, enrollment_opens_at, enrollment_closes_at, access_starts_at, access_ends_at)
+               VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+
+    err := r.DB.QueryRow(query, cohort.CourseID, cohort.Name, cohort.EnrollmentOpensAt, cohort.EnrollmentClosesAt,
+        cohort.AccessStartsAt, cohort.AccessEndsAt).Scan(&cohort.ID, &cohort.CreatedAt)
+    if err != nil {
+        log.Printf("Error creating cohort: %v", err)
+        return err
+    }
+    return nil
+}
+
+// UpdateCohort method
+func (r *CourseRepository) UpdateCohort(cohort *model.Cohort) error {
+    query := `UPDATE course_cohorts
+               SET name = $1, enrollment_opens_at = $2, enrollment_closes_at = $3, access_starts_at = $4, access_ends_at = $5
+               WHERE id = $6 AND course_id = $7
+               RETURNING created_at`
+
+    err := r.DB.QueryRow(query, cohort.Name, cohort.EnrollmentOpensAt, cohort.EnrollmentClosesAt,
+        cohort.AccessStartsAt, cohort.AccessEndsAt, cohort.ID, cohort.CourseID).Scan(&cohort.CreatedAt)
+    if err != nil {
+        if err != sql.ErrNoRows {
+            log.Printf("Error updating cohort: %v", err)
+        }
+        return err // sql.ErrNoRows when the cohort was not found in this course
+    }
+    return nil
+}
+
+// DeleteCohort method
+func (r *CourseRepository) DeleteCohort(courseID, cohortID string) error {
+    query := `DELETE FROM course_cohorts WHERE id = $1 AND course_id = $2`
+
+    // Execute the delete query, enrollments in the cohort make it fail with a foreign key violation
+    result, err := r.DB.Exec(query, cohortID, courseID)
+    if err != nil {
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return sql.ErrNoRows // Indicates that the cohort was not found
+    }
+
+    return nil
+}
+
+// GetEnrollmentAccess returns the student's enrollment and its cohort window, or nil when not enrolled
+func (r *CourseRepository) GetEnrollmentAccess(studentID, courseID string) (*model.EnrollmentAccess, error) {
+    query := `
+        SELECT e.cohort_id, ch.access_starts_at, ch.access_ends_at
+        FROM enrollments e
+        LEFT JOIN course_cohorts ch ON ch.id = e.cohort_id
+        WHERE e.user_id = $1 AND e.course_id = $2
+    `
+    var cohortID sql.NullString
+    var startsAt sql.NullTime
+    var access model.EnrollmentAccess
+    err := r.DB.QueryRow(query, studentID, courseID).Scan(&cohortID, &startsAt, &access.EndsAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, nil
+        }
+        return nil, err
+    }
+
+    access.CohortID = cohortID.String
+    access.StartsAt = startsAt.Time
+    return &access, nil
+}
+
+// SetEnrollmentMode method, a paid course can only use open enrollment and fails with ErrPaidCourseMode
+func (r *CourseRepository) SetEnrollmentMode(courseID, mode string) error {
+    query := `UPDATE courses SET enrollment_mode = $1, updated_at = NOW() WHERE id = $2 AND ($1 = 'open' OR price_cents = 0)`
+
+    // Execute the update query
+    result, err := r.DB.Exec(query, mode, courseID)
+    if err != nil {
+        log.Printf("Error updating enrollment mode: %v", err)
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return r.courseUpdateRefused(courseID, ErrPaidCourseMode)
+    }
+
+    return nil
+}
+
+// SetCoursePrice method, a price of 0 makes the course free.
+// Only a course with open enrollment can have a price, others fail with ErrPaidCourseMode.
+func (r *CourseRepository) SetCoursePrice(courseID string, priceCents int, currency string) error {
+    query := `UPDATE courses SET price_cents = $1, currency = $2, updated_at = NOW() WHERE id = $3 AND ($1 = 0 OR enrollment_mode = 'open')`
+
+    // Execute the update query
+    result, err := r.DB.Exec(query, priceCents, currency, courseID)
+    if err != nil {
+        log.Printf("Error updating course price: %v", err)
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return r.courseUpdateRefused(courseID, ErrPaidCourseMode)
+    }
+
+    return nil
+}
+
+// courseUpdateRefused tells apart a conditional course update that found no course, returning sql.ErrNoRows,
+// from one the course's state refused, returning refused
+func (r *CourseRepository) courseUpdateRefused(courseID string, refused error) error {
+    var exists bool
+    if err := r.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)`, courseID).Scan(&exists); err != nil {
+        return err
+    }
+    if !exists {
+        return sql.ErrNoRows // Indicates that the course was not found
+    }
+    return refused
+}
+
+// CreateEnrollmentRequest stores a pending request to join a course that requires approval.
+// A second pending request of the same student fails with a unique constraint violation.
+func (r *CourseRepository) CreateEnrollmentRequest(request *model.EnrollmentRequest) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := lockEnrollableCourse(tx, request.CourseID, model.EnrollmentModeApproval); err != nil {
+        return err
+    }
+
+    enrolled, err := isEnrolledTx(tx, request.UserID, request.CourseID)
+    if err != nil {
+        return err
+    }
+    if enrolled {
+        return ErrAlreadyEnrolled
+    }
+
+    cohortID := ""
+    if request.CohortID != nil {
+        cohortID = *request.CohortID
+    }
+    if err := checkCohortEnrollment(tx, request.CourseID, cohortID, true); err != nil {
+        return err
+    }
+
+    query := `INSERT INTO enrollment_requests (course_id, user_id, cohort_id, message)
+               VALUES ($1, $2, NULLIF($3, '')::uuid, NULLIF($4, ''))
+               RETURNING id, status, created_at`
+    err = tx.QueryRow(query, request.CourseID, request.UserID, cohortID, request.Message).
+        Scan(&request.ID, &request.Status, &request.CreatedAt)
+    if err != nil {
+        log.Printf("Error creating enrollment request: %v", err)
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// GetEnrollmentRequests returns the course's enrollment requests, optionally only those with status
+func (r *CourseRepository) GetEnrollmentRequests(courseID, status string) ([]model.EnrollmentRequest, error) {
+    query := `
+        SELECT er.id, er.course_id, er.user_id, u.full_name, u.email, er.cohort_id, COALESCE(er.message, ''),
+               er.status, er.decided_by, er.decided_at, er.created_at
+        FROM enrollment_requests er
+        JOIN users u ON u.id = er.user_id
+        WHERE er.course_id = $1 AND ($2 = '' OR er.status::text = $2)
+        ORDER BY er.created_at ASC
+    `
+    rows, err := r.DB.Query(query, courseID, status)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    requests := []model.EnrollmentRequest{}
+    for rows.Next() {
+        var req model.EnrollmentRequest
+        err := rows.Scan(&req.ID, &req.CourseID, &req.UserID, &req.FullName, &req.Email, &req.CohortID, &req.Message,
+            &req.Status, &req.DecidedBy, &req.DecidedAt, &req.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        requests = append(requests, req)
+    }
+    return requests, rows.Err()
+}
+
+// DecideEnrollmentRequest approves or rejects a pending request. An approved student is enrolled,
+// or waitlisted when the course is full, even if the cohort's enrollment window has closed since.
+// It returns the waitlist position, which is 0 when the student was enrolled or the request rejected.
+func (r *CourseRepository) DecideEnrollmentRequest(actorID, courseID, requestID string, approve bool) (int, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return 0, err
+    }
+    defer tx.Rollback()
+
+    seats, err := lockCourseSeats(tx, courseID)
+    if err != nil {
+        return 0, err
+    }
+
+    status := model.RequestStatusRejected
+    if approve {
+        status = model.RequestStatusApproved
+    }
+
+    var studentID, cohortID string
+    query := `UPDATE enrollment_requests SET status = $1, decided_by = $2, decided_at = NOW()
+               WHERE id = $3 AND course_id = $4 AND status = 'pending'
+               RETURNING user_id, COALESCE(cohort_id::text, '')`
+    if err := tx.QueryRow(query, status, actorID, requestID, courseID).Scan(&studentID, &cohortID); err != nil {
+        return 0, err // sql.ErrNoRows when there is no pending request with this ID
+    }
+
+    position := 0
+    if approve {
+        position, err = enrollInTx(tx, seats, studentID, courseID, cohortID, false, model.EnrollmentActionAdded, actorID)
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    return position, tx.Commit()
+}
+
+const accessCodeColumns = `id, course_id, code, cohort_id, max_uses, uses, expires_at, COALESCE(created_by::text, ''), created_at`
+
+func scanAccessCode(row rowScanner) (model.AccessCode, error) {
+    var code model.AccessCode
+    err := row.Scan(&code.ID, &code.CourseID, &code.Code, &code.CohortID, &code.MaxUses, &code.Uses,
+        &code.ExpiresAt, &code.CreatedBy, &code.CreatedAt)
+    return code, err
+}
+
+// CreateAccessCode method
+func (r *CourseRepository) CreateAccessCode(code *model.AccessCode) error {
+    query := `INSERT INTO enrollment_codes (course_id, code, cohort_id, max_uses, expires_at, created_by)
+               VALUES ($1, $2, $3, $4, $5, $6)
+               RETURNING ` + accessCodeColumns
+
+    created, err := scanAccessCode(r.DB.QueryRow(query, code.CourseID, code.Code, code.CohortID, code.MaxUses, code.ExpiresAt, code.CreatedBy))
+    if err != nil {
+        log.Printf("Error creating access code: %v", err)
+        return err
+    }
+    *code = created
+    return nil
+}
+
+// GetAccessCodes method
+func (r *CourseRepository) GetAccessCodes(courseID string) ([]model.AccessCode, error) {
+    query := `SELECT ` + accessCodeColumns + ` FROM enrollment_codes WHERE course_id = $1 ORDER BY created_at DESC`
+    rows, err := r.DB.Query(query, courseID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    codes := []model.AccessCode{}
+    for rows.Next() {
+        code, err := scanAccessCode(rows)
+        if err != nil {
+            return nil, err
+        }
+        codes = append(codes, code)
+    }
+    return codes, rows.Err()
+}
+
+// DeleteAccessCode method
+func (r *CourseRepository) DeleteAccessCode(courseID, codeID string) error {
+    query := `DELETE FROM enrollment_codes WHERE id = $1 AND course_id = $2`
+
+    // Execute the delete query
+    result, err := r.DB.Exec(query, codeID, courseID)
+    if err != nil {
+        return err
+    }
+
+    // Check if any rows were affected
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return sql.ErrNoRows // Indicates that the access code was not found
+    }
+
+    return nil
+}
+
+// RedeemAccessCode uses up one redemption of the code and enrolls the student, or adds them to the
+// waitlist when the course is full. A code bound to a cohort enrolls into that cohort.
+// It returns the waitlist position, which is 0 when the student was enrolled.
+func (r *CourseRepository) RedeemAccessCode(studentID, courseID, code, cohortID string) (int, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return 0, err
+    }
+    defer tx.Rollback()
+
+    seats, err := lockEnrollableCourse(tx, courseID, model.EnrollmentModeCode)
+    if err != nil {
+        return 0, err
+    }
+
+    // Counting the use in the same statement that checks the limit keeps concurrent redemptions within it
+    var codeCohortID string
+    query := `UPDATE enrollment_codes SET uses = uses + 1
+               WHERE course_id = $1 AND code = $2
+                 AND (max_uses IS NULL OR uses < max_uses)
+                 AND (expires_at IS NULL OR expires_at > NOW())
+               RETURNING COALESCE(cohort_id::text, '')`
+    if err := tx.QueryRow(query, courseID, code).Scan(&codeCohortID); err != nil {
+        if err == sql.ErrNoRows {
+            return 0, ErrInvalidAccessCode
+        }
+        return 0, err
+    }
+    if codeCohortID != "" {
+        cohortID = codeCohortID
+    }
+
+    position, err := enrollInTx(tx, seats, studentID, courseID, cohortID, true, model.EnrollmentActionEnrolled, studentID)
+    if err != nil {
+        return 0, err
+    }
+
+    return position, tx.Commit()
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"regexp"
 	"testing"
 	"time"
@@ -40,10 +41,18 @@ func TestCreateCourse(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
 		AddRow(expectedID, expectedCreatedAt, expectedUpdatedAt)
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(expectedSQL).
 		WithArgs(newCourse.Title, newCourse.Description, newCourse.InstructorID).
 		WillReturnRows(rows)
 
+	// The instructor is added as the course owner in the same transaction
+	expectedStaffSQL := regexp.QuoteMeta(`INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, 'owner')`)
+	mock.ExpectExec(expectedStaffSQL).
+		WithArgs(expectedID, newCourse.InstructorID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
 	// Run the function that will be tested
 	err = repo.CreateCourse(newCourse)
 
@@ -82,19 +91,21 @@ func TestGetCoursesByInstructorID(t *testing.T) {
 		{ID: "course-2", InstructorID: instructorID, Title: "Course Two", Description: "Desc Two"},
 	}
 
-	// SQL query that is expected to be executed
-	expectedSQL := regexp.QuoteMeta(`SELECT id, instructor_id, title, description, cover_image_url, created_at, updated_at FROM courses WHERE instructor_id = $1 ORDER BY created_at DESC`)
+	// SQL queries that are expected to be executed
+	expectedCountSQL := regexp.QuoteMeta(`SELECT COUNT(*) FROM courses WHERE id IN (SELECT course_id FROM course_staff WHERE user_id = $1)`)
+	expectedSQL := regexp.QuoteMeta(`SELECT id, instructor_id, title, description, cover_image_url, is_draft, is_template, capacity, enrollment_mode, price_cents, currency, rating_average, rating_count, created_at, updated_at, (created_at)::text, id::text FROM courses WHERE id IN (SELECT course_id FROM course_staff WHERE user_id = $1) ORDER BY created_at DESC, id DESC LIMIT 51`)
 
 	// Prepare the row of data that will be 'returned' by the fake database
-	rows := sqlmock.NewRows([]string{"id", "instructor_id", "title", "description", "cover_image_url", "created_at", "updated_at"}).
-		AddRow(expectedCourses[0].ID, expectedCourses[0].InstructorID, expectedCourses[0].Title, expectedCourses[0].Description, sql.NullString{}, time.Now(), time.Now()).
-		AddRow(expectedCourses[1].ID, expectedCourses[1].InstructorID, expectedCourses[1].Title, expectedCourses[1].Description, sql.NullString{}, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "instructor_id", "title", "description", "cover_image_url", "is_draft", "is_template", "capacity", "enrollment_mode", "price_cents", "currency", "rating_average", "rating_count", "created_at", "updated_at", "created_at", "id"}).
+		AddRow(expectedCourses[0].ID, expectedCourses[0].InstructorID, expectedCourses[0].Title, expectedCourses[0].Description, sql.NullString{}, false, false, nil, "open", 0, "USD", 0, 0, time.Now(), time.Now(), "2025-01-02 00:00:00+00", expectedCourses[0].ID).
+		AddRow(expectedCourses[1].ID, expectedCourses[1].InstructorID, expectedCourses[1].Title, expectedCourses[1].Description, sql.NullString{}, false, false, nil, "open", 0, "USD", 0, 0, time.Now(), time.Now(), "2025-01-01 00:00:00+00", expectedCourses[1].ID)
 
 	// Set expectations in the Mock
+	mock.ExpectQuery(expectedCountSQL).WithArgs(instructorID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 	mock.ExpectQuery(expectedSQL).WithArgs(instructorID).WillReturnRows(rows)
 
 	// Run the function that will be tested
-	courses, err := repo.GetCoursesByInstructorID(instructorID)
+	courses, pageInfo, err := repo.GetCoursesByInstructorID(instructorID, model.ListParams{Limit: 50})
 
 	// Check the result (Assert)
 	if err != nil {
@@ -106,9 +117,451 @@ func TestGetCoursesByInstructorID(t *testing.T) {
 	if courses[0].Title != expectedCourses[0].Title {
 		t.Errorf("expected first course title to be '%s', but got '%s'", expectedCourses[0].Title, courses[0].Title)
 	}
+	if pageInfo.Total != 2 || pageInfo.NextCursor != "" {
+		t.Errorf("expected total 2 without next cursor, but got %+v", pageInfo)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetCourseDetailByID(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	courseID := "course-1"
+
+	// SQL queries that are expected to be executed
+	expectedCourseSQL := regexp.QuoteMeta(`SELECT c.id, c.instructor_id, c.title, c.description, c.cover_image_url, c.is_draft, c.is_template, c.capacity, c.enrollment_mode, c.price_cents, c.currency, c.rating_average, c.rating_count, c.created_at, c.updated_at, u.full_name, (SELECT COUNT(*) FROM enrollments e WHERE e.course_id = c.id) FROM courses c JOIN users u ON u.id = c.instructor_id WHERE c.id = $1 AND c.is_draft = FALSE`)
+	expectedPrerequisitesSQL := regexp.QuoteMeta(`SELECT c.id, c.title, p.requirement FROM course_prerequisites p JOIN courses c ON c.id = p.prerequisite_id WHERE p.course_id = $1 ORDER BY c.title ASC`)
+	expectedOutlineSQL := regexp.QuoteMeta(`SELECT id, title, content_type, position, (is_preview = TRUE AND release_after_days IS NULL AND release_after_material_id IS NULL AND (release_at IS NULL OR release_at <= NOW())) FROM learning_materials WHERE course_id = $1 ORDER BY position ASC`)
+
+	// Set expectations in the Mock
+	mock.ExpectQuery(expectedCourseSQL).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "instructor_id", "title", "description", "cover_image_url", "is_draft", "is_template", "capacity", "enrollment_mode", "price_cents", "currency", "rating_average", "rating_count", "created_at", "updated_at", "full_name", "count"}).
+			AddRow(courseID, "instructor-123", "Course One", "Desc One", sql.NullString{}, false, false, 30, "open", 0, "USD", 0, 0, time.Now(), time.Now(), "Jane Instructor", 28))
+	mock.ExpectQuery(expectedPrerequisitesSQL).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "requirement"}).
+			AddRow("course-0", "Go Basics", "completed"))
+	mock.ExpectQuery(expectedOutlineSQL).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content_type", "position", "is_preview"}).
+			AddRow("material-1", "Welcome", "video", 1, true).
+			AddRow("material-2", "Chapter 1", "text", 2, false))
+
+	// Run the function that will be tested
+	detail, err := repo.GetCourseDetailByID(courseID)
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if detail.InstructorName != "Jane Instructor" {
+		t.Errorf("expected instructor name 'Jane Instructor', but got '%s'", detail.InstructorName)
+	}
+	if detail.SeatsLeft == nil || *detail.SeatsLeft != 2 {
+		t.Errorf("expected 2 seats left, but got %v", detail.SeatsLeft)
+	}
+	if len(detail.Prerequisites) != 1 || detail.Prerequisites[0].Requirement != "completed" {
+		t.Errorf("expected 1 completed prerequisite, but got %+v", detail.Prerequisites)
+	}
+	if len(detail.Outline) != 2 || !detail.Outline[0].IsPreview {
+		t.Errorf("expected 2 outline items with the first as preview, but got %+v", detail.Outline)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+
+func TestRemoveCourseStaffKeepsOwner(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	// SQL query that is expected to be executed
+	expectedSQL := regexp.QuoteMeta(`DELETE FROM course_staff WHERE course_id = $1 AND user_id = $2 AND role <> 'owner'`)
+
+	// No row is deleted when the user is the owner
+	mock.ExpectExec(expectedSQL).WithArgs("course-1", "owner-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Run the function that will be tested
+	err = repo.RemoveCourseStaff("course-1", "owner-1")
+
+	// Check the result (Assert)
+	if err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDuplicateCourse(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	sourceID, instructorID, newID := "course-1", "instructor-456", "course-2"
+
+	// The course, its owner, its sections and its materials are copied in one transaction
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO courses (instructor_id, title, description, cover_image_url, capacity, enrollment_mode, price_cents, currency, is_draft) SELECT $1, $2, description, cover_image_url, capacity, enrollment_mode, price_cents, currency, TRUE FROM courses WHERE id = $3`)).
+		WithArgs(instructorID, "Course One (Copy)", sourceID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "instructor_id", "title", "description", "cover_image_url", "is_draft", "is_template", "capacity", "enrollment_mode", "price_cents", "currency", "rating_average", "rating_count", "created_at", "updated_at"}).
+			AddRow(newID, instructorID, "Course One (Copy)", "Desc One", sql.NullString{}, true, false, nil, "open", 0, "USD", 0, 0, time.Now(), time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, 'owner')`)).
+		WithArgs(newID, instructorID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO learning_materials (id, course_id, section_id, title, content_type, text_content, text_html, video_url, file_url, position, is_preview, release_at, release_after_days, release_after_material_id, duration_seconds, file_size_bytes) SELECT mm.new_id, $1, sm.new_id,`)).
+		WithArgs(newID, sourceID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	// Run the function that will be tested
+	course, err := repo.DuplicateCourse(sourceID, instructorID, "Course One (Copy)")
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if course.ID != newID || !course.IsDraft {
+		t.Errorf("expected draft course %s, but got %+v", newID, course)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+func TestAddPrerequisiteRejectsCycle(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	// course-2 already requires course-1, so course-1 cannot require course-2
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`LOCK TABLE course_prerequisites IN SHARE ROW EXCLUSIVE MODE`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`WITH RECURSIVE chain AS`).WithArgs("course-2", "course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	err = repo.AddPrerequisite("course-1", "course-2", "completed")
+
+	// Check the result (Assert)
+	if !errors.Is(err, ErrPrerequisiteCycle) {
+		t.Errorf("expected ErrPrerequisiteCycle, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetUnmetPrerequisitesCountsCertificates(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	// The student completed course-1 and left it, so only the certificate is left
+	mock.ExpectQuery(regexp.QuoteMeta(`LEFT JOIN certificates cert ON cert.course_id = p.prerequisite_id AND cert.user_id = $1 WHERE p.course_id = $2 AND cert.id IS NULL`)).
+		WithArgs("student-1", "course-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "requirement"}))
+
+	// Run the function that will be tested
+	unmet, err := repo.GetUnmetPrerequisites("student-1", "course-2")
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(unmet) != 0 {
+		t.Errorf("expected no unmet prerequisites, but got %+v", unmet)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestEnrollStudentJoinsWaitlistWhenFull(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	studentID, courseID := "student-1", "course-1"
+
+	// The course row is locked, all 30 seats are taken and two students are already waiting
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(30, false, "open", 0, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2),`)).WithArgs(studentID, courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"enrolled", "waitlisted"}).AddRow(false, false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM course_cohorts WHERE course_id = $1)`)).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM enrollments WHERE course_id = $1`)).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(30))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM enrollments WHERE course_id = $1`)).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(30))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO course_waitlist (course_id, user_id, cohort_id, position)`)).WithArgs(courseID, studentID, "").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO enrollment_history (course_id, user_id, cohort_id, action, actor_id)`)).
+		WithArgs(courseID, studentID, "", "waitlisted", studentID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Run the function that will be tested
+	position, err := repo.EnrollStudent(studentID, courseID, "")
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if position != 3 {
+		t.Errorf("expected waitlist position 3, but got %d", position)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestEnrollStudentRejectsClosedCohort(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	studentID, courseID, cohortID := "student-1", "course-1", "cohort-1"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(nil, false, "open", 0, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2),`)).WithArgs(studentID, courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"enrolled", "waitlisted"}).AddRow(false, false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT NOW() >= enrollment_opens_at AND NOW() < enrollment_closes_at FROM course_cohorts WHERE id = $1 AND course_id = $2`)).
+		WithArgs(cohortID, courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"open"}).AddRow(false))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	_, err = repo.EnrollStudent(studentID, courseID, cohortID)
+
+	// Check the result (Assert)
+	if !errors.Is(err, ErrEnrollmentClosed) {
+		t.Errorf("expected ErrEnrollmentClosed, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUnenrollPromotesWaitlist(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	studentID, courseID := "student-1", "course-1"
+
+	// Leaving a full course records the change and hands the seat to the waitlist
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(30, false, "open", 0, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM enrollments WHERE user_id = $1 AND course_id = $2 RETURNING COALESCE(cohort_id::text, '')`)).
+		WithArgs(studentID, courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"cohort_id"}).AddRow(""))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO enrollment_history (course_id, user_id, cohort_id, action, actor_id)`)).
+		WithArgs(courseID, studentID, "", "left", studentID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM enrollments WHERE course_id = $1`)).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(29))
+	mock.ExpectExec(`WITH promoted AS`).WithArgs(courseID, sql.NullInt64{Int64: 1, Valid: true}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Run the function that will be tested
+	err = repo.Unenroll(studentID, studentID, courseID, "left")
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRedeemAccessCodeRejectsUsedUpCode(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	studentID, courseID := "student-1", "course-1"
+
+	// The use is only counted while the code is within its limit and not expired
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs(courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(nil, false, "code", 0, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE enrollment_codes SET uses = uses + 1`)).WithArgs(courseID, "K7P4QX2M").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	_, err = repo.RedeemAccessCode(studentID, courseID, "K7P4QX2M", "")
+
+	// Check the result (Assert)
+	if !errors.Is(err, ErrInvalidAccessCode) {
+		t.Errorf("expected ErrInvalidAccessCode, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestEnrollStudentRequiresOpenMode(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(nil, false, "approval", 0, "USD"))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	_, err = repo.EnrollStudent("student-1", "course-1", "")
+
+	// Check the result (Assert)
+	var modeErr *EnrollmentModeError
+	if !errors.As(err, &modeErr) || modeErr.Mode != "approval" {
+		t.Errorf("expected EnrollmentModeError for approval mode, but got %v", err)
+	}
 
 	// Ensure all expectations are met
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestUpdateMaterialStoresRenderedHTML(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	material := &model.LearningMaterial{
+		ID:          "material-1",
+		CourseID:    "course-1",
+		Title:       "Intro",
+		TextContent: "**Hello** <script>alert(1)</script>",
+	}
+
+	// The Markdown is stored along with its sanitized HTML
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE learning_materials SET title = $1, text_content = $2, text_html = NULLIF($3, ''), video_url = $4, updated_at = NOW() WHERE id = $5 AND course_id = $6`)).
+		WithArgs("Intro", material.TextContent, "<p><strong>Hello</strong> </p>\n", "", "material-1", "course-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.UpdateMaterial(material); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if material.TextHTML != "<p><strong>Hello</strong> </p>\n" {
+		t.Errorf("expected the rendered HTML on the material, got %q", material.TextHTML)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetPreviewMaterialSkipsDraftCourses(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	// The course is a draft, so the query finds no row
+	mock.ExpectQuery(regexp.QuoteMeta(`OR release_at <= NOW()) AND course_id IN (SELECT id FROM courses WHERE is_draft = FALSE)`)).
+		WithArgs("material-1", "course-1").
+		WillReturnError(sql.ErrNoRows)
+
+	material, err := repo.GetPreviewMaterial("course-1", "material-1")
+	if err != nil || material != nil {
+		t.Errorf("expected no material and no error, got %+v (%v)", material, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+const sectionColumns = `id, course_id, parent_id, title, position, created_at, updated_at`
+
+func scanSection(row rowScanner) (model.Section, error) {
+	var section model.Section
+	err := row.Scan(&section.ID, &section.CourseID, &section.ParentID, &section.Title, &section.Position,
+		&section.CreatedAt, &section.UpdatedAt)
+	return section, err
+}
+
+// GetSections retrieves the sections of a course as a flat list, ordered by position within each level
+func (r *CourseRepository) GetSections(courseID string) ([]model.Section, error) {
+	query := `SELECT ` + sectionColumns + ` FROM course_sections WHERE course_id = $1 ORDER BY position ASC, created_at ASC`
+	rows, err := r.DB.Query(query, courseID)
+	if err != nil {
+		log.Printf("Error fetching sections: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sections []model.Section
+	for rows.Next() {
+		section, err := scanSection(rows)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	return sections, rows.Err()
+}
+
+// GetSectionTree retrieves the sections of a course with their subsections nested, without materials
+func (r *CourseRepository) GetSectionTree(courseID string) ([]model.Section, error) {
+	sections, err := r.GetSections(courseID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCourseContent(sections, nil).Sections, nil
+}
+
+// GetCourseContent retrieves the sections and materials of a course arranged as a tree
+func (r *CourseRepository) GetCourseContent(courseID string) (*model.CourseContent, error) {
+	sections, err := r.GetSections(courseID)
+	if err != nil {
+		return nil, err
+	}
+	materials, err := r.GetMaterialsByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCourseContent(sections, materials), nil
+}
+
+// buildCourseContent nests subsections under their section and materials under their section,
+// keeping the order of both lists within every level
+func buildCourseContent(sections []model.Section, materials []model.LearningMaterial) *model.CourseContent {
+	content := &model.CourseContent{Sections: []model.Section{}, Materials: []model.LearningMaterial{}}
+
+	materialsBySection := map[string][]model.LearningMaterial{}
+	for _, material := range materials {
+		if material.SectionID == nil {
+			content.Materials = append(content.Materials, material)
+			continue
+		}
+		materialsBySection[*material.SectionID] = append(materialsBySection[*material.SectionID], material)
+	}
+
+	subsections := map[string][]model.Section{}
+	for _, section := range sections {
+		if section.ParentID != nil {
+			section.Sections = []model.Section{}
+			section.Materials = append([]model.LearningMaterial{}, materialsBySection[section.ID]...)
+			subsections[*section.ParentID] = append(subsections[*section.ParentID], section)
+		}
+	}
+	for _, section := range sections {
+		if section.ParentID == nil {
+			section.Sections = append([]model.Section{}, subsections[section.ID]...)
+			section.Materials = append([]model.LearningMaterial{}, materialsBySection[section.ID]...)
+			content.Sections = append(content.Sections, section)
+		}
+	}
+	return content
+}
+
+// checkSectionParent verifies that parentID is a top-level section of the course other than sectionID,
+// and that sectionID has no subsections of its own that would end up two levels deep
+func checkSectionParent(tx *sql.Tx, courseID, sectionID string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+
+	var parentIsTopLevel bool
+	query := `SELECT parent_id IS NULL FROM course_sections WHERE id = $1 AND course_id = $2`
+	if err := tx.QueryRow(query, *parentID, courseID).Scan(&parentIsTopLevel); err != nil {
+		return err // sql.ErrNoRows when the parent is not a section of this course
+	}
+	if !parentIsTopLevel || *parentID == sectionID {
+		return ErrSectionNesting
+	}
+
+	if sectionID != "" {
+		var hasSubsections bool
+		query := `SELECT EXISTS(SELECT 1 FROM course_sections WHERE parent_id = $1)`
+		if err := tx.QueryRow(query, sectionID).Scan(&hasSubsections); err != nil {
+			return err
+		}
+		if hasSubsections {
+			return ErrSectionNesting
+		}
+	}
+	return nil
+}
+
+// lockCourseContent locks the course row so concurrent section and material changes keep positions consistent
+func lockCourseContent(tx *sql.Tx, courseID string) error {
+	var id string
+	return tx.QueryRow(`SELECT id FROM courses WHERE id = $1 FOR UPDATE`, courseID).Scan(&id)
+}
+
+// CreateSection adds a section at the end of its level. It returns sql.ErrNoRows when the parent
+// is not a section of the course, and ErrSectionNesting when the parent is itself a subsection.
+func (r *CourseRepository) CreateSection(section *model.Section) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCourseContent(tx, section.CourseID); err != nil {
+		return err
+	}
+	if err := checkSectionParent(tx, section.CourseID, "", section.ParentID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO course_sections (course_id, parent_id, title, position)
+		SELECT $1, $2::uuid, $3, COALESCE(MAX(position), 0) + 1
+		FROM course_sections WHERE course_id = $1 AND parent_id IS NOT DISTINCT FROM $2::uuid
+		RETURNING id, position, created_at, updated_at`
+	err = tx.QueryRow(query, section.CourseID, section.ParentID, section.Title).
+		Scan(&section.ID, &section.Position, &section.CreatedAt, &section.UpdatedAt)
+	if err != nil {
+		log.Printf("Error creating section: %v", err)
+		return err
+	}
+
+	section.Sections = []model.Section{}
+	section.Materials = []model.LearningMaterial{}
+	return tx.Commit()
+}
+
+// UpdateSection renames a section and moves it under parentID, at the end of the new level when the parent changes.
+// It returns sql.ErrNoRows when the section or parent is not in the course, and ErrSectionNesting when the move
+// would nest sections more than one level deep.
+func (r *CourseRepository) UpdateSection(section *model.Section) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCourseContent(tx, section.CourseID); err != nil {
+		return err
+	}
+	if err := checkSectionParent(tx, section.CourseID, section.ID, section.ParentID); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE course_sections s SET
+			title = $1,
+			parent_id = $2::uuid,
+			position = CASE WHEN s.parent_id IS NOT DISTINCT FROM $2::uuid THEN s.position
+				ELSE (SELECT COALESCE(MAX(position), 0) + 1 FROM course_sections
+				      WHERE course_id = $4 AND parent_id IS NOT DISTINCT FROM $2::uuid) END,
+			updated_at = NOW()
+		WHERE s.id = $3 AND s.course_id = $4
+		RETURNING ` + sectionColumns
+	updated, err := scanSection(tx.QueryRow(query, section.Title, section.ParentID, section.ID, section.CourseID))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Error updating section: %v", err)
+		}
+		return err
+	}
+
+	*section = updated
+	return tx.Commit()
+}
+
+// DeleteSection deletes a section without subsections. Its materials move to the parent section,
+// or out of any section for a top-level section.
+func (r *CourseRepository) DeleteSection(courseID, sectionID string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCourseContent(tx, courseID); err != nil {
+		return err
+	}
+
+	var parentID *string
+	query := `SELECT parent_id FROM course_sections WHERE id = $1 AND course_id = $2`
+	if err := tx.QueryRow(query, sectionID, courseID).Scan(&parentID); err != nil {
+		return err
+	}
+
+	moveQuery := `UPDATE learning_materials SET section_id = $1, updated_at = NOW() WHERE section_id = $2`
+	if _, err := tx.Exec(moveQuery, parentID, sectionID); err != nil {
+		log.Printf("Error moving section materials: %v", err)
+		return err
+	}
+
+	// Subsections still reference the section, which fails with a foreign key violation
+	if _, err := tx.Exec(`DELETE FROM course_sections WHERE id = $1`, sectionID); err != nil {
+		log.Printf("Error deleting section: %v", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReorderSections sets the order of the sections under parentID, or of the top-level sections when it is nil.
+// sectionIDs must list every section of that level exactly once.
+func (r *CourseRepository) ReorderSections(courseID string, parentID *string, sectionIDs []string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCourseContent(tx, courseID); err != nil {
+		return err
+	}
+
+	query := `SELECT id FROM course_sections WHERE course_id = $1 AND parent_id IS NOT DISTINCT FROM $2::uuid`
+	rows, err := tx.Query(query, courseID, parentID)
+	if err != nil {
+		return err
+	}
+	level := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		level[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(sectionIDs) != len(level) {
+		return ErrSectionOrderMismatch
+	}
+	for _, id := range sectionIDs {
+		if !level[id] {
+			return ErrSectionOrderMismatch
+		}
+		delete(level, id) // A repeated section ID will not be found again
+	}
+
+	updateQuery := `UPDATE course_sections SET position = $1, updated_at = NOW() WHERE id = $2`
+	for i, id := range sectionIDs {
+		if _, err := tx.Exec(updateQuery, i+1, id); err != nil {
+			log.Printf("Error reordering sections: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetMaterialSection moves a material into a section of its course, or out of any section when sectionID is nil.
+// It returns sql.ErrNoRows when the material or the section is not in the course.
+func (r *CourseRepository) SetMaterialSection(courseID, materialID string, sectionID *string) error {
+	query := `
+		UPDATE learning_materials SET section_id = $1::uuid, updated_at = NOW()
+		WHERE id = $2 AND course_id = $3
+		  AND ($1::uuid IS NULL OR EXISTS(SELECT 1 FROM course_sections WHERE id = $1::uuid AND course_id = $3))`
+	result, err := r.DB.Exec(query, sectionID, materialID, courseID)
+	if err != nil {
+		log.Printf("Error moving material to section: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
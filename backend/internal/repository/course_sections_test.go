@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+func TestBuildCourseContent(t *testing.T) {
+	week1, week2, week1a := "week-1", "week-2", "week-1a"
+	sections := []model.Section{
+		{ID: week1, Title: "Week 1", Position: 1},
+		{ID: week1a, ParentID: &week1, Title: "Week 1 extras", Position: 1},
+		{ID: week2, Title: "Week 2", Position: 2},
+	}
+	materials := []model.LearningMaterial{
+		{ID: "m-1", SectionID: &week1},
+		{ID: "m-2"},
+		{ID: "m-3", SectionID: &week1a},
+		{ID: "m-4", SectionID: &week1},
+	}
+
+	content := buildCourseContent(sections, materials)
+
+	if len(content.Sections) != 2 || content.Sections[0].ID != week1 || content.Sections[1].ID != week2 {
+		t.Fatalf("expected top-level sections week-1 and week-2, got %+v", content.Sections)
+	}
+	first := content.Sections[0]
+	if len(first.Materials) != 2 || first.Materials[0].ID != "m-1" || first.Materials[1].ID != "m-4" {
+		t.Errorf("expected week-1 to hold m-1 and m-4 in order, got %+v", first.Materials)
+	}
+	if len(first.Sections) != 1 || first.Sections[0].ID != week1a {
+		t.Fatalf("expected week-1a nested under week-1, got %+v", first.Sections)
+	}
+	if len(first.Sections[0].Materials) != 1 || first.Sections[0].Materials[0].ID != "m-3" {
+		t.Errorf("expected week-1a to hold m-3, got %+v", first.Sections[0].Materials)
+	}
+	if content.Sections[1].Sections == nil || content.Sections[1].Materials == nil {
+		t.Error("expected empty sections and materials to encode as empty lists, not null")
+	}
+	if len(content.Materials) != 1 || content.Materials[0].ID != "m-2" {
+		t.Errorf("expected m-2 outside any section, got %+v", content.Materials)
+	}
+}
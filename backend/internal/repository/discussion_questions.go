@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+// ErrNestedAnswer is returned when a comment on a reply is accepted as the answer to a question
+var ErrNestedAnswer = errors.New("only a direct reply to a question can be its accepted answer")
+
+// questionListSpec describes how the questions of the Q&A area are paginated, sorted and filtered
+var questionListSpec = listSpec{
+	selectClause:  `SELECT ` + threadColumns + `, c.title`,
+	fromClause:    `FROM discussion_threads t JOIN courses c ON c.id = t.course_id LEFT JOIN users u ON u.id = t.author_id`,
+	idColumn:      "t.id",
+	createdColumn: "t.created_at",
+	sortColumns: map[string]sortColumn{
+		"created_at":       {expr: "t.created_at", cast: "timestamptz"},
+		"last_activity_at": {expr: "t.last_activity_at", cast: "timestamptz"},
+		"votes":            {expr: "t.vote_count", cast: "integer"},
+	},
+	defaultSort: "created_at",
+	defaultDesc: true,
+}
+
+// unansweredCondition matches the questions nobody has answered yet
+const unansweredCondition = "NOT EXISTS(SELECT 1 FROM discussion_posts a WHERE a.thread_id = t.id AND a.parent_id IS NULL)"
+
+func scanQuestion(rows *sql.Rows, cursor *pageCursor) (model.CourseQuestion, error) {
+	var question model.CourseQuestion
+	err := scanThread(rows, &question.Thread, &question.CourseTitle, &cursor.Value, &cursor.ID)
+	return question, err
+}
+
+// GetQuestions retrieves a page of the questions of a course as read by userID,
+// only those without any answer when unanswered is set
+func (r *DiscussionRepository) GetQuestions(courseID, userID string, unanswered bool, params model.ListParams) ([]model.CourseQuestion, *model.PageInfo, error) {
+	var q listQuery
+	q.where("t.course_id = $%d", courseID)
+	q.where("t.kind = 'question'")
+	if unanswered {
+		q.where(unansweredCondition)
+	}
+	q.applyCommonFilters(questionListSpec, params)
+
+	questions, pageInfo, err := queryPage(r.DB, questionListSpec, params, q, scanQuestion)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.markVotedQuestions(questions, userID); err != nil {
+		return nil, nil, err
+	}
+	return questions, pageInfo, nil
+}
+
+// GetUnansweredQuestions retrieves a page of the unanswered questions across every course
+// the instructor is on the staff of, the same courses GetCoursesByInstructorID lists
+func (r *DiscussionRepository) GetUnansweredQuestions(instructorID string, params model.ListParams) ([]model.CourseQuestion, *model.PageInfo, error) {
+	var q listQuery
+	q.where("t.course_id IN (SELECT course_id FROM course_staff WHERE user_id = $%d)", instructorID)
+	q.where("t.kind = 'question'")
+	q.where(unansweredCondition)
+	q.applyCommonFilters(questionListSpec, params)
+
+	return queryPage(r.DB, questionListSpec, params, q, scanQuestion)
+}
+
+// markVotedQuestions sets Voted on the questions userID has upvoted
+func (r *DiscussionRepository) markVotedQuestions(questions []model.CourseQuestion, userID string) error {
+	if len(questions) == 0 {
+		return nil
+	}
+	ids := make([]string, len(questions))
+	for i, question := range questions {
+		ids[i] = question.ID
+	}
+
+	rows, err := r.DB.Query(`SELECT thread_id FROM thread_votes WHERE user_id = $1 AND thread_id::text = ANY($2)`, userID, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	voted := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		voted[id] = true
+	}
+	for i := range questions {
+		questions[i].Voted = voted[questions[i].ID]
+	}
+	return rows.Err()
+}
+
+// VoteThread adds or removes the upvote of userID on a question and returns its new vote count.
+// Voting twice or removing a missing vote leaves the count unchanged.
+func (r *DiscussionRepository) VoteThread(threadID, userID string, up bool) (int, error) {
+	query := `
+		WITH v AS (INSERT INTO thread_votes (thread_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING RETURNING thread_id)
+		UPDATE discussion_threads SET vote_count = vote_count + 1 WHERE id IN (SELECT thread_id FROM v)`
+	if !up {
+		query = `
+		WITH v AS (DELETE FROM thread_votes WHERE thread_id = $1 AND user_id = $2 RETURNING thread_id)
+		UPDATE discussion_threads SET vote_count = vote_count - 1 WHERE id IN (SELECT thread_id FROM v)`
+	}
+	if _, err := r.DB.Exec(query, threadID, userID); err != nil {
+		log.Printf("Error voting on thread: %v", err)
+		return 0, err
+	}
+
+	var count int
+	err := r.DB.QueryRow(`SELECT vote_count FROM discussion_threads WHERE id = $1`, threadID).Scan(&count)
+	return count, err
+}
+
+// VotePost adds or removes the upvote of userID on an answer and returns its new vote count.
+// Voting twice or removing a missing vote leaves the count unchanged.
+func (r *DiscussionRepository) VotePost(postID, userID string, up bool) (int, error) {
+	query := `
+		WITH v AS (INSERT INTO post_votes (post_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING RETURNING post_id)
+		UPDATE discussion_posts SET vote_count = vote_count + 1 WHERE id IN (SELECT post_id FROM v)`
+	if !up {
+		query = `
+		WITH v AS (DELETE FROM post_votes WHERE post_id = $1 AND user_id = $2 RETURNING post_id)
+		UPDATE discussion_posts SET vote_count = vote_count - 1 WHERE id IN (SELECT post_id FROM v)`
+	}
+	if _, err := r.DB.Exec(query, postID, userID); err != nil {
+		log.Printf("Error voting on post: %v", err)
+		return 0, err
+	}
+
+	var count int
+	err := r.DB.QueryRow(`SELECT vote_count FROM discussion_posts WHERE id = $1`, postID).Scan(&count)
+	return count, err
+}
+
+// SetAcceptedAnswer marks a reply of a question as its accepted answer, or clears it when postID is nil.
+// Only direct replies count as answers, a comment on one fails with ErrNestedAnswer.
+// It returns sql.ErrNoRows when the reply does not belong to the question.
+func (r *DiscussionRepository) SetAcceptedAnswer(threadID string, postID *string) error {
+	query := `
+		UPDATE discussion_threads SET accepted_post_id = $1::uuid
+		WHERE id = $2 AND kind = 'question'
+		  AND ($1::uuid IS NULL OR EXISTS(SELECT 1 FROM discussion_posts WHERE id = $1::uuid AND thread_id = $2 AND parent_id IS NULL))`
+	result, err := r.DB.Exec(query, postID, threadID)
+	if err != nil {
+		log.Printf("Error accepting answer: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if postID != nil {
+			var nested bool
+			err := r.DB.QueryRow(`SELECT parent_id IS NOT NULL FROM discussion_posts WHERE id = $1 AND thread_id = $2`, *postID, threadID).Scan(&nested)
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+			if nested {
+				return ErrNestedAnswer
+			}
+		}
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+type DiscussionRepository struct {
+	DB *sql.DB
+}
+
+func NewDiscussionRepository(db *sql.DB) *DiscussionRepository {
+	return &DiscussionRepository{DB: db}
+}
+
+const threadColumns = `t.id, t.course_id, t.material_id, t.kind, t.author_id, COALESCE(u.full_name, ''), t.title, t.body,
+	t.is_pinned, t.is_locked, (SELECT COUNT(*) FROM discussion_posts p WHERE p.thread_id = t.id),
+	t.vote_count, t.accepted_post_id, t.edited_at, t.last_activity_at, t.created_at`
+
+// scanThread scans threadColumns into thread, followed by any extra columns of the query
+func scanThread(row interface{ Scan(...any) error }, thread *model.Thread, extra ...any) error {
+	dest := []any{
+		&thread.ID, &thread.CourseID, &thread.MaterialID, &thread.Kind, &thread.AuthorID, &thread.AuthorName, &thread.Title, &thread.Body,
+		&thread.IsPinned, &thread.IsLocked, &thread.ReplyCount, &thread.VoteCount, &thread.AcceptedPostID,
+		&thread.EditedAt, &thread.LastActivityAt, &thread.CreatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// GetThreads retrieves the discussion threads of a course, or only those about materialID when it is set,
+// pinned ones first and then by latest activity. Questions are listed by GetQuestions.
+func (r *DiscussionRepository) GetThreads(courseID, materialID string) ([]model.Thread, error) {
+	query := `SELECT ` + threadColumns + `
+	           FROM discussion_threads t LEFT JOIN users u ON u.id = t.author_id
+	           WHERE t.course_id = $1 AND t.kind = 'discussion' AND ($2 = '' OR t.material_id::text = $2)
+	           ORDER BY t.is_pinned DESC, t.last_activity_at DESC`
+	rows, err := r.DB.Query(query, courseID, materialID)
+	if err != nil {
+		log.Printf("Error fetching threads: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	threads := []model.Thread{}
+	for rows.Next() {
+		var thread model.Thread
+		if err := scanThread(rows, &thread); err != nil {
+			return nil, err
+		}
+		threads = append(threads, thread)
+	}
+	return threads, rows.Err()
+}
+
+// GetThread retrieves a thread of a course as read by userID, or nil when the course has no such thread
+func (r *DiscussionRepository) GetThread(courseID, threadID, userID string) (*model.Thread, error) {
+	var thread model.Thread
+	query := `SELECT ` + threadColumns + `, EXISTS(SELECT 1 FROM thread_votes v WHERE v.thread_id = t.id AND v.user_id = $3)
+	           FROM discussion_threads t LEFT JOIN users u ON u.id = t.author_id
+	           WHERE t.id = $1 AND t.course_id = $2`
+	err := scanThread(r.DB.QueryRow(query, threadID, courseID, userID), &thread, &thread.Voted)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+// CreateThread stores a new thread. It returns sql.ErrNoRows when the thread's material
+// does not belong to its course.
+func (r *DiscussionRepository) CreateThread(thread *model.Thread) error {
+	query := `
+		INSERT INTO discussion_threads (course_id, material_id, kind, author_id, title, body)
+		SELECT $1, $2::uuid, $3, $4, $5, $6
+		WHERE $2::uuid IS NULL OR EXISTS(SELECT 1 FROM learning_materials WHERE id = $2::uuid AND course_id = $1)
+		RETURNING id, last_activity_at, created_at`
+	err := r.DB.QueryRow(query, thread.CourseID, thread.MaterialID, thread.Kind, thread.AuthorID, thread.Title, thread.Body).
+		Scan(&thread.ID, &thread.LastActivityAt, &thread.CreatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error creating thread: %v", err)
+	}
+	return err
+}
+
+// UpdateThread changes the title and body of a thread and records when it was edited
+func (r *DiscussionRepository) UpdateThread(thread *model.Thread) error {
+	query := `UPDATE discussion_threads SET title = $1, body = $2, edited_at = NOW()
+	           WHERE id = $3 AND course_id = $4 RETURNING edited_at`
+	err := r.DB.QueryRow(query, thread.Title, thread.Body, thread.ID, thread.CourseID).Scan(&thread.EditedAt)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error updating thread: %v", err)
+	}
+	return err
+}
+
+// SetThreadPinned pins a thread above the others or unpins it
+func (r *DiscussionRepository) SetThreadPinned(courseID, threadID string, pinned bool) error {
+	query := `UPDATE discussion_threads SET is_pinned = $1 WHERE id = $2 AND course_id = $3`
+	result, err := r.DB.Exec(query, pinned, threadID, courseID)
+	if err != nil {
+		log.Printf("Error pinning thread: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetThreadLocked locks a thread against new replies from students or unlocks it
+func (r *DiscussionRepository) SetThreadLocked(courseID, threadID string, locked bool) error {
+	query := `UPDATE discussion_threads SET is_locked = $1 WHERE id = $2 AND course_id = $3`
+	result, err := r.DB.Exec(query, locked, threadID, courseID)
+	if err != nil {
+		log.Printf("Error locking thread: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteThread deletes a thread with all its replies
+func (r *DiscussionRepository) DeleteThread(courseID, threadID string) error {
+	result, err := r.DB.Exec(`DELETE FROM discussion_threads WHERE id = $1 AND course_id = $2`, threadID, courseID)
+	if err != nil {
+		log.Printf("Error deleting thread: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetPosts retrieves the replies of a thread as read by userID, nested as a tree and oldest first at every level.
+// The replies to a question are ordered by upvotes first, with the accepted answer on top.
+func (r *DiscussionRepository) GetPosts(thread *model.Thread, userID string) ([]model.Post, error) {
+	query := `SELECT p.id, p.thread_id, p.parent_id, p.author_id, COALESCE(u.full_name, ''), p.body, p.vote_count,
+	                  EXISTS(SELECT 1 FROM post_votes v WHERE v.post_id = p.id AND v.user_id = $2), p.edited_at, p.created_at
+	           FROM discussion_posts p LEFT JOIN users u ON u.id = p.author_id
+	           WHERE p.thread_id = $1
+	           ORDER BY CASE WHEN $3 THEN p.vote_count END DESC NULLS LAST, p.created_at ASC, p.id ASC`
+	isQuestion := thread.Kind == model.ThreadKindQuestion
+	rows, err := r.DB.Query(query, thread.ID, userID, isQuestion)
+	if err != nil {
+		log.Printf("Error fetching posts: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []model.Post
+	for rows.Next() {
+		var post model.Post
+		if err := rows.Scan(&post.ID, &post.ThreadID, &post.ParentID, &post.AuthorID, &post.AuthorName, &post.Body,
+			&post.VoteCount, &post.Voted, &post.EditedAt, &post.CreatedAt); err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tree := nestPosts(posts)
+	if isQuestion && thread.AcceptedPostID != nil {
+		for i, post := range tree {
+			if post.ID == *thread.AcceptedPostID {
+				copy(tree[1:i+1], tree[:i])
+				tree[0] = post
+				break
+			}
+		}
+	}
+	return tree, nil
+}
+
+// nestPosts arranges a flat list of posts into a tree, keeping the order of the list among siblings
+func nestPosts(posts []model.Post) []model.Post {
+	children := map[string][]model.Post{}
+	for _, post := range posts {
+		parent := ""
+		if post.ParentID != nil {
+			parent = *post.ParentID
+		}
+		children[parent] = append(children[parent], post)
+	}
+
+	var build func(parent string) []model.Post
+	build = func(parent string) []model.Post {
+		level := []model.Post{}
+		for _, post := range children[parent] {
+			post.Replies = build(post.ID)
+			level = append(level, post)
+		}
+		return level
+	}
+	return build("")
+}
+
+// GetPost retrieves a reply of a thread, or nil when the thread has no such reply
+func (r *DiscussionRepository) GetPost(threadID, postID string) (*model.Post, error) {
+	var post model.Post
+	query := `SELECT p.id, p.thread_id, p.parent_id, p.author_id, COALESCE(u.full_name, ''), p.body, p.vote_count, p.edited_at, p.created_at
+	           FROM discussion_posts p LEFT JOIN users u ON u.id = p.author_id
+	           WHERE p.id = $1 AND p.thread_id = $2`
+	err := r.DB.QueryRow(query, postID, threadID).Scan(&post.ID, &post.ThreadID, &post.ParentID, &post.AuthorID,
+		&post.AuthorName, &post.Body, &post.VoteCount, &post.EditedAt, &post.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// CreatePost stores a reply and bumps the thread's last activity. It returns sql.ErrNoRows
+// when the parent reply does not belong to the same thread.
+func (r *DiscussionRepository) CreatePost(post *model.Post) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO discussion_posts (thread_id, parent_id, author_id, body)
+		SELECT $1, $2::uuid, $3, $4
+		WHERE $2::uuid IS NULL OR EXISTS(SELECT 1 FROM discussion_posts WHERE id = $2::uuid AND thread_id = $1)
+		RETURNING id, created_at`
+	if err := tx.QueryRow(query, post.ThreadID, post.ParentID, post.AuthorID, post.Body).Scan(&post.ID, &post.CreatedAt); err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Error creating post: %v", err)
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE discussion_threads SET last_activity_at = $1 WHERE id = $2`, post.CreatedAt, post.ThreadID); err != nil {
+		return err
+	}
+
+	post.Replies = []model.Post{}
+	return tx.Commit()
+}
+
+// UpdatePost changes the body of a reply and records when it was edited
+func (r *DiscussionRepository) UpdatePost(post *model.Post) error {
+	query := `UPDATE discussion_posts SET body = $1, edited_at = NOW() WHERE id = $2 AND thread_id = $3 RETURNING edited_at`
+	err := r.DB.QueryRow(query, post.Body, post.ID, post.ThreadID).Scan(&post.EditedAt)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error updating post: %v", err)
+	}
+	return err
+}
+
+// DeletePost deletes a reply together with the replies nested under it
+func (r *DiscussionRepository) DeletePost(threadID, postID string) error {
+	result, err := r.DB.Exec(`DELETE FROM discussion_posts WHERE id = $1 AND thread_id = $2`, postID, threadID)
+	if err != nil {
+		log.Printf("Error deleting post: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+func TestGetPostsNestsReplies(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewDiscussionRepository(db)
+	now := time.Now()
+
+	thread := &model.Thread{ID: "thread-1", Kind: model.ThreadKindDiscussion}
+
+	rows := sqlmock.NewRows([]string{"id", "thread_id", "parent_id", "author_id", "full_name", "body", "vote_count", "voted", "edited_at", "created_at"}).
+		AddRow("post-1", "thread-1", nil, "student-1", "Student One", "First", 0, false, nil, now).
+		AddRow("post-2", "thread-1", "post-1", "student-2", "Student Two", "Reply to first", 0, false, nil, now).
+		AddRow("post-3", "thread-1", nil, "student-2", "Student Two", "Second", 0, false, nil, now).
+		AddRow("post-4", "thread-1", "post-2", "student-1", "Student One", "Reply to reply", 0, false, nil, now)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM discussion_posts p LEFT JOIN users u ON u.id = p.author_id WHERE p.thread_id = $1`)).
+		WithArgs("thread-1", "student-1", false).WillReturnRows(rows)
+
+	// Run the function that will be tested
+	posts, err := repo.GetPosts(thread, "student-1")
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("error was not expected while fetching posts: %s", err)
+	}
+	if len(posts) != 2 || posts[0].ID != "post-1" || posts[1].ID != "post-3" {
+		t.Fatalf("expected top-level posts post-1 and post-3, but got %+v", posts)
+	}
+	if len(posts[0].Replies) != 1 || posts[0].Replies[0].ID != "post-2" {
+		t.Fatalf("expected post-2 nested under post-1, but got %+v", posts[0].Replies)
+	}
+	if len(posts[0].Replies[0].Replies) != 1 || posts[0].Replies[0].Replies[0].ID != "post-4" {
+		t.Errorf("expected post-4 nested under post-2, but got %+v", posts[0].Replies[0].Replies)
+	}
+	if posts[1].Replies == nil || len(posts[1].Replies) != 0 {
+		t.Errorf("expected post-3 to have an empty reply list, but got %+v", posts[1].Replies)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetPostsPutsAcceptedAnswerFirst(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewDiscussionRepository(db)
+	now := time.Now()
+	accepted := "post-3"
+	thread := &model.Thread{ID: "thread-1", Kind: model.ThreadKindQuestion, AcceptedPostID: &accepted}
+
+	// The query already orders answers by votes
+	rows := sqlmock.NewRows([]string{"id", "thread_id", "parent_id", "author_id", "full_name", "body", "vote_count", "voted", "edited_at", "created_at"}).
+		AddRow("post-1", "thread-1", nil, "student-1", "Student One", "Most upvoted", 5, true, nil, now).
+		AddRow("post-2", "thread-1", nil, "student-2", "Student Two", "Upvoted", 2, false, nil, now).
+		AddRow("post-3", "thread-1", nil, "student-3", "Student Three", "Accepted", 1, false, nil, now)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM discussion_posts p LEFT JOIN users u ON u.id = p.author_id WHERE p.thread_id = $1`)).
+		WithArgs("thread-1", "student-2", true).WillReturnRows(rows)
+
+	// Run the function that will be tested
+	posts, err := repo.GetPosts(thread, "student-2")
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("error was not expected while fetching posts: %s", err)
+	}
+	var order []string
+	for _, post := range posts {
+		order = append(order, post.ID)
+	}
+	if len(order) != 3 || order[0] != "post-3" || order[1] != "post-1" || order[2] != "post-2" {
+		t.Errorf("expected answers in order [post-3 post-1 post-2], but got %v", order)
+	}
+	if !posts[1].Voted || posts[1].VoteCount != 5 {
+		t.Errorf("expected post-1 to keep its votes, but got %+v", posts[1])
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSetAcceptedAnswerRejectsNestedReply(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewDiscussionRepository(db)
+	postID := "post-2"
+
+	// The post is in the question but comments on another reply, so nothing is updated
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE discussion_threads SET accepted_post_id = $1::uuid`)).WithArgs(&postID, "thread-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT parent_id IS NOT NULL FROM discussion_posts WHERE id = $1 AND thread_id = $2`)).WithArgs(postID, "thread-1").
+		WillReturnRows(sqlmock.NewRows([]string{"nested"}).AddRow(true))
+
+	// Run the function that will be tested
+	err = repo.SetAcceptedAnswer("thread-1", &postID)
+
+	// Check the result (Assert)
+	if !errors.Is(err, ErrNestedAnswer) {
+		t.Errorf("expected ErrNestedAnswer, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
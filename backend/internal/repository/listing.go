@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort field")
+)
+
+// sortColumn describes a column a list can be ordered by
+type sortColumn struct {
+	expr string // SQL expression used in ORDER BY and keyset comparisons
+	cast string // Postgres type the cursor value is cast back to
+}
+
+// listSpec describes how a list query maps ListParams onto SQL
+type listSpec struct {
+	selectClause  string // Columns scanned into the item, without the trailing cursor columns
+	fromClause    string // FROM and JOIN clauses
+	idColumn      string // Unique column used as keyset tie-breaker
+	createdColumn string // Column filtered by the created range
+	sortColumns   map[string]sortColumn
+	defaultSort   string
+	defaultDesc   bool
+}
+
+// pageCursor is the decoded form of ListParams.Cursor
+type pageCursor struct {
+	Sort  string `json:"s"`           // Sort key the cursor was issued for
+	Desc  bool   `json:"d,omitempty"` // Sort direction the cursor was issued for
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func encodeCursor(c pageCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (*pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// timestamptzLayouts are the text forms Postgres gives timestamptz values in the default ISO date style
+var timestamptzLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07:00:00",
+	time.RFC3339Nano,
+}
+
+// numericPattern matches the decimal text form of numeric values
+var numericPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// validCursorValue reports whether a cursor value can be cast to the Postgres type of its sort column
+func validCursorValue(cast, value string) bool {
+	switch cast {
+	case "timestamptz":
+		for _, layout := range timestamptzLayouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+		return false
+	case "integer":
+		_, err := strconv.ParseInt(value, 10, 32)
+		return err == nil
+	case "smallint":
+		_, err := strconv.ParseInt(value, 10, 16)
+		return err == nil
+	case "numeric":
+		return numericPattern.MatchString(value)
+	case "text":
+		return true
+	}
+	return false
+}
+
+// decodePageCursor decodes a cursor and checks that it was issued for the sort key and direction and that its
+// values cast to the sort column's and the id's types, so a bad cursor is rejected before reaching the database
+func decodePageCursor(s, sortBy string, desc bool, column sortColumn) (*pageCursor, error) {
+	cursor, err := decodeCursor(s)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.Sort != sortBy || cursor.Desc != desc || !validCursorValue(column.cast, cursor.Value) || uuid.Validate(cursor.ID) != nil {
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// listQuery collects WHERE conditions and their positional arguments
+type listQuery struct {
+	conds []string
+	args  []any
+}
+
+// where adds a condition, each %d in cond is replaced by the next placeholder number
+func (q *listQuery) where(cond string, args ...any) {
+	nums := make([]any, len(args))
+	for i := range args {
+		nums[i] = len(q.args) + i + 1
+	}
+	q.conds = append(q.conds, fmt.Sprintf(cond, nums...))
+	q.args = append(q.args, args...)
+}
+
+func (q *listQuery) whereClause() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// applyCommonFilters adds the filters every list supports
+func (q *listQuery) applyCommonFilters(spec listSpec, params model.ListParams) {
+	if !params.CreatedFrom.IsZero() {
+		q.where(spec.createdColumn+" >= $%d", params.CreatedFrom)
+	}
+	if !params.CreatedTo.IsZero() {
+		q.where(spec.createdColumn+" < $%d", params.CreatedTo)
+	}
+}
+
+// queryPage runs the count and page queries for a list and returns one page of items.
+// scan must scan the columns of spec.selectClause followed by the two cursor columns.
+func queryPage[T any](db *sql.DB, spec listSpec, params model.ListParams, q listQuery, scan func(rows *sql.Rows, cursor *pageCursor) (T, error)) ([]T, *model.PageInfo, error) {
+	sortBy, desc := spec.defaultSort, spec.defaultDesc
+	if params.SortBy != "" {
+		sortBy, desc = params.SortBy, params.SortDesc
+	}
+	column, ok := spec.sortColumns[sortBy]
+	if !ok {
+		return nil, nil, ErrInvalidSort
+	}
+
+	var cursor *pageCursor
+	if params.Cursor != "" {
+		var err error
+		if cursor, err = decodePageCursor(params.Cursor, sortBy, desc, column); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Count every matching row before the cursor narrows the result
+	info := &model.PageInfo{}
+	countQuery := "SELECT COUNT(*) " + spec.fromClause + q.whereClause()
+	if err := db.QueryRow(countQuery, q.args...).Scan(&info.Total); err != nil {
+		return nil, nil, err
+	}
+
+	dir, cmp := "ASC", ">"
+	if desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	if cursor != nil {
+		q.where(fmt.Sprintf("(%s, %s) %s ($%%d::%s, $%%d::uuid)", column.expr, spec.idColumn, cmp, column.cast), cursor.Value, cursor.ID)
+	}
+
+	pageQuery := fmt.Sprintf("%s, (%s)::text, %s::text %s%s ORDER BY %s %s, %s %s",
+		spec.selectClause, column.expr, spec.idColumn, spec.fromClause, q.whereClause(),
+		column.expr, dir, spec.idColumn, dir)
+	if params.Limit > 0 {
+		pageQuery += fmt.Sprintf(" LIMIT %d", params.Limit+1)
+	}
+
+	rows, err := db.Query(pageQuery, q.args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var items []T
+	var last pageCursor
+	for rows.Next() {
+		cursor := pageCursor{Sort: sortBy, Desc: desc}
+		item, err := scan(rows, &cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		// The extra row only tells us that another page exists
+		if params.Limit > 0 && len(items) == params.Limit {
+			info.NextCursor = encodeCursor(last)
+			break
+		}
+		items = append(items, item)
+		last = cursor
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return items, info, nil
+}
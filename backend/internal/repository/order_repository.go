@@ -0,0 +1,486 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+var (
+	// ErrPaymentRequired is returned when a student tries to enroll in a paid course without buying it
+	ErrPaymentRequired = errors.New("this course must be bought before enrolling")
+	ErrFreeCourse      = errors.New("this course is free, enroll directly instead")
+	// ErrPaidCourseMode is returned when a course would be paid while students join it by approval or access code
+	ErrPaidCourseMode = errors.New("a paid course must use open enrollment")
+	// ErrCourseFull is returned at checkout, paid seats are never sold into the waitlist
+	ErrCourseFull = errors.New("this course is full")
+	// ErrInvalidCoupon is returned for unknown or expired coupons and coupons that do not apply to the course
+	ErrInvalidCoupon = errors.New("invalid or expired coupon")
+	// ErrCouponUsedUp is returned when a coupon reached its usage cap or the student's limit
+	ErrCouponUsedUp = errors.New("coupon has been used up")
+)
+
+type OrderRepository struct {
+	DB *sql.DB
+}
+
+func NewOrderRepository(db *sql.DB) *OrderRepository {
+	return &OrderRepository{DB: db}
+}
+
+const orderColumns = `o.id, o.user_id, o.course_id, c.title, o.cohort_id, o.amount_cents, o.discount_cents, o.coupon_id,
+	o.currency, o.status, o.provider, o.provider_ref, o.checkout_url, o.paid_at, o.created_at, o.updated_at`
+
+func scanOrder(row interface{ Scan(...any) error }, order *model.Order) error {
+	return row.Scan(
+		&order.ID, &order.UserID, &order.CourseID, &order.CourseTitle, &order.CohortID, &order.AmountCents,
+		&order.DiscountCents, &order.CouponID, &order.Currency, &order.Status, &order.Provider, &order.ProviderRef,
+		&order.CheckoutURL, &order.PaidAt, &order.CreatedAt, &order.UpdatedAt,
+	)
+}
+
+// applyCoupon looks up the coupon with code for the student's order of a course locked with lockCourseSeats
+// and returns its ID and the discount off the course price. Usage caps count paid orders only,
+// and are checked again by redeemCoupon when the order is paid.
+func applyCoupon(tx *sql.Tx, seats courseSeats, studentID, courseID, code string) (string, int, error) {
+	var couponID, discountType, currency string
+	var value, uses int
+	var maxUses, perUserLimit sql.NullInt64
+	query := `SELECT id, discount_type, discount_value, COALESCE(currency, ''), max_uses, per_user_limit, uses FROM coupons
+	           WHERE code = $1 AND (course_id IS NULL OR course_id = $2)
+	             AND (starts_at IS NULL OR starts_at <= NOW()) AND (ends_at IS NULL OR ends_at > NOW())`
+	err := tx.QueryRow(query, code, courseID).Scan(&couponID, &discountType, &value, &currency, &maxUses, &perUserLimit, &uses)
+	if err == sql.ErrNoRows {
+		return "", 0, ErrInvalidCoupon
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	if maxUses.Valid && int64(uses) >= maxUses.Int64 {
+		return "", 0, ErrCouponUsedUp
+	}
+	if perUserLimit.Valid {
+		var redeemed int64
+		countQuery := `SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND user_id = $2`
+		if err := tx.QueryRow(countQuery, couponID, studentID).Scan(&redeemed); err != nil {
+			return "", 0, err
+		}
+		if redeemed >= perUserLimit.Int64 {
+			return "", 0, ErrCouponUsedUp
+		}
+	}
+
+	if discountType == model.CouponPercent {
+		return couponID, seats.priceCents * value / 100, nil
+	}
+	if currency != seats.currency {
+		return "", 0, ErrInvalidCoupon // A fixed discount only applies in its own currency
+	}
+	return couponID, min(value, seats.priceCents), nil
+}
+
+// fulfillOrder marks an order paid, enrolls the student and redeems its coupon.
+// The course row must already be locked with lockCourseSeats. charged tells whether the student paid for the order.
+func fulfillOrder(tx *sql.Tx, orderID, studentID, courseID, cohortID string, charged bool) error {
+	if _, err := tx.Exec(`UPDATE orders SET status = 'paid', paid_at = NOW(), updated_at = NOW() WHERE id = $1`, orderID); err != nil {
+		log.Printf("Error marking order paid: %v", err)
+		return err
+	}
+
+	if err := redeemCoupon(tx, orderID, studentID, charged); err != nil {
+		return err
+	}
+
+	// The seat was checked at checkout, a paid student is enrolled even if the course filled up since
+	enrollQuery := `INSERT INTO enrollments (user_id, course_id, cohort_id) VALUES ($1, $2, NULLIF($3, '')::uuid)
+	                 ON CONFLICT DO NOTHING`
+	result, err := tx.Exec(enrollQuery, studentID, courseID, cohortID)
+	if err != nil {
+		log.Printf("Error enrolling paid student: %v", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM course_waitlist WHERE course_id = $1 AND user_id = $2`, courseID, studentID); err != nil {
+		return err
+	}
+	return recordEnrollment(tx, courseID, studentID, cohortID, model.EnrollmentActionEnrolled, studentID)
+}
+
+// redeemCoupon records the use of the order's coupon, once per order. The usage caps were checked when the
+// order was opened, and other orders may have used the coupon up since, so they are checked again here.
+// An order over a cap is refused with ErrCouponUsedUp when nothing was charged for it. A student who already
+// paid the discounted price keeps the course, and the use is counted past the cap.
+func redeemCoupon(tx *sql.Tx, orderID, studentID string, charged bool) error {
+	var couponID sql.NullString
+	var redeemed bool
+	query := `SELECT coupon_id, EXISTS(SELECT 1 FROM coupon_redemptions WHERE order_id = $1) FROM orders WHERE id = $1`
+	if err := tx.QueryRow(query, orderID).Scan(&couponID, &redeemed); err != nil {
+		return err
+	}
+	if !couponID.Valid || redeemed {
+		return nil
+	}
+
+	// Updating the coupon row locks it, so concurrent orders with the same coupon are counted one at a time
+	capQuery := `UPDATE coupons SET uses = uses + 1
+	              WHERE id = $1 AND (max_uses IS NULL OR uses < max_uses)
+	                AND (per_user_limit IS NULL OR per_user_limit > (SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND user_id = $2))`
+	result, err := tx.Exec(capQuery, couponID.String, studentID)
+	if err != nil {
+		log.Printf("Error redeeming coupon: %v", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if !charged {
+			return ErrCouponUsedUp
+		}
+		log.Printf("Coupon %s was redeemed past its usage cap by paid order %s", couponID.String, orderID)
+		if _, err := tx.Exec(`UPDATE coupons SET uses = uses + 1 WHERE id = $1`, couponID.String); err != nil {
+			return err
+		}
+	}
+
+	redeemQuery := `INSERT INTO coupon_redemptions (coupon_id, order_id, user_id, discount_cents)
+	                 SELECT coupon_id, id, user_id, discount_cents FROM orders WHERE id = $1`
+	if _, err := tx.Exec(redeemQuery, orderID); err != nil {
+		log.Printf("Error redeeming coupon: %v", err)
+		return err
+	}
+	return nil
+}
+
+// CreateOrder opens a pending order for the student at the course's current price, less the discount
+// of couponCode when one is given. A pending order for the same course, cohort, price and coupon is
+// returned instead of a new one, so retrying checkout does not pile up orders. An order discounted to
+// nothing is paid and enrolls the student right away, or fails with ErrCouponUsedUp when its coupon was used up
+// by orders paid in the meantime. It returns sql.ErrNoRows for unknown or draft courses.
+func (r *OrderRepository) CreateOrder(studentID, courseID, cohortID, couponCode string) (*model.Order, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Only open courses are sold, approval and access codes stay the way to get in for free
+	seats, err := lockEnrollableCourse(tx, courseID, model.EnrollmentModeOpen)
+	if err != nil {
+		return nil, err
+	}
+	if seats.priceCents == 0 {
+		return nil, ErrFreeCourse
+	}
+
+	enrolled, err := isEnrolledTx(tx, studentID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		return nil, ErrAlreadyEnrolled
+	}
+
+	if err := checkCohortEnrollment(tx, courseID, cohortID, true); err != nil {
+		return nil, err
+	}
+
+	if seats.capacity.Valid {
+		var taken int64
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM enrollments WHERE course_id = $1`, courseID).Scan(&taken); err != nil {
+			return nil, err
+		}
+		if taken >= seats.capacity.Int64 {
+			return nil, ErrCourseFull
+		}
+	}
+
+	var couponID string
+	discount := 0
+	if couponCode != "" {
+		if couponID, discount, err = applyCoupon(tx, seats, studentID, courseID, couponCode); err != nil {
+			return nil, err
+		}
+	}
+	amount := seats.priceCents - discount
+
+	order := &model.Order{}
+	pendingQuery := `SELECT ` + orderColumns + ` FROM orders o JOIN courses c ON c.id = o.course_id
+	                  WHERE o.user_id = $1 AND o.course_id = $2 AND o.status = 'pending'`
+	err = scanOrder(tx.QueryRow(pendingQuery, studentID, courseID), order)
+	switch {
+	case err == nil:
+		sameCohort := (order.CohortID == nil && cohortID == "") || (order.CohortID != nil && *order.CohortID == cohortID)
+		sameCoupon := (order.CouponID == nil && couponID == "") || (order.CouponID != nil && *order.CouponID == couponID)
+		if sameCohort && sameCoupon && order.AmountCents == amount && order.Currency == seats.currency {
+			return order, tx.Commit()
+		}
+		// The price, coupon or cohort changed since, the old order is given up on
+		if _, err := tx.Exec(`UPDATE orders SET status = 'failed', updated_at = NOW() WHERE id = $1`, order.ID); err != nil {
+			return nil, err
+		}
+	case err != sql.ErrNoRows:
+		return nil, err
+	}
+
+	order = &model.Order{}
+	query := `
+		WITH o AS (
+			INSERT INTO orders (user_id, course_id, cohort_id, amount_cents, discount_cents, coupon_id, currency)
+			VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, NULLIF($6, '')::uuid, $7)
+			RETURNING *
+		)
+		SELECT ` + orderColumns + ` FROM o JOIN courses c ON c.id = o.course_id`
+	row := tx.QueryRow(query, studentID, courseID, cohortID, amount, discount, couponID, seats.currency)
+	if err := scanOrder(row, order); err != nil {
+		log.Printf("Error creating order: %v", err)
+		return nil, err
+	}
+
+	if amount == 0 {
+		if err := fulfillOrder(tx, order.ID, studentID, courseID, cohortID, false); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		order.Status, order.PaidAt = model.OrderStatusPaid, &now
+	}
+
+	return order, tx.Commit()
+}
+
+// SetOrderCheckout stores the provider's checkout session of a pending order
+func (r *OrderRepository) SetOrderCheckout(orderID, provider, reference, checkoutURL string) error {
+	query := `UPDATE orders SET provider = $2, provider_ref = $3, checkout_url = $4, updated_at = NOW()
+	           WHERE id = $1 AND status = 'pending'`
+	result, err := r.DB.Exec(query, orderID, provider, reference, checkoutURL)
+	if err != nil {
+		log.Printf("Error saving checkout: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// FailOrder marks a pending order as failed
+func (r *OrderRepository) FailOrder(orderID string) error {
+	_, err := r.DB.Exec(`UPDATE orders SET status = 'failed', updated_at = NOW() WHERE id = $1 AND status = 'pending'`, orderID)
+	return err
+}
+
+// GetOrdersByUserID retrieves the orders of a student, newest first
+func (r *OrderRepository) GetOrdersByUserID(userID string) ([]model.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders o JOIN courses c ON c.id = o.course_id
+	           WHERE o.user_id = $1 ORDER BY o.created_at DESC`
+	rows, err := r.DB.Query(query, userID)
+	if err != nil {
+		log.Printf("Error fetching orders: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []model.Order{}
+	for rows.Next() {
+		var order model.Order
+		if err := scanOrder(rows, &order); err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+// ProcessPaymentEvent applies a webhook event of the provider to the order of the checkout reference.
+// A successful payment marks the order paid and enrolls the student, a failed one marks a pending
+// order failed. Each event is applied once: retried deliveries of an event, and any event for an
+// order that is already paid, change nothing. It returns sql.ErrNoRows when no order has the reference.
+func (r *OrderRepository) ProcessPaymentEvent(provider, eventID, reference string, paid bool) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// A concurrent delivery of the same event waits here until the first one commits
+	result, err := tx.Exec(`INSERT INTO payment_events (provider, event_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, provider, eventID)
+	if err != nil {
+		log.Printf("Error recording payment event: %v", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return nil // Already processed
+	}
+
+	// Lock the course before the order, in the same order as CreateOrder, so the two cannot deadlock
+	var courseID string
+	if err := tx.QueryRow(`SELECT course_id FROM orders WHERE provider = $1 AND provider_ref = $2`, provider, reference).Scan(&courseID); err != nil {
+		return err // sql.ErrNoRows rolls the event back, so a later retry can still match the order
+	}
+	if _, err := lockCourseSeats(tx, courseID); err != nil {
+		return err
+	}
+
+	var orderID, studentID, cohortID, status string
+	query := `SELECT id, user_id, COALESCE(cohort_id::text, ''), status FROM orders WHERE provider = $1 AND provider_ref = $2 FOR UPDATE`
+	if err := tx.QueryRow(query, provider, reference).Scan(&orderID, &studentID, &cohortID, &status); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE payment_events SET order_id = $3 WHERE provider = $1 AND event_id = $2`, provider, eventID, orderID); err != nil {
+		return err
+	}
+
+	if status == model.OrderStatusPaid {
+		return tx.Commit()
+	}
+	if !paid {
+		if status == model.OrderStatusPending {
+			if _, err := tx.Exec(`UPDATE orders SET status = 'failed', updated_at = NOW() WHERE id = $1`, orderID); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}
+
+	// A payment that still succeeds after the order was given up on is honored rather than lost
+	if err := fulfillOrder(tx, orderID, studentID, courseID, cohortID, true); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const couponColumns = `id, code, discount_type, discount_value, currency, course_id, max_uses, per_user_limit,
+	uses, starts_at, ends_at, created_by, created_at`
+
+func scanCoupon(row interface{ Scan(...any) error }, coupon *model.Coupon) error {
+	return row.Scan(
+		&coupon.ID, &coupon.Code, &coupon.DiscountType, &coupon.DiscountValue, &coupon.Currency, &coupon.CourseID,
+		&coupon.MaxUses, &coupon.PerUserLimit, &coupon.Uses, &coupon.StartsAt, &coupon.EndsAt, &coupon.CreatedBy,
+		&coupon.CreatedAt,
+	)
+}
+
+// CreateCoupon stores a new coupon. A code that is already taken fails with a unique constraint violation.
+func (r *OrderRepository) CreateCoupon(coupon *model.Coupon) error {
+	query := `INSERT INTO coupons (code, discount_type, discount_value, currency, course_id, max_uses, per_user_limit, starts_at, ends_at, created_by)
+	           VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	           RETURNING id, uses, created_at`
+	err := r.DB.QueryRow(query, coupon.Code, coupon.DiscountType, coupon.DiscountValue, coupon.Currency, coupon.CourseID,
+		coupon.MaxUses, coupon.PerUserLimit, coupon.StartsAt, coupon.EndsAt, coupon.CreatedBy).
+		Scan(&coupon.ID, &coupon.Uses, &coupon.CreatedAt)
+	if err != nil {
+		log.Printf("Error creating coupon: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetCouponByID retrieves a coupon, or nil when it does not exist
+func (r *OrderRepository) GetCouponByID(couponID string) (*model.Coupon, error) {
+	var coupon model.Coupon
+	err := scanCoupon(r.DB.QueryRow(`SELECT `+couponColumns+` FROM coupons WHERE id = $1`, couponID), &coupon)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// GetCoupons retrieves the coupons of a course, or every coupon when courseID is empty, newest first
+func (r *OrderRepository) GetCoupons(courseID string) ([]model.Coupon, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE $1 = '' OR course_id::text = $1 ORDER BY created_at DESC`
+	rows, err := r.DB.Query(query, courseID)
+	if err != nil {
+		log.Printf("Error fetching coupons: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	coupons := []model.Coupon{}
+	for rows.Next() {
+		var coupon model.Coupon
+		if err := scanCoupon(rows, &coupon); err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, coupon)
+	}
+	return coupons, rows.Err()
+}
+
+// DeleteCoupon deletes a coupon that was never redeemed. A redeemed coupon is kept for the
+// redemption report and ends now instead, which is reported by returning true.
+func (r *OrderRepository) DeleteCoupon(couponID string) (bool, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var redeemed bool
+	if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM coupon_redemptions WHERE coupon_id = $1)`, couponID).Scan(&redeemed); err != nil {
+		return false, err
+	}
+
+	query := `DELETE FROM coupons WHERE id = $1`
+	if redeemed {
+		query = `UPDATE coupons SET ends_at = NOW() WHERE id = $1 AND (ends_at IS NULL OR ends_at > NOW())`
+	}
+	if _, err := tx.Exec(query, couponID); err != nil {
+		log.Printf("Error deleting coupon: %v", err)
+		return false, err
+	}
+
+	return redeemed, tx.Commit()
+}
+
+// GetCouponRedemptions retrieves the paid orders that used a coupon, newest first
+func (r *OrderRepository) GetCouponRedemptions(couponID string) ([]model.CouponRedemption, error) {
+	query := `
+		SELECT cr.id, cr.coupon_id, cr.order_id, cr.user_id, u.full_name, u.email, o.course_id, c.title,
+		       cr.discount_cents, o.amount_cents, o.currency, cr.redeemed_at
+		FROM coupon_redemptions cr
+		JOIN orders o ON o.id = cr.order_id
+		JOIN users u ON u.id = cr.user_id
+		JOIN courses c ON c.id = o.course_id
+		WHERE cr.coupon_id = $1
+		ORDER BY cr.redeemed_at DESC`
+	rows, err := r.DB.Query(query, couponID)
+	if err != nil {
+		log.Printf("Error fetching coupon redemptions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	redemptions := []model.CouponRedemption{}
+	for rows.Next() {
+		var rd model.CouponRedemption
+		if err := rows.Scan(&rd.ID, &rd.CouponID, &rd.OrderID, &rd.UserID, &rd.FullName, &rd.Email, &rd.CourseID,
+			&rd.CourseTitle, &rd.DiscountCents, &rd.AmountCents, &rd.Currency, &rd.RedeemedAt); err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, rd)
+	}
+	return redemptions, rows.Err()
+}
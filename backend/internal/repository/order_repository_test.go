@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+var orderTestColumns = []string{"id", "user_id", "course_id", "title", "cohort_id", "amount_cents", "discount_cents", "coupon_id",
+	"currency", "status", "provider", "provider_ref", "checkout_url", "paid_at", "created_at", "updated_at"}
+
+func TestEnrollStudentRequiresPayment(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(nil, false, "open", 4900, "USD"))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	_, err = repo.EnrollStudent("student-1", "course-1", "")
+
+	// Check the result (Assert)
+	if !errors.Is(err, ErrPaymentRequired) {
+		t.Errorf("expected ErrPaymentRequired, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestProcessPaymentEventEnrollsOnce(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+	insertEvent := regexp.QuoteMeta(`INSERT INTO payment_events (provider, event_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`)
+
+	// The first delivery marks the order paid and enrolls the student
+	mock.ExpectBegin()
+	mock.ExpectExec(insertEvent).WithArgs("fake", "evt_1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT course_id FROM orders WHERE provider = $1 AND provider_ref = $2`)).WithArgs("fake", "fake_order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"course_id"}).AddRow("course-1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(30, false, "open", 4900, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, COALESCE(cohort_id::text, ''), status FROM orders WHERE provider = $1 AND provider_ref = $2 FOR UPDATE`)).WithArgs("fake", "fake_order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "cohort_id", "status"}).AddRow("order-1", "student-1", "", "pending"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE payment_events SET order_id = $3`)).WithArgs("fake", "evt_1", "order-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET status = 'paid'`)).WithArgs("order-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT coupon_id, EXISTS(SELECT 1 FROM coupon_redemptions WHERE order_id = $1) FROM orders WHERE id = $1`)).WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"coupon_id", "exists"}).AddRow(nil, false))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO enrollments (user_id, course_id, cohort_id) VALUES ($1, $2, NULLIF($3, '')::uuid) ON CONFLICT DO NOTHING`)).
+		WithArgs("student-1", "course-1", "").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM course_waitlist WHERE course_id = $1 AND user_id = $2`)).WithArgs("course-1", "student-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO enrollment_history`)).WithArgs("course-1", "student-1", "", "enrolled", "student-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// A retried delivery of the same event is acknowledged without touching the order
+	mock.ExpectBegin()
+	mock.ExpectExec(insertEvent).WithArgs("fake", "evt_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	for i := 0; i < 2; i++ {
+		if err := repo.ProcessPaymentEvent("fake", "evt_1", "fake_order-1", true); err != nil {
+			t.Fatalf("delivery %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCreateOrderRejectsUsedUpCoupon(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(nil, false, "open", 4900, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2)`)).WithArgs("student-1", "course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM course_cohorts WHERE course_id = $1)`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, discount_type, discount_value, COALESCE(currency, ''), max_uses, per_user_limit, uses FROM coupons`)).WithArgs("SPRING25", "course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "discount_type", "discount_value", "currency", "max_uses", "per_user_limit", "uses"}).
+			AddRow("coupon-1", "percent", 25, "", nil, 1, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND user_id = $2`)).WithArgs("coupon-1", "student-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	_, err = repo.CreateOrder("student-1", "course-1", "", "SPRING25")
+
+	// Check the result (Assert), the student already used their one redemption
+	if !errors.Is(err, ErrCouponUsedUp) {
+		t.Errorf("expected ErrCouponUsedUp, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCreateOrderFulfillsFullyDiscountedOrder(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(nil, false, "open", 4900, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2)`)).WithArgs("student-1", "course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM course_cohorts WHERE course_id = $1)`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, discount_type, discount_value, COALESCE(currency, ''), max_uses, per_user_limit, uses FROM coupons`)).WithArgs("FREE", "course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "discount_type", "discount_value", "currency", "max_uses", "per_user_limit", "uses"}).
+			AddRow("coupon-1", "percent", 100, "", nil, nil, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM orders o JOIN courses c ON c.id = o.course_id WHERE o.user_id = $1 AND o.course_id = $2 AND o.status = 'pending'`)).
+		WithArgs("student-1", "course-1").WillReturnRows(sqlmock.NewRows(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO orders`)).WithArgs("student-1", "course-1", "", 0, 4900, "coupon-1", "USD").
+		WillReturnRows(sqlmock.NewRows(orderTestColumns).
+			AddRow("order-1", "student-1", "course-1", "Go", nil, 0, 4900, "coupon-1", "USD", "pending", nil, nil, nil, nil, time.Now(), time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET status = 'paid'`)).WithArgs("order-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT coupon_id, EXISTS(SELECT 1 FROM coupon_redemptions WHERE order_id = $1) FROM orders WHERE id = $1`)).WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"coupon_id", "exists"}).AddRow("coupon-1", false))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE coupons SET uses = uses + 1 WHERE id = $1 AND (max_uses IS NULL OR uses < max_uses)`)).WithArgs("coupon-1", "student-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO coupon_redemptions`)).WithArgs("order-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO enrollments (user_id, course_id, cohort_id) VALUES ($1, $2, NULLIF($3, '')::uuid) ON CONFLICT DO NOTHING`)).
+		WithArgs("student-1", "course-1", "").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM course_waitlist WHERE course_id = $1 AND user_id = $2`)).WithArgs("course-1", "student-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO enrollment_history`)).WithArgs("course-1", "student-1", "", "enrolled", "student-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Run the function that will be tested
+	order, err := repo.CreateOrder("student-1", "course-1", "", "FREE")
+
+	// Check the result (Assert), the order is paid without a checkout
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Status != model.OrderStatusPaid || order.PaidAt == nil || order.CheckoutURL != nil {
+		t.Errorf("expected a paid order without checkout, but got %+v", order)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCreateOrderRechecksCouponCapWhenFulfilling(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	// The coupon had a use left when it was looked up, another order took it before this one was redeemed
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(nil, false, "open", 4900, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM enrollments WHERE user_id = $1 AND course_id = $2)`)).WithArgs("student-1", "course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM course_cohorts WHERE course_id = $1)`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, discount_type, discount_value, COALESCE(currency, ''), max_uses, per_user_limit, uses FROM coupons`)).WithArgs("FREE", "course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "discount_type", "discount_value", "currency", "max_uses", "per_user_limit", "uses"}).
+			AddRow("coupon-1", "percent", 100, "", 10, nil, 9))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM orders o JOIN courses c ON c.id = o.course_id WHERE o.user_id = $1 AND o.course_id = $2 AND o.status = 'pending'`)).
+		WithArgs("student-1", "course-1").WillReturnRows(sqlmock.NewRows(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO orders`)).WithArgs("student-1", "course-1", "", 0, 4900, "coupon-1", "USD").
+		WillReturnRows(sqlmock.NewRows(orderTestColumns).
+			AddRow("order-1", "student-1", "course-1", "Go", nil, 0, 4900, "coupon-1", "USD", "pending", nil, nil, nil, nil, time.Now(), time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET status = 'paid'`)).WithArgs("order-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT coupon_id, EXISTS(SELECT 1 FROM coupon_redemptions WHERE order_id = $1) FROM orders WHERE id = $1`)).WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"coupon_id", "exists"}).AddRow("coupon-1", false))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE coupons SET uses = uses + 1 WHERE id = $1 AND (max_uses IS NULL OR uses < max_uses)`)).WithArgs("coupon-1", "student-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	_, err = repo.CreateOrder("student-1", "course-1", "", "FREE")
+
+	// Check the result (Assert), nothing was charged so the order is refused
+	if !errors.Is(err, ErrCouponUsedUp) {
+		t.Errorf("expected ErrCouponUsedUp, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSetCoursePriceRequiresOpenMode(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	// The course takes students by access code, so the price is refused
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE courses SET price_cents = $1, currency = $2, updated_at = NOW() WHERE id = $3 AND ($1 = 0 OR enrollment_mode = 'open')`)).
+		WithArgs(4900, "USD", "course-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	// Run the function that will be tested
+	err = repo.SetCoursePrice("course-1", 4900, "USD")
+
+	// Check the result (Assert)
+	if !errors.Is(err, ErrPaidCourseMode) {
+		t.Errorf("expected ErrPaidCourseMode, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRedeemAccessCodeRequiresPayment(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	// A course priced before paid courses had to be open still cannot be joined with a code
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(nil, false, "code", 4900, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE enrollment_codes SET uses = uses + 1`)).WithArgs("course-1", "K7P4QX2M").
+		WillReturnRows(sqlmock.NewRows([]string{"cohort_id"}).AddRow(""))
+	mock.ExpectRollback()
+
+	// Run the function that will be tested
+	_, err = repo.RedeemAccessCode("student-1", "course-1", "K7P4QX2M", "")
+
+	// Check the result (Assert), the rollback also gives the use of the code back
+	if !errors.Is(err, ErrPaymentRequired) {
+		t.Errorf("expected ErrPaymentRequired, but got %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUnenrollDoesNotPromoteIntoPaidCourse(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCourseRepository(db)
+
+	// The freed seat of a paid course is sold through checkout rather than handed to the waitlist
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT capacity, is_draft, enrollment_mode, price_cents, currency FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "is_draft", "enrollment_mode", "price_cents", "currency"}).AddRow(30, false, "open", 4900, "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM enrollments WHERE user_id = $1 AND course_id = $2 RETURNING COALESCE(cohort_id::text, '')`)).
+		WithArgs("student-1", "course-1").WillReturnRows(sqlmock.NewRows([]string{"cohort_id"}).AddRow(""))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO enrollment_history (course_id, user_id, cohort_id, action, actor_id)`)).
+		WithArgs("course-1", "student-1", "", "left", "student-1").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Run the function that will be tested
+	if err := repo.Unenroll("student-1", "student-1", "course-1", "left"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+type ReviewRepository struct {
+	DB *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) *ReviewRepository {
+	return &ReviewRepository{DB: db}
+}
+
+// reviewListSpec describes how the reviews of a course are paginated, sorted and filtered
+var reviewListSpec = listSpec{
+	selectClause: `SELECT r.id, r.course_id, r.user_id, u.full_name, r.rating, r.body, r.reply, r.replied_by, r.replied_at,
+	                      r.is_hidden, r.created_at, r.updated_at`,
+	fromClause:    `FROM course_reviews r JOIN users u ON u.id = r.user_id`,
+	idColumn:      "r.id",
+	createdColumn: "r.created_at",
+	sortColumns: map[string]sortColumn{
+		"created_at": {expr: "r.created_at", cast: "timestamptz"},
+		"rating":     {expr: "r.rating", cast: "smallint"},
+	},
+	defaultSort: "created_at",
+	defaultDesc: true,
+}
+
+// GetReviews retrieves a page of the reviews of a course, including hidden ones when includeHidden is set
+func (r *ReviewRepository) GetReviews(courseID string, includeHidden bool, params model.ListParams) ([]model.Review, *model.PageInfo, error) {
+	var q listQuery
+	q.where("r.course_id = $%d", courseID)
+	if !includeHidden {
+		q.where("r.is_hidden = FALSE")
+	}
+	q.applyCommonFilters(reviewListSpec, params)
+
+	return queryPage(r.DB, reviewListSpec, params, q, func(rows *sql.Rows, cursor *pageCursor) (model.Review, error) {
+		var review model.Review
+		err := rows.Scan(&review.ID, &review.CourseID, &review.UserID, &review.FullName, &review.Rating, &review.Body,
+			&review.Reply, &review.RepliedBy, &review.RepliedAt, &review.IsHidden, &review.CreatedAt, &review.UpdatedAt,
+			&cursor.Value, &cursor.ID)
+		return review, err
+	})
+}
+
+// lockCourseRating locks the course row so concurrent review changes refresh its rating one at a time
+func lockCourseRating(tx *sql.Tx, courseID string) error {
+	var id string
+	return tx.QueryRow(`SELECT id FROM courses WHERE id = $1 FOR UPDATE`, courseID).Scan(&id)
+}
+
+// refreshCourseRating recomputes the rating aggregate of a course locked with lockCourseRating
+func refreshCourseRating(tx *sql.Tx, courseID string) error {
+	query := `
+		UPDATE courses SET rating_average = s.average, rating_count = s.count
+		FROM (
+			SELECT COALESCE(ROUND(AVG(rating), 2), 0) AS average, COUNT(*) AS count
+			FROM course_reviews WHERE course_id = $1 AND is_hidden = FALSE
+		) s
+		WHERE id = $1`
+	if _, err := tx.Exec(query, courseID); err != nil {
+		log.Printf("Error refreshing course rating: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SaveReview creates the student's review of a course, or updates its rating and text when one exists.
+// A reply and the hidden state of an existing review are kept.
+func (r *ReviewRepository) SaveReview(review *model.Review) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCourseRating(tx, review.CourseID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO course_reviews (course_id, user_id, rating, body) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (course_id, user_id) DO UPDATE SET rating = EXCLUDED.rating, body = EXCLUDED.body, updated_at = NOW()
+		RETURNING id, reply, replied_by, replied_at, is_hidden, created_at, updated_at`
+	err = tx.QueryRow(query, review.CourseID, review.UserID, review.Rating, review.Body).Scan(
+		&review.ID, &review.Reply, &review.RepliedBy, &review.RepliedAt, &review.IsHidden, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		log.Printf("Error saving review: %v", err)
+		return err
+	}
+
+	if err := refreshCourseRating(tx, review.CourseID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteReview deletes the student's review of a course.
+// It returns sql.ErrNoRows when the student has not reviewed the course.
+func (r *ReviewRepository) DeleteReview(courseID, userID string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCourseRating(tx, courseID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM course_reviews WHERE course_id = $1 AND user_id = $2`, courseID, userID)
+	if err != nil {
+		log.Printf("Error deleting review: %v", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := refreshCourseRating(tx, courseID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplyToReview sets the course staff's public reply to a review, replacing an earlier reply.
+// It returns sql.ErrNoRows when the review does not belong to the course.
+func (r *ReviewRepository) ReplyToReview(courseID, reviewID, actorID, reply string) error {
+	query := `UPDATE course_reviews SET reply = $1, replied_by = $2, replied_at = NOW()
+	           WHERE id = $3 AND course_id = $4`
+	result, err := r.DB.Exec(query, reply, actorID, reviewID, courseID)
+	if err != nil {
+		log.Printf("Error replying to review: %v", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetReviewHidden hides a review from listings and the course rating, or shows it again.
+// It returns sql.ErrNoRows when the review does not exist.
+func (r *ReviewRepository) SetReviewHidden(reviewID, actorID string, hidden bool) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var courseID string
+	if err := tx.QueryRow(`SELECT course_id FROM course_reviews WHERE id = $1`, reviewID).Scan(&courseID); err != nil {
+		return err
+	}
+	if err := lockCourseRating(tx, courseID); err != nil {
+		return err
+	}
+
+	query := `UPDATE course_reviews SET is_hidden = $1, hidden_by = CASE WHEN $1 THEN $2::uuid END WHERE id = $3`
+	if _, err := tx.Exec(query, hidden, actorID, reviewID); err != nil {
+		log.Printf("Error hiding review: %v", err)
+		return err
+	}
+
+	if err := refreshCourseRating(tx, courseID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
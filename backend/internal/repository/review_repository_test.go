@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+func TestSaveReviewRefreshesRating(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReviewRepository(db)
+	review := &model.Review{CourseID: "course-1", UserID: "student-1", Rating: 4, Body: "Good course"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM courses WHERE id = $1 FOR UPDATE`)).WithArgs("course-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("course-1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO course_reviews (course_id, user_id, rating, body) VALUES ($1, $2, $3, $4) ON CONFLICT (course_id, user_id) DO UPDATE`)).
+		WithArgs("course-1", "student-1", 4, "Good course").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reply", "replied_by", "replied_at", "is_hidden", "created_at", "updated_at"}).
+			AddRow("review-1", nil, nil, nil, false, time.Now(), time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE courses SET rating_average = s.average, rating_count = s.count`)).WithArgs("course-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Run the function that will be tested
+	err = repo.SaveReview(review)
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Errorf("error was not expected while saving review: %s", err)
+	}
+	if review.ID != "review-1" {
+		t.Errorf("expected review ID to be set, but got %q", review.ID)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
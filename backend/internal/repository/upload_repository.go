@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/dimasrizkyfebrian/coursify/internal/model"
+)
+
+type UploadRepository struct {
+	DB *sql.DB
+}
+
+func NewUploadRepository(db *sql.DB) *UploadRepository {
+	return &UploadRepository{DB: db}
+}
+
+const uploadColumns = `id, user_id, course_id, kind, title, filename, filetype, upload_length, upload_offset,
+	file_url, material_id, expires_at, completed_at, created_at`
+
+func scanUpload(row rowScanner) (model.Upload, error) {
+	var upload model.Upload
+	err := row.Scan(
+		&upload.ID, &upload.UserID, &upload.CourseID, &upload.Kind, &upload.Title, &upload.Filename, &upload.Filetype,
+		&upload.Length, &upload.Offset, &upload.FileURL, &upload.MaterialID, &upload.ExpiresAt, &upload.CompletedAt,
+		&upload.CreatedAt,
+	)
+	return upload, err
+}
+
+// CreateUpload starts a new upload with nothing received yet
+func (r *UploadRepository) CreateUpload(upload *model.Upload) error {
+	query := `
+		INSERT INTO uploads (user_id, course_id, kind, title, filename, filetype, upload_length, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + uploadColumns
+	created, err := scanUpload(r.DB.QueryRow(query, upload.UserID, upload.CourseID, upload.Kind, upload.Title,
+		upload.Filename, upload.Filetype, upload.Length, upload.ExpiresAt))
+	if err != nil {
+		log.Printf("Error creating upload: %v", err)
+		return err
+	}
+	*upload = created
+	return nil
+}
+
+// GetUpload retrieves an upload started by userID, or nil when there is none
+func (r *UploadRepository) GetUpload(uploadID, userID string) (*model.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE id = $1 AND user_id = $2`
+	upload, err := scanUpload(r.DB.QueryRow(query, uploadID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// SetUploadOffset records how many bytes of an upload were received and pushes back its expiry
+func (r *UploadRepository) SetUploadOffset(uploadID string, offset int64, expiresAt time.Time) error {
+	query := `UPDATE uploads SET upload_offset = $1, expires_at = $2 WHERE id = $3 AND completed_at IS NULL`
+	if _, err := r.DB.Exec(query, offset, expiresAt, uploadID); err != nil {
+		log.Printf("Error updating upload offset: %v", err)
+		return err
+	}
+	return nil
+}
+
+// CompleteUpload records the file a completed upload was attached as, and the material created from it if any
+func (r *UploadRepository) CompleteUpload(uploadID, fileURL string, materialID *string) error {
+	query := `UPDATE uploads SET file_url = $1, material_id = $2, completed_at = NOW() WHERE id = $3`
+	if _, err := r.DB.Exec(query, fileURL, materialID, uploadID); err != nil {
+		log.Printf("Error completing upload: %v", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteUpload removes an upload record
+func (r *UploadRepository) DeleteUpload(uploadID string) error {
+	if _, err := r.DB.Exec(`DELETE FROM uploads WHERE id = $1`, uploadID); err != nil {
+		log.Printf("Error deleting upload: %v", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteExpiredUploads removes the uploads past their expiry and returns the IDs of the incomplete ones,
+// whose partial files are left to remove
+func (r *UploadRepository) DeleteExpiredUploads() ([]string, error) {
+	rows, err := r.DB.Query(`DELETE FROM uploads WHERE expires_at < NOW() RETURNING id, completed_at IS NULL`)
+	if err != nil {
+		log.Printf("Error deleting expired uploads: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incomplete []string
+	for rows.Next() {
+		var id string
+		var isIncomplete bool
+		if err := rows.Scan(&id, &isIncomplete); err != nil {
+			return nil, err
+		}
+		if isIncomplete {
+			incomplete = append(incomplete, id)
+		}
+	}
+	return incomplete, rows.Err()
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestDeleteExpiredUploadsReturnsIncompleteOnes(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewUploadRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM uploads WHERE expires_at < NOW() RETURNING id, completed_at IS NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "incomplete"}).
+			AddRow("upload-1", true).
+			AddRow("upload-2", false).
+			AddRow("upload-3", true))
+
+	incomplete, err := repo.DeleteExpiredUploads()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Completed uploads were moved into the uploads folder, so only the others have partial files to remove
+	if len(incomplete) != 2 || incomplete[0] != "upload-1" || incomplete[1] != "upload-3" {
+		t.Errorf("expected upload-1 and upload-3, got %v", incomplete)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
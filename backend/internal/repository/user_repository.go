@@ -52,26 +52,44 @@ func (r *UserRepository) GetUserByEmail(email string) (*model.User, error) {
 	return &user, nil
 }
 
-// GetUsersByStatus Method
-func (r *UserRepository) GetUsersByStatus(status string) ([]model.User, error) {
-	query := `SELECT id, full_name, email, role, status, created_at, updated_at FROM users WHERE status = $1 ORDER BY created_at ASC`
+// userListSpec describes how user lists are paginated, sorted and filtered
+var userListSpec = listSpec{
+	selectClause:  `SELECT id, full_name, email, role, status, created_at, updated_at`,
+	fromClause:    `FROM users`,
+	idColumn:      "id",
+	createdColumn: "created_at",
+	sortColumns: map[string]sortColumn{
+		"created_at": {expr: "created_at", cast: "timestamptz"},
+		"updated_at": {expr: "updated_at", cast: "timestamptz"},
+		"full_name":  {expr: "full_name", cast: "text"},
+		"email":      {expr: "email", cast: "text"},
+	},
+	defaultSort: "created_at",
+	defaultDesc: false,
+}
 
-	rows, err := r.DB.Query(query, status)
-	if err != nil {
-		return nil, err
+// listUsers runs a paginated user list query with the filters from params
+func (r *UserRepository) listUsers(params model.ListParams) ([]model.User, *model.PageInfo, error) {
+	var q listQuery
+	if params.Role != "" {
+		q.where("role = $%d", params.Role)
+	}
+	if params.Status != "" {
+		q.where("status = $%d", params.Status)
 	}
-	defer rows.Close()
+	q.applyCommonFilters(userListSpec, params)
 
-	var users []model.User
-	for rows.Next() {
+	return queryPage(r.DB, userListSpec, params, q, func(rows *sql.Rows, cursor *pageCursor) (model.User, error) {
 		var user model.User
-		if err := rows.Scan(&user.ID, &user.FullName, &user.Email, &user.Role, &user.Status, &user.CreatedAt, &user.UpdatedAt); err != nil {
-			return nil, err
-		}
-		users = append(users, user)
-	}
+		err := rows.Scan(&user.ID, &user.FullName, &user.Email, &user.Role, &user.Status, &user.CreatedAt, &user.UpdatedAt, &cursor.Value, &cursor.ID)
+		return user, err
+	})
+}
 
-	return users, nil
+// GetUsersByStatus Method
+func (r *UserRepository) GetUsersByStatus(status string, params model.ListParams) ([]model.User, *model.PageInfo, error) {
+	params.Status = status
+	return r.listUsers(params)
 }
 
 // UpdateUserStatus Method
@@ -127,24 +145,8 @@ func (r *UserRepository) GetPendingUserCount() (int, error) {
 }
 
 // GetAllUsers Method
-func (r *UserRepository) GetAllUsers() ([]model.User, error) {
-	query := `SELECT id, full_name, email, role, status, created_at, updated_at FROM users ORDER BY created_at ASC`
-
-	rows, err := r.DB.Query(query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var users []model.User
-	for rows.Next() {
-		var user model.User
-		if err := rows.Scan(&user.ID, &user.FullName, &user.Email, &user.Role, &user.Status, &user.CreatedAt, &user.UpdatedAt); err != nil {
-			return nil, err
-		}
-		users = append(users, user)
-		}
-	return users, nil
+func (r *UserRepository) GetAllUsers(params model.ListParams) ([]model.User, *model.PageInfo, error) {
+	return r.listUsers(params)
 }
 
 // UpdateUser Method
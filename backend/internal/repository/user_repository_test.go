@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"regexp"
 	"testing"
 	"time"
@@ -138,4 +139,116 @@ func TestUpdateUserStatus(t *testing.T) {
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestGetAllUsersWithCursor(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	// Request the second page of students, one user per page, newest first
+	cursor := encodeCursor(pageCursor{Sort: "created_at", Desc: true, Value: "2025-01-02 00:00:00+00", ID: "6f1c2a9e-0000-4000-8000-000000000002"})
+	params := model.ListParams{Limit: 1, Cursor: cursor, SortBy: "created_at", SortDesc: true, Role: "student"}
+
+	// SQL queries that are expected to be executed
+	expectedCountSQL := regexp.QuoteMeta(`SELECT COUNT(*) FROM users WHERE role = $1`)
+	expectedSQL := regexp.QuoteMeta(`SELECT id, full_name, email, role, status, created_at, updated_at, (created_at)::text, id::text FROM users WHERE role = $1 AND (created_at, id) < ($2::timestamptz, $3::uuid) ORDER BY created_at DESC, id DESC LIMIT 2`)
+
+	// Two rows are returned, so another page exists after this one
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "role", "status", "created_at", "updated_at", "created_at", "id"}).
+		AddRow("6f1c2a9e-0000-4000-8000-000000000003", "Third User", "third@example.com", "student", "active", time.Now(), time.Now(), "2025-01-01 00:00:00+00", "6f1c2a9e-0000-4000-8000-000000000003").
+		AddRow("6f1c2a9e-0000-4000-8000-000000000004", "Fourth User", "fourth@example.com", "student", "active", time.Now(), time.Now(), "2024-12-31 00:00:00+00", "6f1c2a9e-0000-4000-8000-000000000004")
+
+	// Set expectations in the mock
+	mock.ExpectQuery(expectedCountSQL).WithArgs("student").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectQuery(expectedSQL).WithArgs("student", "2025-01-02 00:00:00+00", "6f1c2a9e-0000-4000-8000-000000000002").WillReturnRows(rows)
+
+	// Run function to be tested
+	users, pageInfo, err := repo.GetAllUsers(params)
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != "6f1c2a9e-0000-4000-8000-000000000003" {
+		t.Errorf("expected only user-3 on the page, but got %+v", users)
+	}
+	if pageInfo.Total != 4 {
+		t.Errorf("expected total 4, but got %d", pageInfo.Total)
+	}
+	if pageInfo.NextCursor != encodeCursor(pageCursor{Sort: "created_at", Desc: true, Value: "2025-01-01 00:00:00+00", ID: "6f1c2a9e-0000-4000-8000-000000000003"}) {
+		t.Errorf("expected next cursor to point at user-3, but got %s", pageInfo.NextCursor)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetAllUsersWithoutLimit(t *testing.T) {
+	// Setup mock database
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	// A limit of 0 returns every user, for callers that read the response as the complete list
+	expectedSQL := regexp.QuoteMeta(`SELECT id, full_name, email, role, status, created_at, updated_at, (created_at)::text, id::text FROM users ORDER BY created_at ASC, id ASC`) + "$"
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "role", "status", "created_at", "updated_at", "created_at", "id"}).
+		AddRow("6f1c2a9e-0000-4000-8000-000000000001", "First User", "first@example.com", "student", "active", time.Now(), time.Now(), "2025-01-02 00:00:00+00", "6f1c2a9e-0000-4000-8000-000000000001").
+		AddRow("6f1c2a9e-0000-4000-8000-000000000002", "Second User", "second@example.com", "student", "active", time.Now(), time.Now(), "2025-01-01 00:00:00+00", "6f1c2a9e-0000-4000-8000-000000000002")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(expectedSQL).WillReturnRows(rows)
+
+	// Run function to be tested
+	users, pageInfo, err := repo.GetAllUsers(model.ListParams{})
+
+	// Check the result (Assert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 2 || pageInfo.NextCursor != "" {
+		t.Errorf("expected both users and no next cursor, but got %+v and %q", users, pageInfo.NextCursor)
+	}
+
+	// Ensure all expectations are met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetAllUsersRejectsBadCursors(t *testing.T) {
+	// Setup mock database
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	id := "6f1c2a9e-0000-4000-8000-000000000002"
+
+	// No query may run for a cursor Postgres could not cast
+	cursors := map[string]string{
+		"issued for another sort":      encodeCursor(pageCursor{Sort: "full_name", Desc: true, Value: "Ana", ID: id}),
+		"issued for another direction": encodeCursor(pageCursor{Sort: "created_at", Value: "2025-01-02 00:00:00+00", ID: id}),
+		"not a timestamp":              encodeCursor(pageCursor{Sort: "created_at", Desc: true, Value: "yesterday", ID: id}),
+		"not a uuid":                   encodeCursor(pageCursor{Sort: "created_at", Desc: true, Value: "2025-01-02 00:00:00+00", ID: "user-2"}),
+		"not base64":                   "%%%",
+	}
+	for name, cursor := range cursors {
+		params := model.ListParams{Limit: 1, Cursor: cursor, SortBy: "created_at", SortDesc: true}
+		if _, _, err := repo.GetAllUsers(params); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: expected ErrInvalidCursor, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package storage manages files saved under the publicly served uploads folder.
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is the folder served under /uploads/
+const Dir = "uploads"
+
+// PartialDir holds resumable uploads while they are incomplete. It is kept apart from Dir
+// so unfinished and unchecked files are never served.
+const PartialDir = "partial-uploads"
+
+// Path converts a /uploads/... URL into a path on disk.
+// It returns false for URLs that do not point into the uploads folder.
+func Path(fileURL string) (string, bool) {
+	if !strings.HasPrefix(fileURL, "/"+Dir+"/") {
+		return "", false
+	}
+	path := filepath.Clean(strings.TrimPrefix(fileURL, "/"))
+	if !strings.HasPrefix(path, Dir+string(filepath.Separator)) {
+		return "", false
+	}
+	return path, true
+}
+
+// URL converts a path on disk inside the uploads folder into its public URL
+func URL(path string) string {
+	return "/" + filepath.ToSlash(path)
+}
+
+// Save writes src to a new file at dir/name under the uploads folder and returns its URL
+func Save(src io.Reader, dir, name string) (string, error) {
+	dir = filepath.Join(Dir, dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name)
+	dst, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return URL(path), nil
+}
+
+// Move moves a file from outside the uploads folder to dir/name under it and returns its URL
+func Move(path, dir, name string) (string, error) {
+	dir = filepath.Join(Dir, dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	dstPath := filepath.Join(dir, name)
+	if err := os.Rename(path, dstPath); err != nil {
+		return "", err
+	}
+	return URL(dstPath), nil
+}
+
+// Copy copies a file served from /uploads next to the original under a new name
+// and returns the URL of the copy. URLs outside /uploads are returned unchanged.
+func Copy(fileURL, newName string) (string, error) {
+	srcPath, ok := Path(fileURL)
+	if !ok {
+		return fileURL, nil
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dstPath := filepath.Join(filepath.Dir(srcPath), newName+filepath.Ext(srcPath))
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(dstPath)
+		return "", fmt.Errorf("copy %s: %w", srcPath, err)
+	}
+
+	return URL(dstPath), nil
+}
+
+// Remove deletes uploaded files, typically ones created by a request that failed later on
+func Remove(fileURLs ...string) {
+	for _, fileURL := range fileURLs {
+		if path, ok := Path(fileURL); ok {
+			os.Remove(path)
+		}
+	}
+}
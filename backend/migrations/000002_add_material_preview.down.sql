@@ -0,0 +1 @@
+ALTER TABLE learning_materials DROP COLUMN IF EXISTS is_preview;
@@ -0,0 +1,2 @@
+-- free preview flag on learning materials
+ALTER TABLE learning_materials ADD COLUMN is_preview BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS course_staff;
+
+DROP TYPE IF EXISTS staff_role;
@@ -0,0 +1,18 @@
+-- custom types
+CREATE TYPE staff_role AS ENUM ('owner', 'co_instructor', 'ta');
+
+-- course_staff table
+CREATE TABLE course_staff (
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role staff_role NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (course_id, user_id)
+);
+
+-- only one owner per course
+CREATE UNIQUE INDEX course_staff_one_owner ON course_staff (course_id) WHERE role = 'owner';
+
+-- existing instructors become owners of their courses
+INSERT INTO course_staff (course_id, user_id, role)
+SELECT id, instructor_id, 'owner' FROM courses;
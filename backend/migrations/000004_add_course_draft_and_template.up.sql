@@ -0,0 +1,5 @@
+-- draft courses are hidden from the catalog until published
+ALTER TABLE courses ADD COLUMN is_draft BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- templates can be duplicated by any instructor
+ALTER TABLE courses ADD COLUMN is_template BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS course_prerequisites;
+DROP TABLE IF EXISTS material_completions;
+
+ALTER TABLE enrollments DROP COLUMN IF EXISTS completed_at;
+
+DROP TYPE IF EXISTS prerequisite_requirement;
@@ -0,0 +1,23 @@
+-- custom types
+CREATE TYPE prerequisite_requirement AS ENUM ('enrolled', 'completed');
+
+-- a course is completed once every material has been completed
+ALTER TABLE enrollments ADD COLUMN completed_at TIMESTAMPTZ;
+
+-- material_completions table
+CREATE TABLE material_completions (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    material_id UUID NOT NULL REFERENCES learning_materials(id) ON DELETE CASCADE,
+    completed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, material_id)
+);
+
+-- course_prerequisites table
+CREATE TABLE course_prerequisites (
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    prerequisite_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    requirement prerequisite_requirement NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (course_id, prerequisite_id),
+    CHECK (course_id <> prerequisite_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS course_waitlist;
+
+ALTER TABLE courses DROP COLUMN IF EXISTS capacity;
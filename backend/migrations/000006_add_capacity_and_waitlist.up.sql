@@ -0,0 +1,13 @@
+-- optional seat limit, NULL means unlimited
+ALTER TABLE courses ADD COLUMN capacity INTEGER CHECK (capacity > 0);
+
+-- course_waitlist table, students are promoted in position order when a seat frees up
+CREATE TABLE course_waitlist (
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (course_id, user_id),
+    -- deferred so a reorder can swap positions inside one transaction
+    UNIQUE (course_id, position) DEFERRABLE INITIALLY DEFERRED
+);
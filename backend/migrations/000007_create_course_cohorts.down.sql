@@ -0,0 +1,4 @@
+ALTER TABLE course_waitlist DROP COLUMN IF EXISTS cohort_id;
+ALTER TABLE enrollments DROP COLUMN IF EXISTS cohort_id;
+
+DROP TABLE IF EXISTS course_cohorts;
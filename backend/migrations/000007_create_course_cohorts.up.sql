@@ -0,0 +1,19 @@
+-- course_cohorts table, each cohort is one term-based run of a course
+CREATE TABLE course_cohorts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    enrollment_opens_at TIMESTAMPTZ NOT NULL,
+    enrollment_closes_at TIMESTAMPTZ NOT NULL,
+    access_starts_at TIMESTAMPTZ NOT NULL,
+    access_ends_at TIMESTAMPTZ, -- NULL keeps access open after the term
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (enrollment_closes_at > enrollment_opens_at),
+    CHECK (access_ends_at IS NULL OR access_ends_at > access_starts_at)
+);
+
+CREATE INDEX idx_course_cohorts_course_id ON course_cohorts(course_id);
+
+-- NULL cohort means a self-paced enrollment without an access window
+ALTER TABLE enrollments ADD COLUMN cohort_id UUID REFERENCES course_cohorts(id) ON DELETE RESTRICT;
+ALTER TABLE course_waitlist ADD COLUMN cohort_id UUID REFERENCES course_cohorts(id) ON DELETE CASCADE;
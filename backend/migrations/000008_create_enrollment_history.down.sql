@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS enrollment_history;
+
+DROP TYPE IF EXISTS enrollment_action;
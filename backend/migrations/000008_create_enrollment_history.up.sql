@@ -0,0 +1,15 @@
+-- custom types
+CREATE TYPE enrollment_action AS ENUM ('enrolled', 'waitlisted', 'promoted', 'added', 'left', 'removed');
+
+-- enrollment_history table, one row per change to a course's roster or waitlist
+CREATE TABLE enrollment_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    cohort_id UUID REFERENCES course_cohorts(id) ON DELETE SET NULL,
+    action enrollment_action NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for automatic waitlist promotions
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_enrollment_history_course_id ON enrollment_history(course_id, created_at);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS enrollment_codes;
+DROP TABLE IF EXISTS enrollment_requests;
+
+ALTER TABLE courses DROP COLUMN IF EXISTS enrollment_mode;
+
+DROP TYPE IF EXISTS enrollment_request_status;
+DROP TYPE IF EXISTS enrollment_mode;
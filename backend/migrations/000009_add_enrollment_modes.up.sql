@@ -0,0 +1,37 @@
+-- custom types
+CREATE TYPE enrollment_mode AS ENUM ('open', 'approval', 'code');
+CREATE TYPE enrollment_request_status AS ENUM ('pending', 'approved', 'rejected');
+
+-- open courses accept anyone, approval courses take requests, code courses need an access code
+ALTER TABLE courses ADD COLUMN enrollment_mode enrollment_mode NOT NULL DEFAULT 'open';
+
+-- enrollment_requests table
+CREATE TABLE enrollment_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    cohort_id UUID REFERENCES course_cohorts(id) ON DELETE CASCADE,
+    message TEXT,
+    status enrollment_request_status NOT NULL DEFAULT 'pending',
+    decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- a student has at most one open request per course
+CREATE UNIQUE INDEX enrollment_requests_one_pending ON enrollment_requests(course_id, user_id) WHERE status = 'pending';
+
+-- enrollment_codes table
+CREATE TABLE enrollment_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    cohort_id UUID REFERENCES course_cohorts(id) ON DELETE CASCADE, -- students redeeming the code join this cohort
+    max_uses INTEGER CHECK (max_uses > 0), -- NULL means unlimited
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ, -- NULL never expires
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_enrollment_codes_course_id ON enrollment_codes(course_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS orders;
+
+ALTER TABLE courses DROP COLUMN IF EXISTS currency;
+ALTER TABLE courses DROP COLUMN IF EXISTS price_cents;
+
+DROP TYPE IF EXISTS order_status;
@@ -0,0 +1,36 @@
+-- custom types
+CREATE TYPE order_status AS ENUM ('pending', 'paid', 'failed');
+
+-- prices are stored in the currency's minor unit, 0 means the course is free
+ALTER TABLE courses ADD COLUMN price_cents INTEGER NOT NULL DEFAULT 0 CHECK (price_cents >= 0);
+ALTER TABLE courses ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- orders table, a confirmed payment turns an order into an enrollment
+CREATE TABLE orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    cohort_id UUID REFERENCES course_cohorts(id) ON DELETE SET NULL,
+    amount_cents INTEGER NOT NULL CHECK (amount_cents > 0),
+    currency CHAR(3) NOT NULL,
+    status order_status NOT NULL DEFAULT 'pending',
+    provider VARCHAR(32),
+    provider_ref VARCHAR(255),
+    checkout_url TEXT,
+    paid_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_ref)
+);
+
+-- a student has at most one pending order per course, checkout reuses it
+CREATE UNIQUE INDEX orders_one_pending ON orders(user_id, course_id) WHERE status = 'pending';
+
+-- payment_events table, webhook events already processed so retries are ignored
+CREATE TABLE payment_events (
+    provider VARCHAR(32) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, event_id)
+);
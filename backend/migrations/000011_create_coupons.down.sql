@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS coupon_redemptions;
+
+DELETE FROM orders WHERE amount_cents = 0;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_amount_cents_check;
+ALTER TABLE orders ADD CONSTRAINT orders_amount_cents_check CHECK (amount_cents > 0);
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_cents;
+ALTER TABLE orders DROP COLUMN IF EXISTS coupon_id;
+
+DROP TABLE IF EXISTS coupons;
+DROP TYPE IF EXISTS coupon_discount_type;
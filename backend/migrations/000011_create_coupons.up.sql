@@ -0,0 +1,42 @@
+-- custom types
+CREATE TYPE coupon_discount_type AS ENUM ('percent', 'fixed');
+
+-- coupons table
+CREATE TABLE coupons (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(32) NOT NULL UNIQUE, -- stored upper case, matched case-insensitively
+    discount_type coupon_discount_type NOT NULL,
+    discount_value INTEGER NOT NULL CHECK (discount_value > 0), -- percent, or minor units of currency
+    currency CHAR(3), -- required for fixed discounts, which only apply to courses in that currency
+    course_id UUID REFERENCES courses(id) ON DELETE CASCADE, -- NULL applies to every paid course
+    max_uses INTEGER CHECK (max_uses > 0), -- NULL means unlimited
+    per_user_limit INTEGER CHECK (per_user_limit > 0), -- NULL means unlimited
+    uses INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (discount_type <> 'percent' OR discount_value <= 100),
+    CHECK (discount_type <> 'fixed' OR currency IS NOT NULL),
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX idx_coupons_course_id ON coupons(course_id);
+
+-- orders remember the coupon applied at checkout, a full discount makes the amount 0
+ALTER TABLE orders ADD COLUMN coupon_id UUID REFERENCES coupons(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN discount_cents INTEGER NOT NULL DEFAULT 0 CHECK (discount_cents >= 0);
+ALTER TABLE orders DROP CONSTRAINT orders_amount_cents_check;
+ALTER TABLE orders ADD CONSTRAINT orders_amount_cents_check CHECK (amount_cents >= 0);
+
+-- coupon_redemptions table, one row per paid order that used a coupon
+CREATE TABLE coupon_redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    coupon_id UUID NOT NULL REFERENCES coupons(id) ON DELETE RESTRICT,
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    discount_cents INTEGER NOT NULL,
+    redeemed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_coupon_redemptions_coupon_user ON coupon_redemptions(coupon_id, user_id);
@@ -0,0 +1,4 @@
+ALTER TABLE courses DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE courses DROP COLUMN IF EXISTS rating_average;
+
+DROP TABLE IF EXISTS course_reviews;
@@ -0,0 +1,20 @@
+-- course_reviews table, one review per student and course
+CREATE TABLE course_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT NOT NULL DEFAULT '',
+    reply TEXT, -- the course staff's public reply
+    replied_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    replied_at TIMESTAMPTZ,
+    is_hidden BOOLEAN NOT NULL DEFAULT FALSE, -- hidden by an admin, left out of listings and the aggregate
+    hidden_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (course_id, user_id)
+);
+
+-- aggregate of the visible reviews, kept up to date whenever a review changes
+ALTER TABLE courses ADD COLUMN rating_average NUMERIC(3, 2) NOT NULL DEFAULT 0;
+ALTER TABLE courses ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- course_announcements table
+CREATE TABLE course_announcements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    is_pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    emailed_at TIMESTAMPTZ, -- when the announcement was last sent to enrolled students by email
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_course_announcements_course_id ON course_announcements(course_id);
+
+-- announcement_reads table, the announcements each student has read
+CREATE TABLE announcement_reads (
+    announcement_id UUID NOT NULL REFERENCES course_announcements(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    read_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (announcement_id, user_id)
+);
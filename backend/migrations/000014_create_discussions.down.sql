@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS discussion_posts;
+DROP TABLE IF EXISTS discussion_threads;
@@ -0,0 +1,30 @@
+-- discussion_threads table, scoped to a course or to one of its materials
+CREATE TABLE discussion_threads (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    material_id UUID REFERENCES learning_materials(id) ON DELETE CASCADE, -- NULL for course-wide threads
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    is_pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    is_locked BOOLEAN NOT NULL DEFAULT FALSE, -- locked threads take no new replies from students
+    edited_at TIMESTAMPTZ,
+    last_activity_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_discussion_threads_course_id ON discussion_threads(course_id);
+CREATE INDEX idx_discussion_threads_material_id ON discussion_threads(material_id);
+
+-- discussion_posts table, replies nest under the thread or another reply
+CREATE TABLE discussion_posts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    thread_id UUID NOT NULL REFERENCES discussion_threads(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES discussion_posts(id) ON DELETE CASCADE, -- NULL for direct replies to the thread
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_discussion_posts_thread_id ON discussion_posts(thread_id);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS post_votes;
+DROP TABLE IF EXISTS thread_votes;
+
+DROP INDEX IF EXISTS idx_discussion_threads_course_kind;
+ALTER TABLE discussion_posts DROP COLUMN IF EXISTS vote_count;
+ALTER TABLE discussion_threads
+    DROP COLUMN IF EXISTS vote_count,
+    DROP COLUMN IF EXISTS accepted_post_id,
+    DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,25 @@
+-- Questions are threads of the Q&A area, answered by replies and upvoted by participants
+ALTER TABLE discussion_threads
+    ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'discussion' CHECK (kind IN ('discussion', 'question')),
+    ADD COLUMN accepted_post_id UUID REFERENCES discussion_posts(id) ON DELETE SET NULL,
+    ADD COLUMN vote_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE discussion_posts ADD COLUMN vote_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_discussion_threads_course_kind ON discussion_threads(course_id, kind);
+
+-- thread_votes table, one upvote per user and question
+CREATE TABLE thread_votes (
+    thread_id UUID NOT NULL REFERENCES discussion_threads(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (thread_id, user_id)
+);
+
+-- post_votes table, one upvote per user and answer
+CREATE TABLE post_votes (
+    post_id UUID NOT NULL REFERENCES discussion_posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_id, user_id)
+);
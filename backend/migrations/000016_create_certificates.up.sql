@@ -0,0 +1,15 @@
+-- certificates table, issued once per student and completed course
+CREATE TABLE certificates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    serial VARCHAR(32) NOT NULL UNIQUE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    course_id UUID REFERENCES courses(id) ON DELETE SET NULL,
+    student_name VARCHAR(255) NOT NULL, -- copied from users.full_name when issued
+    course_title VARCHAR(255) NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ,
+    revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoke_reason TEXT,
+    UNIQUE (user_id, course_id)
+);
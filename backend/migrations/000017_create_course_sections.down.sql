@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_learning_materials_section_id;
+ALTER TABLE learning_materials DROP COLUMN IF EXISTS section_id;
+
+DROP TABLE IF EXISTS course_sections;
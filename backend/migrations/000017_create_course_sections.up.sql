@@ -0,0 +1,16 @@
+-- course_sections table, titled and ordered groups of materials nested at most one level
+CREATE TABLE course_sections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES course_sections(id), -- NULL for top-level sections, a section with subsections cannot be deleted
+    title VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL, -- order among the sections sharing the same parent
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_course_sections_course_id ON course_sections(course_id);
+
+-- materials outside any section keep a NULL section_id
+ALTER TABLE learning_materials ADD COLUMN section_id UUID REFERENCES course_sections(id) ON DELETE SET NULL;
+CREATE INDEX idx_learning_materials_section_id ON learning_materials(section_id);
@@ -0,0 +1,2 @@
+ALTER TABLE learning_materials DROP CONSTRAINT IF EXISTS learning_materials_course_id_position_key;
+ALTER TABLE learning_materials DROP CONSTRAINT IF EXISTS learning_materials_position_check;
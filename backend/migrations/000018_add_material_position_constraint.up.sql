@@ -0,0 +1,12 @@
+-- close the gaps left by deleted materials and break ties by creation time
+UPDATE learning_materials m SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY position, created_at, id) AS position
+    FROM learning_materials
+) ordered
+WHERE m.id = ordered.id AND m.position <> ordered.position;
+
+ALTER TABLE learning_materials ADD CONSTRAINT learning_materials_position_check CHECK (position > 0);
+-- deferred so a reorder can swap positions inside one transaction
+ALTER TABLE learning_materials ADD CONSTRAINT learning_materials_course_id_position_key
+    UNIQUE (course_id, position) DEFERRABLE INITIALLY DEFERRED;
@@ -0,0 +1,6 @@
+ALTER TABLE learning_materials
+    DROP CONSTRAINT IF EXISTS learning_materials_release_self_check,
+    DROP CONSTRAINT IF EXISTS learning_materials_release_check,
+    DROP COLUMN IF EXISTS release_after_material_id,
+    DROP COLUMN IF EXISTS release_after_days,
+    DROP COLUMN IF EXISTS release_at;
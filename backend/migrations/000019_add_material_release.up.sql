@@ -0,0 +1,8 @@
+-- drip release, a material with no rule is available as soon as the student enrolls
+ALTER TABLE learning_materials
+    ADD COLUMN release_at TIMESTAMPTZ, -- released at a fixed date
+    ADD COLUMN release_after_days INTEGER CHECK (release_after_days >= 0), -- released this many days after the student enrolls
+    ADD COLUMN release_after_material_id UUID REFERENCES learning_materials(id) ON DELETE SET NULL, -- released once the student completes that material
+    ADD CONSTRAINT learning_materials_release_check
+        CHECK (num_nonnulls(release_at, release_after_days, release_after_material_id) <= 1),
+    ADD CONSTRAINT learning_materials_release_self_check CHECK (release_after_material_id <> id);
@@ -0,0 +1,3 @@
+ALTER TABLE learning_materials
+    DROP COLUMN IF EXISTS file_size_bytes,
+    DROP COLUMN IF EXISTS duration_seconds;
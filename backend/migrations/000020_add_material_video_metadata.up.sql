@@ -0,0 +1,4 @@
+-- metadata recorded for uploaded video files, NULL for other materials and linked videos
+ALTER TABLE learning_materials
+    ADD COLUMN duration_seconds INTEGER CHECK (duration_seconds >= 0),
+    ADD COLUMN file_size_bytes BIGINT CHECK (file_size_bytes >= 0);
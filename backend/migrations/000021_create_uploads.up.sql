@@ -0,0 +1,20 @@
+-- uploads table, resumable tus uploads that become a course cover or a new material once complete
+CREATE TABLE uploads (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('cover', 'pdf', 'video')),
+    title VARCHAR(255) NOT NULL DEFAULT '', -- title of the new material, empty for covers
+    filename VARCHAR(255) NOT NULL,
+    filetype VARCHAR(255) NOT NULL DEFAULT '', -- MIME type declared by the client
+    upload_length BIGINT NOT NULL CHECK (upload_length > 0),
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    file_url VARCHAR(255), -- set once the completed upload is attached
+    material_id UUID REFERENCES learning_materials(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL, -- pushed back by every chunk, abandoned uploads are removed after it
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (upload_offset BETWEEN 0 AND upload_length)
+);
+
+CREATE INDEX idx_uploads_expires_at ON uploads(expires_at);
@@ -0,0 +1,2 @@
+ALTER TABLE learning_materials
+    DROP COLUMN IF EXISTS text_html;
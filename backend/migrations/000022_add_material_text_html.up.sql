@@ -0,0 +1,3 @@
+-- sanitized HTML rendered from the Markdown in text_content, filled in by the server on write
+ALTER TABLE learning_materials
+    ADD COLUMN text_html TEXT;